@@ -0,0 +1,169 @@
+// =============================================================================
+// FILE: internal/api/apitest/server.go
+// PURPOSE: Fake OF API for tests. An httptest server that answers paginated
+//          API paths with recorded JSON fixtures, keyed by path and cursor,
+//          and media paths with raw bytes. Points the endpoint builders at
+//          itself through OF_BASE_URL; "{{base}}" in a fixture is replaced
+//          with the server's URL so media links resolve to it.
+// =============================================================================
+
+package apitest
+
+import (
+	"bytes"
+	"embed"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fixtures holds the recorded API responses.
+//
+//go:embed testdata/*.json
+var fixtures embed.FS
+
+// cursorParams are the query parameters that carry a pagination cursor:
+// afterPublishTime for timeline-style areas, id for messages, offset for
+// offset-paged areas.
+var cursorParams = []string{"afterPublishTime", "id", "offset"}
+
+// ---------------------------------------------------------------------------
+// Server
+// ---------------------------------------------------------------------------
+
+// Server is a fake OF API. Unregistered paths answer 404.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	pages    map[string][]byte
+	files    map[string]http.HandlerFunc
+	requests []string
+}
+
+// NewServer starts a fake API and points the API endpoints at it for the
+// duration of the test. Request rate limiting is disabled.
+//
+// Parameters:
+//   - t: The test; the server is closed when it ends.
+//
+// Returns:
+//   - The running Server.
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+	s := &Server{
+		pages: make(map[string][]byte),
+		files: make(map[string]http.HandlerFunc),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+	t.Setenv("OF_BASE_URL", s.URL)
+	t.Setenv("OF_RATE_LIMIT_ENABLED", "false")
+	return s
+}
+
+// Page answers requests for apiPath whose cursor equals cursor ("" for the
+// first page) with the named fixture, "{{base}}" replaced by the server URL.
+//
+// Parameters:
+//   - t: The test, failed if the fixture does not exist.
+//   - apiPath: The endpoint path, e.g. "/api2/v2/users/1/posts".
+//   - cursor: The cursor query value as the client sends it.
+//   - fixture: The fixture file name under testdata.
+func (s *Server) Page(t testing.TB, apiPath, cursor, fixture string) {
+	t.Helper()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pages[apiPath+"?"+cursor] = bytes.ReplaceAll(Fixture(t, fixture), []byte("{{base}}"), []byte(s.URL))
+}
+
+// File answers requests for filePath with body.
+//
+// Parameters:
+//   - filePath: The media path, e.g. "/files/1.jpg".
+//   - body: The file content.
+//
+// Returns:
+//   - The file's full URL.
+func (s *Server) File(filePath string, body []byte) string {
+	return s.Handle(filePath, func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, path.Base(filePath), time.Time{}, bytes.NewReader(body))
+	})
+}
+
+// Handle answers requests for filePath with a custom handler, e.g. one that
+// stalls to simulate a slow download.
+//
+// Parameters:
+//   - filePath: The media path.
+//   - h: The handler.
+//
+// Returns:
+//   - The path's full URL.
+func (s *Server) Handle(filePath string, h http.HandlerFunc) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.files[filePath] = h
+	return s.URL + filePath
+}
+
+// Requests returns the request URIs received so far, in order.
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+// serve dispatches a request to its file handler or page fixture.
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests = append(s.requests, r.URL.RequestURI())
+	file := s.files[r.URL.Path]
+	page, ok := s.pages[r.URL.Path+"?"+cursorOf(r)]
+	s.mu.Unlock()
+
+	switch {
+	case file != nil:
+		file(w, r)
+	case ok:
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(page)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// cursorOf returns the request's pagination cursor, or "".
+func cursorOf(r *http.Request) string {
+	q := r.URL.Query()
+	for _, p := range cursorParams {
+		if v := q.Get(p); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// ---------------------------------------------------------------------------
+// Fixtures
+// ---------------------------------------------------------------------------
+
+// Fixture returns the content of a recorded response.
+//
+// Parameters:
+//   - t: The test, failed if the fixture does not exist.
+//   - name: The fixture file name under testdata.
+//
+// Returns:
+//   - The fixture bytes.
+func Fixture(t testing.TB, name string) []byte {
+	t.Helper()
+	data, err := fixtures.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatalf("fixture %s: %v", name, err)
+	}
+	return data
+}
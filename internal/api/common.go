@@ -11,6 +11,7 @@ import (
 	"fmt"
 
 	"gofscraper/internal/model"
+	"gofscraper/internal/utils"
 )
 
 // ---------------------------------------------------------------------------
@@ -80,6 +81,7 @@ func parsePost(raw map[string]any, apiType string, modelID int64) model.Post {
 	}
 	if created, ok := raw["postedAt"].(string); ok {
 		p.CreatedAt = created
+		p.PostedAt = created
 	}
 	if created, ok := raw["createdAt"].(string); ok {
 		if p.CreatedAt == "" {
@@ -87,6 +89,36 @@ func parsePost(raw map[string]any, apiType string, modelID int64) model.Post {
 		}
 	}
 
+	if pinned, ok := raw["isPinned"].(bool); ok {
+		p.Pinned = pinned
+	}
+	if opened, ok := raw["isOpened"].(bool); ok {
+		p.Opened = opened
+	}
+	if fav, ok := raw["isFavorite"].(bool); ok {
+		p.Favorited = fav
+	}
+	if mass, ok := raw["isFromQueue"].(bool); ok {
+		p.Mass = mass
+	}
+	if _, ok := raw["streamId"].(float64); ok {
+		p.Stream = true
+	}
+	if from, ok := raw["fromUser"].(map[string]any); ok {
+		if id, ok := from["id"].(float64); ok {
+			p.FromUser = int64(id)
+		}
+	}
+
+	// Purchased and labelled content carries its real origin in responseType.
+	p.RawResponseType = apiType
+	if rt, ok := raw["responseType"].(string); ok && rt != "" {
+		if apiType == "purchased" || apiType == "labels" {
+			p.RawResponseType = rt
+		}
+	}
+	p.ResponseType = p.DeriveResponseType()
+
 	// Parse media list.
 	if mediaList, ok := raw["media"].([]any); ok {
 		for _, mediaItem := range mediaList {
@@ -116,7 +148,13 @@ func parseMedia(raw map[string]any, postID int64) *model.Media {
 		m.CreatedAt = created
 	}
 
-	// Extract source URL (full > source > preview).
+	// Media without an explicit canView flag (stories, highlights) is viewable.
+	m.CanView = true
+	if canView, ok := raw["canView"].(bool); ok {
+		m.CanView = canView
+	}
+
+	// Extract source URL (full > source > files.full).
 	if src, ok := raw["full"].(string); ok && src != "" {
 		m.RawURL = src
 	} else if src, ok := raw["source"].(map[string]any); ok {
@@ -124,6 +162,27 @@ func parseMedia(raw map[string]any, postID int64) *model.Media {
 			m.RawURL = srcURL
 		}
 	}
+	if files, ok := raw["files"].(map[string]any); ok {
+		if full, ok := files["full"].(map[string]any); ok {
+			if m.RawURL == "" {
+				if srcURL, ok := full["url"].(string); ok {
+					m.RawURL = srcURL
+				}
+			}
+			if size, ok := full["size"].(float64); ok {
+				m.Size = size
+			}
+		}
+	}
+	if dur, ok := raw["duration"].(float64); ok && dur > 0 {
+		m.Duration = fmt.Sprintf("%.0f", dur)
+	}
+
+	// Keep every rendition so a quality can be chosen before download.
+	m.Variants = parseVariants(raw, m.RawURL, m.Size)
+	if len(m.Variants) > 0 {
+		m.SelectedQuality = model.QualitySource
+	}
 
 	// Check if DRM protected.
 	if files, ok := raw["files"].(map[string]any); ok {
@@ -139,6 +198,87 @@ func parseMedia(raw map[string]any, postID int64) *model.Media {
 	return m
 }
 
+// parseVariants collects a media item's renditions: the source file and
+// each entry of "videoSources" (keyed by height, valued by a URL or an
+// object with url and size). Returns nil when there is no source URL.
+func parseVariants(raw map[string]any, sourceURL string, sourceSize float64) []model.MediaVariant {
+	if sourceURL == "" {
+		return nil
+	}
+	variants := []model.MediaVariant{{Quality: model.QualitySource, URL: sourceURL, Size: sourceSize}}
+
+	sources, _ := raw["videoSources"].(map[string]any)
+	for key, val := range sources {
+		v := model.MediaVariant{Quality: model.NormalizeQuality(key)}
+		switch src := val.(type) {
+		case string:
+			v.URL = src
+		case map[string]any:
+			v.URL, _ = src["url"].(string)
+			v.Size, _ = src["size"].(float64)
+		}
+		if v.URL == "" || v.Quality == model.QualitySource {
+			continue
+		}
+		variants = append(variants, v)
+	}
+	model.SortVariants(variants)
+	return variants
+}
+
+// ---------------------------------------------------------------------------
+// Pagination
+// ---------------------------------------------------------------------------
+
+// PostPage is a single page of a cursor-paginated post listing.
+type PostPage struct {
+	// Posts holds the posts on this page, in API order.
+	Posts []model.Post
+
+	// HasMore reports whether the API advertised a further page.
+	HasMore bool
+
+	// Next is the cursor to request the following page with: an
+	// afterPublishTime timestamp for timeline-style areas, or the oldest
+	// message ID for messages.
+	Next float64
+}
+
+// parsePostPage extracts a PostPage from a paginated API response.
+//
+// Parameters:
+//   - raw: The raw JSON response as a map.
+//   - apiType: The API source type (e.g. "timeline", "messages").
+//   - modelID: The model's numeric ID.
+//
+// Returns:
+//   - The parsed page with its next cursor.
+func parsePostPage(raw map[string]any, apiType string, modelID int64) PostPage {
+	page := PostPage{
+		Posts: parsePostList(raw, apiType, modelID),
+	}
+	if more, ok := raw["hasMore"].(bool); ok {
+		page.HasMore = more
+	}
+	if len(page.Posts) == 0 {
+		return page
+	}
+
+	last := page.Posts[len(page.Posts)-1]
+	if apiType == "messages" {
+		page.Next = float64(last.ID)
+		return page
+	}
+
+	page.Next = extractPaginationAfter(raw)
+	if page.Next == 0 {
+		if t, err := utils.ParseFlexibleDate(last.Date()); err == nil {
+			page.Next = float64(t.UnixMicro()) / 1e6
+		}
+	}
+	return page
+}
+
 // extractPaginationAfter reads the "tailMarker" or "afterPublishTime"
 // cursor from a paginated response.
 //
@@ -9,6 +9,7 @@ package api
 
 import (
 	"fmt"
+	"strconv"
 
 	"gofscraper/internal/config/env"
 )
@@ -49,7 +50,7 @@ func TimelineURL(modelID int64) string {
 //   - modelID: The model's numeric ID.
 //   - after: The pagination cursor timestamp.
 func TimelineNextURL(modelID int64, after float64) string {
-	return base() + fmt.Sprintf(env.TimelineNextEP(), modelID, after)
+	return base() + fmt.Sprintf(env.TimelineNextEP(), modelID, formatCursor(after))
 }
 
 // PinnedURL returns the pinned posts endpoint.
@@ -57,7 +58,7 @@ func TimelineNextURL(modelID int64, after float64) string {
 // Parameters:
 //   - modelID: The model's numeric ID.
 func PinnedURL(modelID int64) string {
-	return base() + fmt.Sprintf(env.TimelinePinnedEP(), modelID)
+	return base() + fmt.Sprintf(env.TimelinePinnedEP(), modelID, "0")
 }
 
 // ArchivedURL returns the archived posts endpoint.
@@ -70,7 +71,7 @@ func ArchivedURL(modelID int64) string {
 
 // ArchivedNextURL returns the paginated archived endpoint.
 func ArchivedNextURL(modelID int64, after float64) string {
-	return base() + fmt.Sprintf(env.ArchivedNextEP(), modelID, after)
+	return base() + fmt.Sprintf(env.ArchivedNextEP(), modelID, formatCursor(after))
 }
 
 // StreamsURL returns the streams endpoint.
@@ -80,7 +81,7 @@ func StreamsURL(modelID int64) string {
 
 // StreamsNextURL returns the paginated streams endpoint.
 func StreamsNextURL(modelID int64, after float64) string {
-	return base() + fmt.Sprintf(env.StreamsNextEP(), modelID, after)
+	return base() + fmt.Sprintf(env.StreamsNextEP(), modelID, formatCursor(after))
 }
 
 // MessagesURL returns the messages endpoint.
@@ -88,21 +89,37 @@ func MessagesURL(modelID int64) string {
 	return base() + fmt.Sprintf(env.MessagesEP(), modelID)
 }
 
-// MessagesNextURL returns the paginated messages endpoint.
-func MessagesNextURL(modelID int64, after string) string {
-	return base() + fmt.Sprintf(env.MessagesNextEP(), modelID, after)
+// MessagesNextURL returns the paginated messages endpoint. Messages are
+// returned newest first, so the cursor is the ID of the oldest message seen.
+func MessagesNextURL(modelID int64, messageID int64) string {
+	return base() + fmt.Sprintf(env.MessagesNextEP(), modelID, messageID)
 }
 
-// HighlightsURL returns the highlights + stories endpoint.
-func HighlightsURL(modelID int64) string {
-	return base() + fmt.Sprintf(env.HighlightsWithStoriesEP(), modelID)
+// HighlightsURL returns the paginated highlights list endpoint.
+func HighlightsURL(modelID int64, offset int) string {
+	return base() + fmt.Sprintf(env.HighlightsWithStoriesEP(), modelID, offset)
 }
 
-// StoryURL returns a specific story endpoint.
+// StoriesURL returns the active stories endpoint.
+func StoriesURL(modelID int64) string {
+	return base() + fmt.Sprintf(env.HighlightsWithAStoryEP(), modelID)
+}
+
+// StoryURL returns a specific highlight endpoint, which embeds its stories.
 func StoryURL(storyID int64) string {
 	return base() + fmt.Sprintf(env.StoryEP(), storyID)
 }
 
+// SingleStoryURL returns the endpoint for one story by its ID.
+func SingleStoryURL(storyID int64) string {
+	return base() + fmt.Sprintf(env.StoriesSpecificEP(), storyID)
+}
+
+// PostURL returns the endpoint for a single timeline post.
+func PostURL(postID int64) string {
+	return base() + fmt.Sprintf(env.IndividualTimelineEP(), postID)
+}
+
 // LabelsURL returns the labels list endpoint.
 func LabelsURL(modelID int64) string {
 	return base() + fmt.Sprintf(env.LabelsEP(), modelID)
@@ -113,14 +130,14 @@ func LabelledPostsURL(modelID, labelID int64) string {
 	return base() + fmt.Sprintf(env.LabelledPostsEP(), modelID, labelID)
 }
 
-// SubscriptionsURL returns the subscriptions list endpoint.
-func SubscriptionsURL(subType string) string {
-	return base() + fmt.Sprintf(env.SubscriptionsEP(), subType)
+// SubscriptionsURL returns the offset-paginated subscriptions list endpoint.
+func SubscriptionsURL(subType string, offset int) string {
+	return base() + fmt.Sprintf(env.SubscriptionsEP(), offset, subType)
 }
 
-// PurchasedURL returns the purchased content endpoint.
-func PurchasedURL(modelID int64) string {
-	return base() + fmt.Sprintf(env.PurchasedContentEP(), modelID)
+// PurchasedURL returns the offset-paginated purchased content endpoint.
+func PurchasedURL(modelID int64, offset int) string {
+	return base() + fmt.Sprintf(env.PurchasedContentEP(), offset, modelID)
 }
 
 // FavoriteURL returns the like/unlike endpoint.
@@ -132,3 +149,9 @@ func FavoriteURL(postID int64, action string) string {
 func InitURL() string {
 	return base() + env.InitEP()
 }
+
+// formatCursor renders an afterPublishTime cursor the way the API emits it
+// (seconds with microsecond precision).
+func formatCursor(after float64) string {
+	return strconv.FormatFloat(after, 'f', 6, 64)
+}
@@ -20,9 +20,9 @@ import (
 // Stories
 // ---------------------------------------------------------------------------
 
-// GetStories fetches story posts for a model.
+// GetStories fetches the model's currently active stories.
 func (c *Client) GetStories(ctx context.Context, modelID int64) ([]model.Post, error) {
-	url := HighlightsURL(modelID)
+	url := StoriesURL(modelID)
 	req := gohttp.NewRequest(url)
 	resp, err := gohttp.DoWithRetry(ctx, c.session, req, gohttp.DefaultRetryConfig())
 	if err != nil {
@@ -52,35 +52,85 @@ func (c *Client) GetStories(ctx context.Context, modelID int64) ([]model.Post, e
 // Highlights
 // ---------------------------------------------------------------------------
 
-// GetHighlights fetches highlight posts for a model.
+// GetHighlights fetches highlight posts for a model. The highlight list is
+// paginated by offset and only carries highlight IDs, so each highlight is
+// then fetched individually for its embedded stories.
 func (c *Client) GetHighlights(ctx context.Context, modelID int64) ([]model.Post, error) {
-	url := HighlightsURL(modelID)
+	var highlightIDs []int64
+	offset := 0
+	for {
+		url := HighlightsURL(modelID, offset)
+		req := gohttp.NewRequest(url)
+		resp, err := gohttp.DoWithRetry(ctx, c.session, req, gohttp.DefaultRetryConfig())
+		if err != nil {
+			return nil, fmt.Errorf("GetHighlights: %w", err)
+		}
+		if !resp.IsOK() {
+			resp.Close()
+			return nil, fmt.Errorf("GetHighlights: status %d", resp.StatusCode)
+		}
+
+		var raw map[string]any
+		if err := resp.JSON(&raw); err != nil {
+			return nil, fmt.Errorf("GetHighlights: decode error: %w", err)
+		}
+
+		list, _ := raw["list"].([]any)
+		for _, item := range list {
+			if hlMap, ok := item.(map[string]any); ok {
+				if id, ok := hlMap["id"].(float64); ok {
+					highlightIDs = append(highlightIDs, int64(id))
+				}
+			}
+		}
+
+		more, _ := raw["hasMore"].(bool)
+		if !more || len(list) == 0 {
+			break
+		}
+		offset += len(list)
+	}
+
+	var posts []model.Post
+	for _, id := range highlightIDs {
+		stories, err := c.getHighlightStories(ctx, id, modelID)
+		if err != nil {
+			return posts, err
+		}
+		posts = append(posts, stories...)
+	}
+	return posts, nil
+}
+
+// getHighlightStories fetches the stories embedded in a single highlight.
+// A zero modelID is taken from the highlight's userId.
+func (c *Client) getHighlightStories(ctx context.Context, highlightID, modelID int64) ([]model.Post, error) {
+	url := StoryURL(highlightID)
 	req := gohttp.NewRequest(url)
 	resp, err := gohttp.DoWithRetry(ctx, c.session, req, gohttp.DefaultRetryConfig())
 	if err != nil {
-		return nil, fmt.Errorf("GetHighlights: %w", err)
+		return nil, fmt.Errorf("GetHighlights: highlight %d: %w", highlightID, err)
 	}
 	if !resp.IsOK() {
 		resp.Close()
-		return nil, fmt.Errorf("GetHighlights: status %d", resp.StatusCode)
+		return nil, fmt.Errorf("GetHighlights: highlight %d: status %d", highlightID, resp.StatusCode)
 	}
 
-	var raw []any
+	var raw map[string]any
 	if err := resp.JSON(&raw); err != nil {
-		return nil, fmt.Errorf("GetHighlights: decode error: %w", err)
+		return nil, fmt.Errorf("GetHighlights: highlight %d: decode error: %w", highlightID, err)
+	}
+
+	if id, ok := raw["userId"].(float64); ok && modelID == 0 {
+		modelID = int64(id)
 	}
 
 	var posts []model.Post
-	for _, item := range raw {
-		if hlMap, ok := item.(map[string]any); ok {
-			// Highlights contain nested stories.
-			if stories, ok := hlMap["stories"].([]any); ok {
-				for _, s := range stories {
-					if storyMap, ok := s.(map[string]any); ok {
-						post := parsePost(storyMap, "highlights", modelID)
-						posts = append(posts, post)
-					}
-				}
+	if stories, ok := raw["stories"].([]any); ok {
+		for _, s := range stories {
+			if storyMap, ok := s.(map[string]any); ok {
+				post := parsePost(storyMap, "highlights", modelID)
+				posts = append(posts, post)
 			}
 		}
 	}
@@ -118,6 +168,13 @@ func (c *Client) GetPinned(ctx context.Context, modelID int64) ([]model.Post, er
 
 // GetArchived fetches archived posts for a model.
 func (c *Client) GetArchived(ctx context.Context, modelID int64, after float64) ([]model.Post, error) {
+	page, err := c.GetArchivedPage(ctx, modelID, after)
+	return page.Posts, err
+}
+
+// GetArchivedPage fetches a single page of archived posts along with the
+// cursor for the following page.
+func (c *Client) GetArchivedPage(ctx context.Context, modelID int64, after float64) (PostPage, error) {
 	var url string
 	if after > 0 {
 		url = ArchivedNextURL(modelID, after)
@@ -128,19 +185,19 @@ func (c *Client) GetArchived(ctx context.Context, modelID int64, after float64)
 	req := gohttp.NewRequest(url)
 	resp, err := gohttp.DoWithRetry(ctx, c.session, req, gohttp.DefaultRetryConfig())
 	if err != nil {
-		return nil, fmt.Errorf("GetArchived: %w", err)
+		return PostPage{}, fmt.Errorf("GetArchived: %w", err)
 	}
 	if !resp.IsOK() {
 		resp.Close()
-		return nil, fmt.Errorf("GetArchived: status %d", resp.StatusCode)
+		return PostPage{}, fmt.Errorf("GetArchived: status %d", resp.StatusCode)
 	}
 
 	var raw map[string]any
 	if err := resp.JSON(&raw); err != nil {
-		return nil, fmt.Errorf("GetArchived: decode error: %w", err)
+		return PostPage{}, fmt.Errorf("GetArchived: decode error: %w", err)
 	}
 
-	return parsePostList(raw, "archived", modelID), nil
+	return parsePostPage(raw, "archived", modelID), nil
 }
 
 // ---------------------------------------------------------------------------
@@ -149,6 +206,13 @@ func (c *Client) GetArchived(ctx context.Context, modelID int64, after float64)
 
 // GetStreams fetches stream posts for a model.
 func (c *Client) GetStreams(ctx context.Context, modelID int64, after float64) ([]model.Post, error) {
+	page, err := c.GetStreamsPage(ctx, modelID, after)
+	return page.Posts, err
+}
+
+// GetStreamsPage fetches a single page of stream posts along with the
+// cursor for the following page.
+func (c *Client) GetStreamsPage(ctx context.Context, modelID int64, after float64) (PostPage, error) {
 	var url string
 	if after > 0 {
 		url = StreamsNextURL(modelID, after)
@@ -159,19 +223,19 @@ func (c *Client) GetStreams(ctx context.Context, modelID int64, after float64) (
 	req := gohttp.NewRequest(url)
 	resp, err := gohttp.DoWithRetry(ctx, c.session, req, gohttp.DefaultRetryConfig())
 	if err != nil {
-		return nil, fmt.Errorf("GetStreams: %w", err)
+		return PostPage{}, fmt.Errorf("GetStreams: %w", err)
 	}
 	if !resp.IsOK() {
 		resp.Close()
-		return nil, fmt.Errorf("GetStreams: status %d", resp.StatusCode)
+		return PostPage{}, fmt.Errorf("GetStreams: status %d", resp.StatusCode)
 	}
 
 	var raw map[string]any
 	if err := resp.JSON(&raw); err != nil {
-		return nil, fmt.Errorf("GetStreams: decode error: %w", err)
+		return PostPage{}, fmt.Errorf("GetStreams: decode error: %w", err)
 	}
 
-	return parsePostList(raw, "streams", modelID), nil
+	return parsePostPage(raw, "streams", modelID), nil
 }
 
 // ---------------------------------------------------------------------------
@@ -210,56 +274,87 @@ func (c *Client) GetLabels(ctx context.Context, modelID int64) ([]model.Post, er
 // Purchased
 // ---------------------------------------------------------------------------
 
-// GetPurchased fetches purchased content for a model.
+// GetPurchased fetches purchased content for a model, following the
+// offset pagination until the API reports no further pages.
 func (c *Client) GetPurchased(ctx context.Context, modelID int64) ([]model.Post, error) {
-	url := PurchasedURL(modelID)
-	req := gohttp.NewRequest(url)
-	resp, err := gohttp.DoWithRetry(ctx, c.session, req, gohttp.DefaultRetryConfig())
-	if err != nil {
-		return nil, fmt.Errorf("GetPurchased: %w", err)
-	}
-	if !resp.IsOK() {
-		resp.Close()
-		return nil, fmt.Errorf("GetPurchased: status %d", resp.StatusCode)
-	}
+	var posts []model.Post
+	offset := 0
+	for {
+		url := PurchasedURL(modelID, offset)
+		req := gohttp.NewRequest(url)
+		resp, err := gohttp.DoWithRetry(ctx, c.session, req, gohttp.DefaultRetryConfig())
+		if err != nil {
+			return posts, fmt.Errorf("GetPurchased: %w", err)
+		}
+		if !resp.IsOK() {
+			resp.Close()
+			return posts, fmt.Errorf("GetPurchased: status %d", resp.StatusCode)
+		}
 
-	var raw map[string]any
-	if err := resp.JSON(&raw); err != nil {
-		return nil, fmt.Errorf("GetPurchased: decode error: %w", err)
-	}
+		var raw map[string]any
+		if err := resp.JSON(&raw); err != nil {
+			return posts, fmt.Errorf("GetPurchased: decode error: %w", err)
+		}
 
-	return parsePostList(raw, "purchased", modelID), nil
+		page := parsePostPage(raw, "purchased", modelID)
+		posts = append(posts, page.Posts...)
+		if !page.HasMore || len(page.Posts) == 0 {
+			return posts, nil
+		}
+		offset += len(page.Posts)
+	}
 }
 
 // ---------------------------------------------------------------------------
 // Subscriptions
 // ---------------------------------------------------------------------------
 
-// GetSubscriptions fetches the user's active or expired subscriptions.
+// GetSubscriptions fetches the user's subscriptions of the given type
+// ("all", "active", or "expired"), following offset pagination.
 func (c *Client) GetSubscriptions(ctx context.Context, subType string) ([]model.User, error) {
-	url := SubscriptionsURL(subType)
-	req := gohttp.NewRequest(url)
-	resp, err := gohttp.DoWithRetry(ctx, c.session, req, gohttp.DefaultRetryConfig())
-	if err != nil {
-		return nil, fmt.Errorf("GetSubscriptions: %w", err)
-	}
-	if !resp.IsOK() {
-		resp.Close()
-		return nil, fmt.Errorf("GetSubscriptions: status %d", resp.StatusCode)
-	}
-
-	var raw []any
-	if err := resp.JSON(&raw); err != nil {
-		return nil, fmt.Errorf("GetSubscriptions: decode error: %w", err)
+	if subType == "" {
+		subType = "all"
 	}
 
 	var users []model.User
-	for _, item := range raw {
-		if userMap, ok := item.(map[string]any); ok {
-			users = append(users, parseUserFromMap(userMap))
+	offset := 0
+	for {
+		req := gohttp.NewRequest(SubscriptionsURL(subType, offset))
+		resp, err := gohttp.DoWithRetry(ctx, c.session, req, gohttp.DefaultRetryConfig())
+		if err != nil {
+			return users, fmt.Errorf("GetSubscriptions: %w", err)
+		}
+		if !resp.IsOK() {
+			resp.Close()
+			return users, fmt.Errorf("GetSubscriptions: status %d", resp.StatusCode)
+		}
+
+		// format=infinite wraps the page as {list, hasMore}; older responses
+		// are a bare array.
+		var raw any
+		if err := resp.JSON(&raw); err != nil {
+			return users, fmt.Errorf("GetSubscriptions: decode error: %w", err)
+		}
+		var items []any
+		hasMore := false
+		switch v := raw.(type) {
+		case []any:
+			items = v
+		case map[string]any:
+			items, _ = v["list"].([]any)
+			hasMore, _ = v["hasMore"].(bool)
+		}
+
+		for _, item := range items {
+			if userMap, ok := item.(map[string]any); ok {
+				users = append(users, parseUserFromMap(userMap))
+			}
+		}
+		if !hasMore || len(items) == 0 {
+			return users, nil
 		}
+		offset += len(items)
 	}
-	return users, nil
 }
 
 // ---------------------------------------------------------------------------
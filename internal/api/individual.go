@@ -0,0 +1,135 @@
+// =============================================================================
+// FILE: internal/api/individual.go
+// PURPOSE: Single-item API handlers. Fetches one post, message, story, or
+//          highlight by ID for manual (URL-based) downloads.
+//          Ports Python data/api/individual.py.
+// =============================================================================
+
+package api
+
+import (
+	"context"
+	"fmt"
+
+	gohttp "gofscraper/internal/http"
+	"gofscraper/internal/model"
+)
+
+// ---------------------------------------------------------------------------
+// Single post
+// ---------------------------------------------------------------------------
+
+// GetPost fetches a single timeline post by ID. The owning model's ID is
+// taken from the post's author.
+//
+// Parameters:
+//   - ctx: Context for cancellation.
+//   - postID: The post ID.
+//
+// Returns:
+//   - The post, and any error.
+func (c *Client) GetPost(ctx context.Context, postID int64) (model.Post, error) {
+	raw, err := c.getMap(ctx, PostURL(postID))
+	if err != nil {
+		return model.Post{}, fmt.Errorf("GetPost: %w", err)
+	}
+
+	var modelID int64
+	if author, ok := raw["author"].(map[string]any); ok {
+		if id, ok := author["id"].(float64); ok {
+			modelID = int64(id)
+		}
+	}
+	return parsePost(raw, "timeline", modelID), nil
+}
+
+// ---------------------------------------------------------------------------
+// Single message
+// ---------------------------------------------------------------------------
+
+// GetMessage fetches a single message from a model's chat. The chat API has
+// no single-message endpoint, so the page starting at messageID is fetched
+// and searched for it.
+//
+// Parameters:
+//   - ctx: Context for cancellation.
+//   - modelID: The model's numeric ID (the chat ID).
+//   - messageID: The message ID.
+//
+// Returns:
+//   - The message as a post, and any error.
+func (c *Client) GetMessage(ctx context.Context, modelID, messageID int64) (model.Post, error) {
+	// The cursor is exclusive, so start just above the wanted ID.
+	page, err := c.GetMessagesPage(ctx, modelID, float64(messageID+1))
+	if err != nil {
+		return model.Post{}, err
+	}
+	for _, p := range page.Posts {
+		if p.ID == messageID {
+			return p, nil
+		}
+	}
+	return model.Post{}, fmt.Errorf("GetMessage: message %d not found in chat %d", messageID, modelID)
+}
+
+// ---------------------------------------------------------------------------
+// Single story / highlight
+// ---------------------------------------------------------------------------
+
+// GetStory fetches a single story by ID. The owning model's ID is taken from
+// the story's userId.
+//
+// Parameters:
+//   - ctx: Context for cancellation.
+//   - storyID: The story ID.
+//
+// Returns:
+//   - The story as a post, and any error.
+func (c *Client) GetStory(ctx context.Context, storyID int64) (model.Post, error) {
+	raw, err := c.getMap(ctx, SingleStoryURL(storyID))
+	if err != nil {
+		return model.Post{}, fmt.Errorf("GetStory: %w", err)
+	}
+
+	var modelID int64
+	if id, ok := raw["userId"].(float64); ok {
+		modelID = int64(id)
+	}
+	return parsePost(raw, "stories", modelID), nil
+}
+
+// GetHighlight fetches the stories of a single highlight. The owning
+// model's ID is taken from the highlight's userId.
+//
+// Parameters:
+//   - ctx: Context for cancellation.
+//   - highlightID: The highlight ID.
+//
+// Returns:
+//   - The highlight's stories as posts, and any error.
+func (c *Client) GetHighlight(ctx context.Context, highlightID int64) ([]model.Post, error) {
+	return c.getHighlightStories(ctx, highlightID, 0)
+}
+
+// ---------------------------------------------------------------------------
+// Helpers
+// ---------------------------------------------------------------------------
+
+// getMap GETs url with retries and decodes a JSON object response.
+func (c *Client) getMap(ctx context.Context, url string) (map[string]any, error) {
+	req := gohttp.NewRequest(url)
+	resp, err := gohttp.DoWithRetry(ctx, c.session, req, gohttp.DefaultRetryConfig())
+	if err != nil {
+		return nil, err
+	}
+	if !resp.IsOK() {
+		resp.Close()
+		return nil, fmt.Errorf("status %d", resp.StatusCode)
+	}
+
+	var raw map[string]any
+	if err := resp.JSON(&raw); err != nil {
+		return nil, fmt.Errorf("decode error: %w", err)
+	}
+	return raw, nil
+}
@@ -59,6 +59,9 @@ func parseUserFromMap(raw map[string]any) model.User {
 	if name, ok := raw["name"].(string); ok {
 		u.Name = name
 	}
+	if username, ok := raw["username"].(string); ok && username != "" {
+		u.Name = username
+	}
 
 	return u
 }
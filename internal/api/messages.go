@@ -24,9 +24,24 @@ import (
 // Returns:
 //   - Slice of posts (messages), and any error.
 func (c *Client) GetMessages(ctx context.Context, modelID int64, after float64) ([]model.Post, error) {
+	page, err := c.GetMessagesPage(ctx, modelID, after)
+	return page.Posts, err
+}
+
+// GetMessagesPage fetches a single page of messages, newest first, along
+// with the cursor (oldest message ID) for the following page.
+//
+// Parameters:
+//   - ctx: Context.
+//   - modelID: The model's numeric ID.
+//   - after: Message ID cursor (0 for first page).
+//
+// Returns:
+//   - The page of messages, and any error.
+func (c *Client) GetMessagesPage(ctx context.Context, modelID int64, after float64) (PostPage, error) {
 	var url string
 	if after > 0 {
-		url = MessagesNextURL(modelID, fmt.Sprintf("%.0f", after))
+		url = MessagesNextURL(modelID, int64(after))
 	} else {
 		url = MessagesURL(modelID)
 	}
@@ -34,18 +49,18 @@ func (c *Client) GetMessages(ctx context.Context, modelID int64, after float64)
 	req := gohttp.NewRequest(url)
 	resp, err := gohttp.DoWithRetry(ctx, c.session, req, gohttp.DefaultRetryConfig())
 	if err != nil {
-		return nil, fmt.Errorf("GetMessages: %w", err)
+		return PostPage{}, fmt.Errorf("GetMessages: %w", err)
 	}
 
 	if !resp.IsOK() {
 		resp.Close()
-		return nil, fmt.Errorf("GetMessages: status %d", resp.StatusCode)
+		return PostPage{}, fmt.Errorf("GetMessages: status %d", resp.StatusCode)
 	}
 
 	var raw map[string]any
 	if err := resp.JSON(&raw); err != nil {
-		return nil, fmt.Errorf("GetMessages: decode error: %w", err)
+		return PostPage{}, fmt.Errorf("GetMessages: decode error: %w", err)
 	}
 
-	return parsePostList(raw, "messages", modelID), nil
+	return parsePostPage(raw, "messages", modelID), nil
 }
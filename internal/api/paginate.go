@@ -0,0 +1,101 @@
+// =============================================================================
+// FILE: internal/api/paginate.go
+// PURPOSE: Cursor pagination driver. Walks a paginated endpoint page by page
+//          until the API reports no further pages, the cursor stops
+//          advancing, or posts fall behind a date floor. Ports Python
+//          data/api/common/after.py and the per-area scrape loops.
+// =============================================================================
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"gofscraper/internal/model"
+	"gofscraper/internal/utils"
+)
+
+// ---------------------------------------------------------------------------
+// Types
+// ---------------------------------------------------------------------------
+
+// PageFunc fetches a single page of posts starting at the given cursor.
+type PageFunc func(ctx context.Context, after float64) (PostPage, error)
+
+// PaginateOptions controls how Paginate walks an endpoint.
+type PaginateOptions struct {
+	// Start is the cursor for the first request (0 for the first page).
+	Start float64
+
+	// Floor drops posts dated before it. For descending endpoints it also
+	// stops pagination once a page reaches past the floor. Zero disables it.
+	Floor time.Time
+
+	// Descending is true for endpoints that return newest content first
+	// (messages). Ascending endpoints should seed Start from the floor.
+	Descending bool
+}
+
+// ---------------------------------------------------------------------------
+// Paginate
+// ---------------------------------------------------------------------------
+
+// Paginate repeatedly calls fetch until the endpoint is exhausted or the
+// date floor is reached, returning every collected post in API order.
+//
+// Parameters:
+//   - ctx: Context for cancellation.
+//   - fetch: Fetches one page for a cursor.
+//   - opts: Start cursor, date floor, and ordering.
+//
+// Returns:
+//   - All collected posts, and any error. Posts gathered before an error are
+//     still returned.
+func Paginate(ctx context.Context, fetch PageFunc, opts PaginateOptions) ([]model.Post, error) {
+	var all []model.Post
+	seen := make(map[int64]bool)
+	cursor := opts.Start
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return all, err
+		}
+
+		page, err := fetch(ctx, cursor)
+		if err != nil {
+			return all, err
+		}
+
+		reachedFloor := false
+		for _, post := range page.Posts {
+			if seen[post.ID] {
+				continue
+			}
+			if !opts.Floor.IsZero() && postBefore(post, opts.Floor) {
+				reachedFloor = true
+				continue
+			}
+			seen[post.ID] = true
+			all = append(all, post)
+		}
+
+		if !page.HasMore || len(page.Posts) == 0 || page.Next == 0 || page.Next == cursor {
+			return all, nil
+		}
+		if opts.Descending && reachedFloor {
+			return all, nil
+		}
+		cursor = page.Next
+	}
+}
+
+// postBefore reports whether the post's date is earlier than floor. Posts
+// with no parseable date are never treated as before the floor.
+func postBefore(post model.Post, floor time.Time) bool {
+	t, err := utils.ParseFlexibleDate(post.Date())
+	if err != nil {
+		return false
+	}
+	return t.Before(floor)
+}
@@ -0,0 +1,181 @@
+// =============================================================================
+// FILE: internal/api/paginate_test.go
+// PURPOSE: Tests for the cursor pagination driver against the fake API:
+//          cursor advance, hasMore=false, a stalled cursor with repeated
+//          posts, and the date floor in ascending and descending order.
+// =============================================================================
+
+package api_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"gofscraper/internal/api"
+	"gofscraper/internal/api/apitest"
+	gohttp "gofscraper/internal/http"
+	"gofscraper/internal/model"
+)
+
+// page is one fixture registration on the fake API.
+type page struct {
+	path, cursor, fixture string
+}
+
+func TestPaginate(t *testing.T) {
+	floor := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	msgFloor := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		pages    []page
+		messages bool
+		modelID  int64
+		opts     api.PaginateOptions
+		wantIDs  []int64
+		wantReqs []string
+	}{
+		{
+			name: "cursor advances until hasMore is false",
+			pages: []page{
+				{"/api2/v2/users/1/posts", "", "timeline_1_page1.json"},
+				{"/api2/v2/users/1/posts", "1704189600.000000", "timeline_1_page2.json"},
+			},
+			modelID: 1,
+			wantIDs: []int64{101, 102, 103, 104},
+			wantReqs: []string{
+				"/api2/v2/users/1/posts?limit=100&order=publish_date_asc&skip_users=all&skip_users_dups=1&pinned=0&format=infinite",
+				"/api2/v2/users/1/posts?limit=100&order=publish_date_asc&skip_users=all&skip_users_dups=1&afterPublishTime=1704189600.000000&pinned=0&format=infinite",
+			},
+		},
+		{
+			name: "hasMore false ignores the tail marker",
+			pages: []page{
+				{"/api2/v2/users/5/posts", "", "timeline_1_page2.json"},
+			},
+			modelID: 5,
+			wantIDs: []int64{103, 104},
+			wantReqs: []string{
+				"/api2/v2/users/5/posts?limit=100&order=publish_date_asc&skip_users=all&skip_users_dups=1&pinned=0&format=infinite",
+			},
+		},
+		{
+			name: "stalled cursor stops and repeated posts are kept once",
+			pages: []page{
+				{"/api2/v2/users/2/posts", "", "timeline_2_page1.json"},
+				{"/api2/v2/users/2/posts", "1704189600.000000", "timeline_2_stalled.json"},
+			},
+			modelID: 2,
+			wantIDs: []int64{201, 202, 203},
+			wantReqs: []string{
+				"/api2/v2/users/2/posts?limit=100&order=publish_date_asc&skip_users=all&skip_users_dups=1&pinned=0&format=infinite",
+				"/api2/v2/users/2/posts?limit=100&order=publish_date_asc&skip_users=all&skip_users_dups=1&afterPublishTime=1704189600.000000&pinned=0&format=infinite",
+			},
+		},
+		{
+			name: "ascending floor drops older posts and keeps paging",
+			pages: []page{
+				{"/api2/v2/users/3/posts", "1704153600.000000", "timeline_3_floor.json"},
+				{"/api2/v2/users/3/posts", "1704189600.000000", "timeline_1_page2.json"},
+			},
+			modelID: 3,
+			opts:    api.PaginateOptions{Start: float64(floor.Unix()), Floor: floor},
+			wantIDs: []int64{302, 103, 104},
+			wantReqs: []string{
+				"/api2/v2/users/3/posts?limit=100&order=publish_date_asc&skip_users=all&skip_users_dups=1&afterPublishTime=1704153600.000000&pinned=0&format=infinite",
+				"/api2/v2/users/3/posts?limit=100&order=publish_date_asc&skip_users=all&skip_users_dups=1&afterPublishTime=1704189600.000000&pinned=0&format=infinite",
+			},
+		},
+		{
+			name: "descending floor stops at the first page past it",
+			pages: []page{
+				{"/api2/v2/chats/4/messages", "", "messages_page1.json"},
+				{"/api2/v2/chats/4/messages", "404", "messages_page2.json"},
+				{"/api2/v2/chats/4/messages", "402", "messages_page3.json"},
+			},
+			messages: true,
+			modelID:  4,
+			opts:     api.PaginateOptions{Floor: msgFloor, Descending: true},
+			wantIDs:  []int64{405, 404, 403},
+			wantReqs: []string{
+				"/api2/v2/chats/4/messages?limit=100&order=desc&skip_users=all&skip_users_dups=1",
+				"/api2/v2/chats/4/messages?limit=100&id=404&order=desc&skip_users=all&skip_users_dups=1",
+			},
+		},
+		{
+			name: "descending without a floor pages to the end",
+			pages: []page{
+				{"/api2/v2/chats/4/messages", "", "messages_page1.json"},
+				{"/api2/v2/chats/4/messages", "404", "messages_page2.json"},
+				{"/api2/v2/chats/4/messages", "402", "messages_page3.json"},
+			},
+			messages: true,
+			modelID:  4,
+			opts:     api.PaginateOptions{Descending: true},
+			wantIDs:  []int64{405, 404, 403, 402, 401},
+			wantReqs: []string{
+				"/api2/v2/chats/4/messages?limit=100&order=desc&skip_users=all&skip_users_dups=1",
+				"/api2/v2/chats/4/messages?limit=100&id=404&order=desc&skip_users=all&skip_users_dups=1",
+				"/api2/v2/chats/4/messages?limit=100&id=402&order=desc&skip_users=all&skip_users_dups=1",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := apitest.NewServer(t)
+			for _, p := range tt.pages {
+				srv.Page(t, p.path, p.cursor, p.fixture)
+			}
+			client := api.NewClient(gohttp.New(nil))
+
+			fetch := func(ctx context.Context, after float64) (api.PostPage, error) {
+				return client.GetTimelinePage(ctx, tt.modelID, after)
+			}
+			if tt.messages {
+				fetch = func(ctx context.Context, after float64) (api.PostPage, error) {
+					return client.GetMessagesPage(ctx, tt.modelID, after)
+				}
+			}
+
+			posts, err := api.Paginate(context.Background(), fetch, tt.opts)
+			if err != nil {
+				t.Fatalf("Paginate: %v", err)
+			}
+			if got := postIDs(posts); !slices.Equal(got, tt.wantIDs) {
+				t.Errorf("post IDs = %v, want %v", got, tt.wantIDs)
+			}
+			if got := srv.Requests(); !slices.Equal(got, tt.wantReqs) {
+				t.Errorf("requests = %q, want %q", got, tt.wantReqs)
+			}
+		})
+	}
+}
+
+func TestPaginateReturnsPostsBeforeError(t *testing.T) {
+	srv := apitest.NewServer(t)
+	// The second page is not registered, so it answers 404.
+	srv.Page(t, "/api2/v2/users/1/posts", "", "timeline_1_page1.json")
+	client := api.NewClient(gohttp.New(nil))
+
+	posts, err := api.Paginate(context.Background(), func(ctx context.Context, after float64) (api.PostPage, error) {
+		return client.GetTimelinePage(ctx, 1, after)
+	}, api.PaginateOptions{})
+	if err == nil {
+		t.Fatal("Paginate: want error for the missing page")
+	}
+	if got, want := postIDs(posts), []int64{101, 102}; !slices.Equal(got, want) {
+		t.Errorf("post IDs = %v, want %v", got, want)
+	}
+}
+
+// postIDs returns the IDs of posts in order.
+func postIDs(posts []model.Post) []int64 {
+	ids := make([]int64, len(posts))
+	for i, p := range posts {
+		ids[i] = p.ID
+	}
+	return ids
+}
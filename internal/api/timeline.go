@@ -28,6 +28,21 @@ import (
 // Returns:
 //   - Slice of posts, and any error.
 func (c *Client) GetTimeline(ctx context.Context, modelID int64, after float64) ([]model.Post, error) {
+	page, err := c.GetTimelinePage(ctx, modelID, after)
+	return page.Posts, err
+}
+
+// GetTimelinePage fetches a single page of timeline posts along with the
+// cursor for the following page.
+//
+// Parameters:
+//   - ctx: Context for cancellation.
+//   - modelID: The model's numeric ID.
+//   - after: afterPublishTime cursor (0 for first page).
+//
+// Returns:
+//   - The page of posts, and any error.
+func (c *Client) GetTimelinePage(ctx context.Context, modelID int64, after float64) (PostPage, error) {
 	var url string
 	if after > 0 {
 		url = TimelineNextURL(modelID, after)
@@ -38,18 +53,18 @@ func (c *Client) GetTimeline(ctx context.Context, modelID int64, after float64)
 	req := gohttp.NewRequest(url)
 	resp, err := gohttp.DoWithRetry(ctx, c.session, req, gohttp.DefaultRetryConfig())
 	if err != nil {
-		return nil, fmt.Errorf("GetTimeline: %w", err)
+		return PostPage{}, fmt.Errorf("GetTimeline: %w", err)
 	}
 
 	if !resp.IsOK() {
 		resp.Close()
-		return nil, fmt.Errorf("GetTimeline: status %d", resp.StatusCode)
+		return PostPage{}, fmt.Errorf("GetTimeline: status %d", resp.StatusCode)
 	}
 
 	var raw map[string]any
 	if err := resp.JSON(&raw); err != nil {
-		return nil, fmt.Errorf("GetTimeline: decode error: %w", err)
+		return PostPage{}, fmt.Errorf("GetTimeline: decode error: %w", err)
 	}
 
-	return parsePostList(raw, "timeline", modelID), nil
+	return parsePostPage(raw, "timeline", modelID), nil
 }
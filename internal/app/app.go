@@ -13,12 +13,16 @@ import (
 	"log/slog"
 	"os"
 	"os/signal"
+	"sync/atomic"
 	"syscall"
+	"time"
 
 	"gofscraper/internal/auth"
 	"gofscraper/internal/config"
+	"gofscraper/internal/download"
 	gohttp "gofscraper/internal/http"
 	"gofscraper/internal/logging"
+	"gofscraper/internal/paths"
 )
 
 // ---------------------------------------------------------------------------
@@ -29,9 +33,14 @@ import (
 type App struct {
 	ctx     context.Context
 	cancel  context.CancelFunc
-	cfg     *config.AppConfig
+	cfg     atomic.Pointer[config.AppConfig] // Swapped by SIGHUP reloads
 	session *gohttp.SessionManager
 	logger  *slog.Logger
+
+	bandwidth *download.Bandwidth // Shared by every downloader
+
+	retryFailed bool     // Download only media whose last attempt failed
+	quality     []string // Quality preference from --quality (config when empty)
 }
 
 // New creates a new App instance.
@@ -52,7 +61,7 @@ func (a *App) Init() error {
 	if err := config.Init(""); err != nil {
 		return fmt.Errorf("load config: %w", err)
 	}
-	a.cfg = config.Get()
+	a.cfg.Store(config.Get())
 
 	// Step 2: Initialize logging.
 	if err := logging.Init(&logging.Options{
@@ -72,14 +81,38 @@ func (a *App) Init() error {
 
 	// Step 4: Create HTTP session.
 	a.session = gohttp.New(authData)
+	a.bandwidth = download.NewBandwidth(0)
+	a.loadBandwidth()
 
 	// Step 5: Setup signal handling.
 	a.setupSignals()
 
+	// Step 6: Remove stale staged downloads.
+	a.cleanupTempDir()
+
 	return nil
 }
 
-// setupSignals configures graceful shutdown on SIGINT/SIGTERM.
+// cleanupTempDir removes staged downloads older than temp_max_age from the
+// configured temp_dir.
+func (a *App) cleanupTempDir() {
+	dir := config.GetTempDir()
+	if dir == "" {
+		return
+	}
+	maxAge := time.Duration(config.GetTempMaxAge()) * time.Hour
+	removed, err := paths.CleanupTempFiles(dir, maxAge)
+	if err != nil {
+		a.logger.Warn("temp cleanup failed", "dir", dir, "error", err)
+		return
+	}
+	if removed > 0 {
+		a.logger.Info("removed stale temp files", "dir", dir, "count", removed)
+	}
+}
+
+// setupSignals configures graceful shutdown on SIGINT/SIGTERM and config
+// reload on SIGHUP.
 func (a *App) setupSignals() {
 	sigs := make(chan os.Signal, 1)
 	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
@@ -89,6 +122,34 @@ func (a *App) setupSignals() {
 		a.logger.Info("received signal, shutting down", "signal", sig)
 		a.cancel()
 	}()
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	go func() {
+		for {
+			select {
+			case <-hup:
+				a.reloadConfig()
+			case <-a.ctx.Done():
+				signal.Stop(hup)
+				return
+			}
+		}
+	}()
+}
+
+// reloadConfig re-reads the config file and applies its bandwidth limits,
+// dropping any runtime override.
+func (a *App) reloadConfig() {
+	if err := config.Init(config.ConfigPath()); err != nil {
+		a.logger.Error("config reload failed", "error", err)
+		return
+	}
+	a.cfg.Store(config.Get())
+	a.bandwidth.ClearOverride()
+	a.loadBandwidth()
+	a.logger.Info("config reloaded", "download_limit", a.bandwidth.Limit())
 }
 
 // Context returns the app's context.
@@ -101,12 +162,29 @@ func (a *App) Session() *gohttp.SessionManager {
 	return a.session
 }
 
-// Config returns the app config.
+// Config returns the app config. A SIGHUP reload replaces it, so callers
+// should not hold on to the result across long-running work.
 func (a *App) Config() *config.AppConfig {
-	return a.cfg
+	return a.cfg.Load()
 }
 
 // Logger returns the app logger.
 func (a *App) Logger() *slog.Logger {
 	return a.logger
 }
+
+// Bandwidth returns the bandwidth limiter shared by all downloads.
+func (a *App) Bandwidth() *download.Bandwidth {
+	return a.bandwidth
+}
+
+// SetRetryFailed restricts downloads to media whose last recorded attempt
+// failed.
+func (a *App) SetRetryFailed(retry bool) {
+	a.retryFailed = retry
+}
+
+// SetQuality overrides the configured quality preference list.
+func (a *App) SetQuality(prefs []string) {
+	a.quality = prefs
+}
@@ -0,0 +1,61 @@
+// =============================================================================
+// FILE: internal/app/app_test.go
+// PURPOSE: Tests for App lifecycle helpers: a SIGHUP config reload swaps
+//          the config safely while other goroutines read it.
+// =============================================================================
+
+package app
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"gofscraper/internal/config"
+	"gofscraper/internal/download"
+)
+
+func TestReloadConfigWhileReading(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.Init(path); err != nil {
+		t.Fatal(err)
+	}
+
+	a := &App{logger: slog.New(slog.DiscardHandler), bandwidth: download.NewBandwidth(0)}
+	a.cfg.Store(config.Get())
+
+	// Run under -race: readers must not race the reload.
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					if a.Config() == nil {
+						t.Error("Config() = nil during reload")
+						return
+					}
+				}
+			}
+		}()
+	}
+	for range 50 {
+		a.reloadConfig()
+	}
+	close(stop)
+	wg.Wait()
+
+	if a.Config() != config.Get() {
+		t.Error("Config() does not return the reloaded config")
+	}
+}
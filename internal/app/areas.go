@@ -0,0 +1,99 @@
+// =============================================================================
+// FILE: internal/app/areas.go
+// PURPOSE: Content area dispatcher. Maps a content area name to its API
+//          endpoint and walks the endpoint's cursor pagination until it is
+//          exhausted or the date floor is reached. Ports Python
+//          data/posts/post.py process_areas and its per-area helpers.
+// =============================================================================
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"gofscraper/internal/api"
+	"gofscraper/internal/filter"
+	"gofscraper/internal/model"
+)
+
+// ---------------------------------------------------------------------------
+// Area dispatch
+// ---------------------------------------------------------------------------
+
+// FetchAreaPosts retrieves every post for one content area of a model.
+//
+// Timeline-style areas are ordered oldest first, so a non-zero floor seeds
+// the afterPublishTime cursor directly. Messages are ordered newest first and
+// are paged by message ID until a page reaches past the floor.
+//
+// Parameters:
+//   - ctx: Context for cancellation.
+//   - client: The API client.
+//   - area: The content area name (e.g. "timeline", "messages").
+//   - modelID: The model's numeric ID.
+//   - floor: Posts before this date are skipped (zero for no floor).
+//
+// Returns:
+//   - The area's posts, and any error.
+func FetchAreaPosts(ctx context.Context, client *api.Client, area string, modelID int64, floor time.Time) ([]model.Post, error) {
+	ascending := api.PaginateOptions{Floor: floor}
+	if !floor.IsZero() {
+		ascending.Start = float64(floor.Unix())
+	}
+
+	switch strings.ToLower(strings.TrimSpace(area)) {
+	case "timeline":
+		return api.Paginate(ctx, func(ctx context.Context, after float64) (api.PostPage, error) {
+			return client.GetTimelinePage(ctx, modelID, after)
+		}, ascending)
+
+	case "archived":
+		return api.Paginate(ctx, func(ctx context.Context, after float64) (api.PostPage, error) {
+			return client.GetArchivedPage(ctx, modelID, after)
+		}, ascending)
+
+	case "streams":
+		return api.Paginate(ctx, func(ctx context.Context, after float64) (api.PostPage, error) {
+			return client.GetStreamsPage(ctx, modelID, after)
+		}, ascending)
+
+	case "messages":
+		return api.Paginate(ctx, func(ctx context.Context, after float64) (api.PostPage, error) {
+			return client.GetMessagesPage(ctx, modelID, after)
+		}, api.PaginateOptions{Floor: floor, Descending: true})
+
+	case "pinned":
+		posts, err := client.GetPinned(ctx, modelID)
+		return filterFloor(posts, floor), err
+
+	case "stories":
+		posts, err := client.GetStories(ctx, modelID)
+		return filterFloor(posts, floor), err
+
+	case "highlights":
+		posts, err := client.GetHighlights(ctx, modelID)
+		return filterFloor(posts, floor), err
+
+	case "labels":
+		posts, err := client.GetLabels(ctx, modelID)
+		return filterFloor(posts, floor), err
+
+	case "purchased":
+		posts, err := client.GetPurchased(ctx, modelID)
+		return filterFloor(posts, floor), err
+
+	default:
+		return nil, fmt.Errorf("unknown content area: %s", area)
+	}
+}
+
+// filterFloor drops posts dated before floor for the non-paginated areas.
+func filterFloor(posts []model.Post, floor time.Time) []model.Post {
+	if f := filter.ByPostDate(floor, time.Time{}); f != nil {
+		return f(posts)
+	}
+	return posts
+}
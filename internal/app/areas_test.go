@@ -0,0 +1,98 @@
+// =============================================================================
+// FILE: internal/app/areas_test.go
+// PURPOSE: Tests for the content area dispatcher against the fake API: the
+//          floor seeds the timeline cursor and stops message paging.
+// =============================================================================
+
+package app
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"gofscraper/internal/api"
+	"gofscraper/internal/api/apitest"
+	gohttp "gofscraper/internal/http"
+)
+
+func TestFetchAreaPosts(t *testing.T) {
+	tests := []struct {
+		name      string
+		area      string
+		modelID   int64
+		floor     time.Time
+		wantIDs   []int64
+		wantFirst string
+		wantReqs  int
+	}{
+		{
+			name:      "timeline without floor starts at the first page",
+			area:      "timeline",
+			modelID:   1,
+			wantIDs:   []int64{101, 102, 103, 104},
+			wantFirst: "/api2/v2/users/1/posts?limit=100&order=publish_date_asc&skip_users=all&skip_users_dups=1&pinned=0&format=infinite",
+			wantReqs:  2,
+		},
+		{
+			name:      "timeline floor seeds the afterPublishTime cursor",
+			area:      "Timeline",
+			modelID:   3,
+			floor:     time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+			wantIDs:   []int64{302, 103, 104},
+			wantFirst: "/api2/v2/users/3/posts?limit=100&order=publish_date_asc&skip_users=all&skip_users_dups=1&afterPublishTime=1704153600.000000&pinned=0&format=infinite",
+			wantReqs:  2,
+		},
+		{
+			name:      "messages floor stops paging newest first",
+			area:      "messages",
+			modelID:   4,
+			floor:     time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			wantIDs:   []int64{405, 404, 403},
+			wantFirst: "/api2/v2/chats/4/messages?limit=100&order=desc&skip_users=all&skip_users_dups=1",
+			wantReqs:  2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := apitest.NewServer(t)
+			srv.Page(t, "/api2/v2/users/1/posts", "", "timeline_1_page1.json")
+			srv.Page(t, "/api2/v2/users/1/posts", "1704189600.000000", "timeline_1_page2.json")
+			srv.Page(t, "/api2/v2/users/3/posts", "1704153600.000000", "timeline_3_floor.json")
+			srv.Page(t, "/api2/v2/users/3/posts", "1704189600.000000", "timeline_1_page2.json")
+			srv.Page(t, "/api2/v2/chats/4/messages", "", "messages_page1.json")
+			srv.Page(t, "/api2/v2/chats/4/messages", "404", "messages_page2.json")
+			srv.Page(t, "/api2/v2/chats/4/messages", "402", "messages_page3.json")
+			client := api.NewClient(gohttp.New(nil))
+
+			posts, err := FetchAreaPosts(context.Background(), client, tt.area, tt.modelID, tt.floor)
+			if err != nil {
+				t.Fatalf("FetchAreaPosts: %v", err)
+			}
+			ids := make([]int64, len(posts))
+			for i, p := range posts {
+				ids[i] = p.ID
+			}
+			if !slices.Equal(ids, tt.wantIDs) {
+				t.Errorf("post IDs = %v, want %v", ids, tt.wantIDs)
+			}
+			reqs := srv.Requests()
+			if len(reqs) != tt.wantReqs {
+				t.Errorf("got %d requests %q, want %d", len(reqs), reqs, tt.wantReqs)
+			}
+			if len(reqs) > 0 && reqs[0] != tt.wantFirst {
+				t.Errorf("first request = %q, want %q", reqs[0], tt.wantFirst)
+			}
+		})
+	}
+}
+
+func TestFetchAreaPostsUnknownArea(t *testing.T) {
+	_, err := FetchAreaPosts(context.Background(), nil, "bogus", 1, time.Time{})
+	if err == nil || !strings.Contains(err.Error(), "unknown content area") {
+		t.Fatalf("err = %v, want unknown content area", err)
+	}
+}
@@ -1,33 +1,82 @@
 // =============================================================================
 // FILE: internal/app/daemon.go
-// PURPOSE: Daemon mode for running the scraper on a schedule using a ticker.
-//          Manages DaemonConfig with configurable intervals and runs the
-//          scrape loop until the context is cancelled. Ports Python
-//          runner/manager/daemon.py.
+// PURPOSE: Daemon mode for running the scraper on a schedule. Manages
+//          DaemonConfig with an interval or pluggable schedule, persists each
+//          action's last and next run so restarts resume the schedule, and
+//          runs the scrape loop until the context is cancelled or every
+//          action has reached MaxRuns. Ports Python runner/manager/daemon.py.
 // =============================================================================
 
 package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
+
+	"gofscraper/internal/paths"
 )
 
+// ---------------------------------------------------------------------------
+// Schedule
+// ---------------------------------------------------------------------------
+
+// Schedule computes daemon run times.
+type Schedule interface {
+	// NextRun returns when to run next given the previous run's start time
+	// (zero if there was none).
+	NextRun(lastRun time.Time) time.Time
+
+	// String describes the schedule; a change invalidates persisted
+	// next-run times.
+	String() string
+}
+
+// intervalSchedule runs at a fixed interval, starting immediately.
+type intervalSchedule time.Duration
+
+func (d intervalSchedule) NextRun(lastRun time.Time) time.Time {
+	if lastRun.IsZero() {
+		return time.Now()
+	}
+	return lastRun.Add(time.Duration(d))
+}
+
+func (d intervalSchedule) String() string {
+	return "every " + time.Duration(d).String()
+}
+
 // ---------------------------------------------------------------------------
 // DaemonConfig
 // ---------------------------------------------------------------------------
 
 // DaemonConfig holds the configuration for daemon mode operation.
 type DaemonConfig struct {
-	// Interval is the duration between scrape runs.
+	// Interval is the duration between scrape runs. Used when Schedule is nil.
 	Interval time.Duration
 
+	// Schedule computes run times. Overrides Interval when set.
+	Schedule Schedule
+
+	// MaxRuns stops running an action once it has run this many times;
+	// the daemon exits when every action has (0 = unlimited). Runs of an
+	// interrupted daemon with the same MaxRuns count toward it; a changed
+	// limit, or a previous daemon that finished, starts the count over.
+	MaxRuns int
+
+	// StatePath is the file the schedule is persisted to. Empty uses
+	// paths.DaemonStatePath().
+	StatePath string
+
 	// Actions are the actions to perform on each run.
 	Actions []string
 
 	// Areas are the content areas to scrape on each run.
 	Areas []string
+
+	// Run performs one action. Nil dispatches through RunAction.
+	Run func(ctx context.Context, action string) error
 }
 
 // DefaultDaemonConfig returns a DaemonConfig with sensible defaults.
@@ -47,9 +96,12 @@ func DefaultDaemonConfig() DaemonConfig {
 // Returns:
 //   - Error if the configuration is invalid.
 func (dc DaemonConfig) Validate() error {
-	if dc.Interval < 1*time.Minute {
+	if dc.Schedule == nil && dc.Interval < 1*time.Minute {
 		return fmt.Errorf("daemon interval must be at least 1 minute, got %s", dc.Interval)
 	}
+	if dc.MaxRuns < 0 {
+		return fmt.Errorf("daemon max runs must be non-negative, got %d", dc.MaxRuns)
+	}
 	if len(dc.Actions) == 0 {
 		return fmt.Errorf("daemon requires at least one action")
 	}
@@ -59,12 +111,31 @@ func (dc DaemonConfig) Validate() error {
 	return nil
 }
 
+// schedule returns the configured schedule, falling back to Interval.
+func (dc DaemonConfig) schedule() Schedule {
+	if dc.Schedule != nil {
+		return dc.Schedule
+	}
+	return intervalSchedule(dc.Interval)
+}
+
+// statePath returns the configured state file path.
+func (dc DaemonConfig) statePath() string {
+	if dc.StatePath != "" {
+		return dc.StatePath
+	}
+	return paths.DaemonStatePath()
+}
+
 // ---------------------------------------------------------------------------
 // RunDaemon
 // ---------------------------------------------------------------------------
 
-// RunDaemon starts the daemon loop that runs scrape operations at the
-// configured interval. Blocks until the context is cancelled.
+// RunDaemon starts the daemon loop. Each action keeps its own persisted
+// last and next run; on start, actions whose saved next run is still in the
+// future wait for it instead of running immediately. Blocks until the
+// context is cancelled or every action has run MaxRuns times (see
+// DaemonConfig.MaxRuns).
 //
 // Parameters:
 //   - ctx: Context for cancellation (e.g., from signal handler).
@@ -77,46 +148,142 @@ func (a *App) RunDaemon(ctx context.Context, dc DaemonConfig) error {
 		return fmt.Errorf("invalid daemon config: %w", err)
 	}
 
+	sched := dc.schedule()
+	statePath := dc.statePath()
+	state, err := LoadDaemonState(statePath)
+	if err != nil {
+		return err
+	}
+
 	a.logger.Info("daemon mode starting",
-		"interval", dc.Interval,
+		"schedule", sched.String(),
+		"max_runs", dc.MaxRuns,
 		"actions", dc.Actions,
 		"areas", dc.Areas,
+		"state", statePath,
 	)
 
-	// Run immediately on first iteration.
-	if err := a.runDaemonCycle(ctx, dc); err != nil {
-		a.logger.Error("daemon cycle failed", "error", err)
+	if state.startRuns(dc.Actions, dc.MaxRuns) {
+		a.logger.Debug("daemon run counts reset", "max_runs", dc.MaxRuns)
 	}
 
-	ticker := time.NewTicker(dc.Interval)
-	defer ticker.Stop()
+	// Resume saved next runs; recompute them when the schedule changed.
+	for _, action := range dc.Actions {
+		as := state.action(action)
+		if as.NextRun.IsZero() || as.Schedule != sched.String() {
+			as.Schedule = sched.String()
+			as.NextRun = sched.NextRun(as.LastRun)
+		}
+	}
+	a.saveDaemonState(state, statePath)
 
-	for {
-		select {
-		case <-ctx.Done():
-			a.logger.Info("daemon shutting down")
-			return nil
-		case <-ticker.C:
-			a.logger.Info("daemon cycle starting")
-			if err := a.runDaemonCycle(ctx, dc); err != nil {
-				a.logger.Error("daemon cycle failed", "error", err)
-				// Continue running; do not exit on non-fatal errors.
+	for cycle := 1; ; cycle++ {
+		actions := dc.remainingActions(state)
+		if len(actions) == 0 {
+			break
+		}
+		next := earliestRun(state, actions)
+		if wait := time.Until(next); wait > 0 {
+			a.logger.Info(fmt.Sprintf("daemon sleeping for %s until next run", wait.Round(time.Second)),
+				"next_run", next.Format(time.RFC3339),
+			)
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				a.logger.Info("daemon shutting down")
+				return nil
+			case <-timer.C:
 			}
-			a.logger.Info(fmt.Sprintf("daemon sleeping for %s until next run", dc.Interval))
+		}
+
+		a.logger.Info("daemon cycle starting", "cycle", cycle)
+		if err := a.runDaemonCycle(ctx, dc, actions, sched, state, statePath); err != nil {
+			if ctx.Err() != nil {
+				a.logger.Info("daemon shutting down")
+				return nil
+			}
+			a.logger.Error("daemon cycle failed", "error", err)
+			// Continue running; do not exit on non-fatal errors.
 		}
 	}
+
+	a.logger.Info("daemon reached max runs", "max_runs", dc.MaxRuns)
+	return nil
 }
 
-// runDaemonCycle executes a single scrape cycle within the daemon loop.
-func (a *App) runDaemonCycle(ctx context.Context, dc DaemonConfig) error {
-	// Dispatch to the action router for each configured action.
-	for _, action := range dc.Actions {
+// runDaemonCycle runs every given action that is due and records its
+// schedule.
+func (a *App) runDaemonCycle(ctx context.Context, dc DaemonConfig, actions []string, sched Schedule, state *DaemonState, statePath string) error {
+	var errs []error
+	for _, action := range actions {
 		if ctx.Err() != nil {
 			return ctx.Err()
 		}
-		if err := a.RunAction(ctx, action, dc.Areas, nil); err != nil {
-			return fmt.Errorf("daemon action %s: %w", action, err)
+		as := state.action(action)
+		if time.Now().Before(as.NextRun) {
+			continue
 		}
+
+		started := time.Now()
+		err := a.runDaemonAction(ctx, dc, action)
+		if ctx.Err() != nil {
+			// Interrupted runs are retried on restart.
+			return ctx.Err()
+		}
+
+		as.LastRun = started
+		as.NextRun = sched.NextRun(started)
+		as.Runs++
+		as.LastError = ""
+		if err != nil {
+			as.LastError = err.Error()
+			errs = append(errs, fmt.Errorf("daemon action %s: %w", action, err))
+		}
+		a.saveDaemonState(state, statePath)
 	}
-	return nil
+	return errors.Join(errs...)
+}
+
+// runDaemonAction runs one action through dc.Run or the action router.
+func (a *App) runDaemonAction(ctx context.Context, dc DaemonConfig, action string) error {
+	if dc.Run != nil {
+		return dc.Run(ctx, action)
+	}
+	return a.RunAction(ctx, action, dc.Areas, nil)
+}
+
+// saveDaemonState persists the state, logging rather than failing so a
+// read-only config directory does not stop the daemon.
+func (a *App) saveDaemonState(state *DaemonState, path string) {
+	if err := state.Save(path); err != nil {
+		a.logger.Warn("failed to save daemon state", "error", err)
+	}
+}
+
+// remainingActions returns the configured actions whose run count is
+// still below MaxRuns.
+func (dc DaemonConfig) remainingActions(state *DaemonState) []string {
+	if dc.MaxRuns == 0 {
+		return dc.Actions
+	}
+	var actions []string
+	for _, action := range dc.Actions {
+		if state.action(action).Runs < dc.MaxRuns {
+			actions = append(actions, action)
+		}
+	}
+	return actions
+}
+
+// earliestRun returns the soonest next run among the given actions.
+func earliestRun(state *DaemonState, actions []string) time.Time {
+	var next time.Time
+	for _, action := range actions {
+		t := state.action(action).NextRun
+		if next.IsZero() || t.Before(next) {
+			next = t
+		}
+	}
+	return next
 }
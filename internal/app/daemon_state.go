@@ -0,0 +1,130 @@
+// =============================================================================
+// FILE: internal/app/daemon_state.go
+// PURPOSE: Persistent daemon schedule state. Records the last and next run
+//          time of each daemon action in a JSON file so a restarted daemon
+//          resumes its schedule instead of running again immediately.
+// =============================================================================
+
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"gofscraper/internal/paths"
+)
+
+// ---------------------------------------------------------------------------
+// DaemonState
+// ---------------------------------------------------------------------------
+
+// ActionSchedule is the persisted schedule of one daemon action.
+type ActionSchedule struct {
+	// Schedule describes the schedule NextRun was computed from. A
+	// different schedule on restart discards NextRun.
+	Schedule string `json:"schedule"`
+
+	LastRun time.Time `json:"last_run,omitzero"`
+	NextRun time.Time `json:"next_run,omitzero"`
+
+	// Runs counts completed runs toward the state's MaxRuns.
+	Runs      int    `json:"runs"`
+	LastError string `json:"last_error,omitempty"`
+}
+
+// DaemonState holds the persisted schedule of every daemon action.
+type DaemonState struct {
+	// MaxRuns is the limit the actions' run counts were made under. A
+	// different limit on restart starts the counts over.
+	MaxRuns int                        `json:"max_runs,omitempty"`
+	Actions map[string]*ActionSchedule `json:"actions"`
+}
+
+// LoadDaemonState reads the daemon state file. A missing file yields an
+// empty state.
+//
+// Parameters:
+//   - path: Path to the state file.
+//
+// Returns:
+//   - The state, and any read or decode error.
+func LoadDaemonState(path string) (*DaemonState, error) {
+	state := &DaemonState{Actions: make(map[string]*ActionSchedule)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read daemon state: %w", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("decode daemon state %s: %w", path, err)
+	}
+	if state.Actions == nil {
+		state.Actions = make(map[string]*ActionSchedule)
+	}
+	return state, nil
+}
+
+// Save writes the state atomically through a temporary file.
+//
+// Parameters:
+//   - path: Path to the state file.
+//
+// Returns:
+//   - Error if the file cannot be written.
+func (s *DaemonState) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode daemon state: %w", err)
+	}
+	if err := paths.EnsureParentDir(path); err != nil {
+		return fmt.Errorf("create daemon state dir: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("write daemon state: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("replace daemon state: %w", err)
+	}
+	return nil
+}
+
+// startRuns prepares the run counts of actions for a daemon limited to
+// maxRuns runs each. Counts left by an interrupted daemon with the same
+// limit carry over; they start over when the limit changed or when every
+// action had already reached it, i.e. the previous daemon finished.
+//
+// Returns:
+//   - Whether the counts were reset.
+func (s *DaemonState) startRuns(actions []string, maxRuns int) bool {
+	finished := maxRuns > 0
+	for _, name := range actions {
+		if s.action(name).Runs < maxRuns {
+			finished = false
+		}
+	}
+	if s.MaxRuns == maxRuns && !finished {
+		return false
+	}
+	s.MaxRuns = maxRuns
+	for _, as := range s.Actions {
+		as.Runs = 0
+	}
+	return true
+}
+
+// action returns the schedule for an action, creating it if needed.
+func (s *DaemonState) action(name string) *ActionSchedule {
+	as, ok := s.Actions[name]
+	if !ok {
+		as = &ActionSchedule{}
+		s.Actions[name] = as
+	}
+	return as
+}
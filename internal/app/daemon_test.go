@@ -0,0 +1,87 @@
+// =============================================================================
+// FILE: internal/app/daemon_test.go
+// PURPOSE: Tests for the daemon loop: MaxRuns counts the runs of an
+//          interrupted daemon, so a restart does not start over, but a
+//          finished daemon or a changed limit starts a new count.
+// =============================================================================
+
+package app
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// immediateSchedule is always due.
+type immediateSchedule struct{}
+
+func (immediateSchedule) NextRun(time.Time) time.Time { return time.Now() }
+func (immediateSchedule) String() string              { return "immediately" }
+
+func TestRunDaemonMaxRuns(t *testing.T) {
+	tests := []struct {
+		name      string
+		savedMax  int // MaxRuns of the earlier daemon; 0 with saved 0 is no state
+		saved     int // Runs recorded by the earlier daemon
+		wantCalls int
+	}{
+		{name: "fresh state runs up to max", wantCalls: 2},
+		{name: "interrupted runs count toward max", savedMax: 2, saved: 1, wantCalls: 1},
+		{name: "finished daemon starts over", savedMax: 2, saved: 2, wantCalls: 2},
+		{name: "changed limit starts over", savedMax: 3, saved: 1, wantCalls: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statePath := filepath.Join(t.TempDir(), "daemon_state.json")
+			state := &DaemonState{MaxRuns: tt.savedMax, Actions: map[string]*ActionSchedule{}}
+			if tt.saved > 0 {
+				state.Actions["download"] = &ActionSchedule{
+					Schedule: immediateSchedule{}.String(),
+					LastRun:  time.Now().Add(-time.Hour),
+					NextRun:  time.Now().Add(-time.Minute),
+					Runs:     tt.saved,
+				}
+			}
+			if err := state.Save(statePath); err != nil {
+				t.Fatal(err)
+			}
+
+			calls := 0
+			a := &App{logger: slog.New(slog.DiscardHandler)}
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			err := a.RunDaemon(ctx, DaemonConfig{
+				Schedule:  immediateSchedule{},
+				MaxRuns:   2,
+				StatePath: statePath,
+				Actions:   []string{"download"},
+				Areas:     []string{"timeline"},
+				Run: func(context.Context, string) error {
+					calls++
+					return nil
+				},
+			})
+			if err != nil {
+				t.Fatalf("RunDaemon: %v", err)
+			}
+			if ctx.Err() != nil {
+				t.Fatal("RunDaemon waited for another run past MaxRuns")
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("ran %d times, want %d", calls, tt.wantCalls)
+			}
+
+			saved, err := LoadDaemonState(statePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if saved.MaxRuns != 2 || saved.Actions["download"].Runs != 2 {
+				t.Errorf("saved max runs %d, runs %d; want 2, 2", saved.MaxRuns, saved.Actions["download"].Runs)
+			}
+		})
+	}
+}
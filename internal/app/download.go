@@ -0,0 +1,434 @@
+// =============================================================================
+// FILE: internal/app/download.go
+// PURPOSE: Download wiring. Builds the download orchestrator and media
+//          filter chain from config, resolves output paths for media items,
+//          and records download attempts and completed downloads in the
+//          model database. Ports Python
+//          commands/scraper/actions/download/download.py setup.
+// =============================================================================
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gofscraper/internal/config"
+	"gofscraper/internal/db"
+	"gofscraper/internal/download"
+	"gofscraper/internal/filter"
+	"gofscraper/internal/model"
+	"gofscraper/internal/paths"
+)
+
+// ---------------------------------------------------------------------------
+// Orchestrator setup
+// ---------------------------------------------------------------------------
+
+// NewDownloader creates a download orchestrator configured from the loaded
+// config and bound to the app's HTTP session.
+//
+// Returns:
+//   - A ready-to-use Orchestrator.
+func (a *App) NewDownloader() *download.Orchestrator {
+	cfg := download.DefaultConfig()
+	cfg.Workers = config.GetDownloadSemaphores()
+	cfg.SpeedLimit = config.GetDownloadLimit()
+	cfg.Bandwidth = a.bandwidth
+	cfg.Segments = config.GetDownloadSegments()
+	cfg.SegmentMinSize = config.GetSegmentMinSize()
+	cfg.TempDir = config.GetTempDir()
+	cfg.MinFreeSpace = config.GetSystemFreeSize()
+	cfg.DiskAlert = func(message string) { notifyDiscord(a.logger, message) }
+	cfg.SetFileTimes = config.GetSetFileTimes()
+	cfg.WriteSidecars = config.GetWriteSidecars()
+	cfg.EmbedMetadata = config.GetEmbedMetadata()
+	cfg.ProbeMedia = config.GetProbeMedia()
+	if ffmpeg := config.GetFFmpeg(); ffmpeg != "" {
+		cfg.FFmpegPath = ffmpeg
+	}
+	cfg.Logger = a.logger
+
+	return download.NewOrchestrator(cfg, a.session)
+}
+
+// loadBandwidth applies the configured limit and schedule to the shared
+// bandwidth limiter. Invalid schedule windows are logged and skipped.
+func (a *App) loadBandwidth() {
+	var windows []download.BandwidthWindow
+	for _, lw := range config.GetDownloadLimitSchedule() {
+		w, err := download.ParseBandwidthWindow(lw.Start, lw.End, lw.Limit)
+		if err != nil {
+			a.logger.Warn("ignoring download limit window", "start", lw.Start, "end", lw.End, "error", err)
+			continue
+		}
+		windows = append(windows, w)
+	}
+	a.bandwidth.SetLimit(config.GetDownloadLimit())
+	a.bandwidth.SetSchedule(windows)
+}
+
+// ---------------------------------------------------------------------------
+// Quality selection
+// ---------------------------------------------------------------------------
+
+// QualityPreference returns the quality preference list: the --quality
+// override if set, else download_options.quality.
+//
+// Returns:
+//   - Quality names in order of preference.
+func (a *App) QualityPreference() []string {
+	if len(a.quality) > 0 {
+		return a.quality
+	}
+	return config.GetQuality()
+}
+
+// SelectQuality points each media item at its preferred rendition. Run it
+// before filtering by size and resolving paths, which depend on the choice.
+//
+// Parameters:
+//   - media: The media items to update.
+func (a *App) SelectQuality(media []*model.Media) {
+	prefs := a.QualityPreference()
+	for _, m := range media {
+		m.SelectQuality(prefs)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Path resolution
+// ---------------------------------------------------------------------------
+
+// ResolveMediaPath expands the configured dir and file format templates for
+// a media item and stores the result in m.FilePath.
+//
+// Parameters:
+//   - m: The media item; its post-level fields must already be linked.
+//
+// Returns:
+//   - The resolved file path.
+func ResolveMediaPath(m *model.Media) string {
+	ext := strings.TrimPrefix(path.Ext(m.Filename()), ".")
+	if ext == "" {
+		ext = m.ContentTypeExt()
+	}
+
+	saveLocation := config.GetSaveLocation()
+	mp := model.NewMediaPlaceholder(m, ext)
+	mp.SetBaseVariables(config.ConfigDirPath(), config.GetMainProfile(), saveLocation, 0, "")
+	mp.SetMediaVariables(m.Username, m.ModelID, m.SelectedQuality)
+
+	mp.GenerateDir(config.GetDirFormat(), saveLocation, false)
+	mp.GenerateFilename(config.GetFileFormat())
+	m.FilePath = filepath.Clean(mp.FilePath())
+	return m.FilePath
+}
+
+// ---------------------------------------------------------------------------
+// Filtering
+// ---------------------------------------------------------------------------
+
+// MediaFilters builds the configured media filter chain applied before
+// download: viewable, linked, media type, file size, length, and dupes.
+//
+// Returns:
+//   - The composed MediaFilter.
+func MediaFilters() filter.MediaFilter {
+	return filter.ChainMedia(
+		filter.ByViewable(true),
+		filter.ByURLPresence(),
+		filter.ByMediaType(config.GetFilter()),
+		filter.BySize(float64(config.GetFileSizeMin()), float64(config.GetFileSizeMax())),
+		filter.ByMediaLength(
+			time.Duration(config.GetMinMediaLength())*time.Second,
+			time.Duration(config.GetMaxMediaLength())*time.Second,
+		),
+		filter.ByMediaDupe(),
+	)
+}
+
+// ---------------------------------------------------------------------------
+// Model database
+// ---------------------------------------------------------------------------
+
+// OpenModelDB opens a model's database: its partition of the consolidated
+// database when the metadata template selects one, otherwise its own file.
+//
+// Parameters:
+//   - username: The model's username.
+//
+// Returns:
+//   - The connection, and any error.
+func OpenModelDB(username string) (*db.Conn, error) {
+	if path, ok := paths.ConsolidatedDBPath(); ok {
+		return db.OpenConsolidated(username, path)
+	}
+	return db.Open(username, paths.DBPath(username))
+}
+
+// ---------------------------------------------------------------------------
+// Download
+// ---------------------------------------------------------------------------
+
+// DownloadMedia downloads media items for one model through the model's
+// persistent download queue, records every attempt, and marks every
+// successful download in the model's database. With
+// SetRetryFailed, only media whose last attempt failed are downloaded.
+//
+// Parameters:
+//   - ctx: Context for cancellation.
+//   - username: The model's username.
+//   - media: Media items to download; paths are resolved when unset.
+//
+// Returns:
+//   - The batch Result, and any pipeline-level error.
+func (a *App) DownloadMedia(ctx context.Context, username string, media []*model.Media) (*download.Result, error) {
+	conn, err := OpenModelDB(username)
+	if err != nil {
+		if a.retryFailed {
+			return nil, fmt.Errorf("open database: %w", err)
+		}
+		a.logger.Warn("downloads will not be recorded", "user", username, "error", err)
+	}
+
+	if a.retryFailed {
+		media, err = retryFailedMedia(ctx, conn, media)
+		if err != nil {
+			return nil, err
+		}
+		a.logger.Info("retrying failed downloads", "user", username, "count", len(media))
+	}
+
+	for _, m := range media {
+		if m.FilePath == "" {
+			m.SelectQuality(a.QualityPreference())
+			ResolveMediaPath(m)
+		}
+	}
+	return a.runDownloads(ctx, conn, username, media)
+}
+
+// runDownloads queues media in the model's download queue and downloads
+// them, recording each attempt and each completed download as it finishes.
+// Without a database connection nothing is queued or recorded.
+func (a *App) runDownloads(ctx context.Context, conn *db.Conn, username string, media []*model.Media) (*download.Result, error) {
+	dl := a.NewDownloader()
+	if conn != nil {
+		if err := enqueueMedia(ctx, conn, media); err != nil {
+			a.logger.Warn("download queue unavailable", "user", username, "error", err)
+		}
+		dl.SetAttemptRecorder(attemptRecorder(conn, a.logger))
+		dl.SetQueue(&queueTracker{conn: conn, logger: a.logger})
+	}
+
+	result, err := dl.DownloadBatch(ctx, username, media)
+	if err != nil {
+		return result, fmt.Errorf("download batch: %w", err)
+	}
+
+	if conn != nil {
+		if err := db.ClearDoneDownloads(context.WithoutCancel(ctx), conn); err != nil {
+			a.logger.Warn("failed to clear download queue", "user", username, "error", err)
+		}
+	}
+	return result, nil
+}
+
+// retryFailedMedia keeps the media whose last recorded attempt failed.
+func retryFailedMedia(ctx context.Context, conn *db.Conn, media []*model.Media) ([]*model.Media, error) {
+	failed, err := db.GetFailedMediaIDs(ctx, conn)
+	if err != nil {
+		return nil, fmt.Errorf("load failed downloads: %w", err)
+	}
+	ids := make(map[int64]bool, len(failed))
+	for id := range failed {
+		ids[id] = true
+	}
+	return filter.ByRetryFailed(ids, true)(media), nil
+}
+
+// attemptRecorder returns an AttemptFunc that appends each attempt to the
+// model's download_attempts table.
+func attemptRecorder(conn *db.Conn, logger *slog.Logger) download.AttemptFunc {
+	return func(ctx context.Context, at download.Attempt) {
+		var errMsg string
+		if at.Err != nil {
+			errMsg = at.Err.Error()
+		}
+		row := db.DownloadAttemptRow{
+			MediaID:      at.MediaID,
+			PostID:       at.PostID,
+			AttemptedAt:  at.Started.UTC().Format(time.RFC3339),
+			HTTPStatus:   at.HTTPStatus,
+			ErrorClass:   db.NullString(string(at.ErrorClass)),
+			Error:        db.NullString(errMsg),
+			BytesWritten: at.BytesWritten,
+			DurationMS:   at.Duration.Milliseconds(),
+			Succeeded:    at.Succeeded(),
+			ModelID:      at.ModelID,
+		}
+		// Record interrupted attempts too, after the run context is canceled.
+		if err := db.RecordDownloadAttempt(context.WithoutCancel(ctx), conn, row); err != nil {
+			logger.Warn("failed to record download attempt", "media_id", at.MediaID, "error", err)
+		}
+	}
+}
+
+// MediaRow converts a media item into a database row.
+//
+// Parameters:
+//   - m: The media item; FilePath supplies the directory (recorded as
+//     paths.StoredDir gives it) and filename, and Hash and Size describe
+//     the downloaded file.
+//   - downloaded: Whether the file is on disk.
+//
+// Returns:
+//   - The row to upsert.
+func MediaRow(m *model.Media, downloaded bool) db.MediaRow {
+	row := db.MediaRow{
+		MediaID:    m.ID,
+		PostID:     m.PostID,
+		Link:       db.NullString(m.Link()),
+		Directory:  db.NullString(paths.StoredDir(filepath.Dir(m.FilePath))),
+		Filename:   db.NullString(filepath.Base(m.FilePath)),
+		Size:       int64(m.Size),
+		APIType:    db.NullString(m.ResponseType),
+		MediaType:  db.NullString(string(m.MediaType())),
+		Preview:    m.Preview == 1,
+		Linked:     db.NullString(string(m.DownloadKind())),
+		Downloaded: downloaded,
+		CreatedAt:  db.NullString(m.CreatedAt),
+		PostedAt:   db.NullString(m.PostedAt),
+		Hash:       db.NullString(m.Hash),
+		ModelID:    m.ModelID,
+		Quality:    db.NullString(m.SelectedQuality),
+	}
+	if info := m.Info; info != nil {
+		row.Duration = db.NullFloat64(info.Duration)
+		row.Width = db.NullInt64(int64(info.Width))
+		row.Height = db.NullInt64(int64(info.Height))
+		row.Codec = db.NullString(info.Codec)
+		row.Bitrate = db.NullInt64(info.Bitrate)
+	}
+	return row
+}
+
+// MediaFromRow rebuilds a media item from its database row and its post's
+// row, with the fields the path templates use. Only what the database
+// records is restored; the post text is the sanitized stored text.
+//
+// Parameters:
+//   - row: The media row.
+//   - username: The model's username.
+//   - post: The media's post, message, or story row; nil if not recorded.
+//
+// Returns:
+//   - The media item, linked to a minimal parent post.
+func MediaFromRow(row db.MediaRow, username string, post *db.PostRow) *model.Media {
+	m := &model.Media{
+		ID:              row.MediaID,
+		PostID:          row.PostID,
+		Type:            mediaTypeAPIName(model.MediaType(row.MediaType.String)),
+		Username:        username,
+		ModelID:         row.ModelID,
+		Size:            float64(row.Size),
+		CreatedAt:       row.CreatedAt.String,
+		PostedAt:        row.PostedAt.String,
+		ResponseType:    row.APIType.String,
+		Value:           "free",
+		Hash:            row.Hash.String,
+		SelectedQuality: row.Quality.String,
+	}
+	if row.Linked.String == string(model.DownloadTypeProtected) {
+		m.MpdURL = row.Link.String
+	} else {
+		m.RawURL = row.Link.String
+	}
+	if row.Preview {
+		m.Preview = 1
+	}
+	if row.Directory.Valid && row.Filename.Valid {
+		m.FilePath = filepath.Join(paths.ResolveStoredDir(row.Directory.String), row.Filename.String)
+	}
+
+	p := &model.Post{
+		ID:           row.PostID,
+		ModelID:      row.ModelID,
+		Username:     username,
+		ResponseType: model.ResponseType(row.APIType.String),
+		PostedAt:     row.PostedAt.String,
+	}
+	if post != nil {
+		p.RawText = post.Text.String
+		p.Price = post.Price
+		p.Paid = post.Paid != 0
+		p.Archived = post.Archived != 0
+		m.Value = p.Value()
+		m.Text = p.RawText
+	}
+	m.Post = p
+	return m
+}
+
+// mediaTypeAPIName maps a stored media type back to the API type name that
+// model.Media.MediaType classifies.
+func mediaTypeAPIName(t model.MediaType) string {
+	switch t {
+	case model.MediaTypeImages:
+		return "photo"
+	case model.MediaTypeVideos:
+		return "video"
+	case model.MediaTypeAudios:
+		return "audio"
+	case model.MediaTypeTexts:
+		return "text"
+	}
+	return ""
+}
+
+// LoadMediaInfo attaches the properties probed on earlier downloads to
+// media that have none, so length filters and sorts can use real values.
+// Stored info is only used when it was probed from the same rendition.
+//
+// Parameters:
+//   - ctx: Context for cancellation.
+//   - conn: The model's database.
+//   - media: The media items to update.
+//
+// Returns:
+//   - Error if the database could not be read.
+func LoadMediaInfo(ctx context.Context, conn *db.Conn, media []*model.Media) error {
+	rows, err := db.GetAllMedia(ctx, conn)
+	if err != nil {
+		return fmt.Errorf("load media info: %w", err)
+	}
+	byID := make(map[int64]db.MediaRow, len(rows))
+	for _, row := range rows {
+		if row.Duration.Valid || row.Width.Valid || row.Codec.Valid {
+			byID[row.MediaID] = row
+		}
+	}
+	for _, m := range media {
+		row, ok := byID[m.ID]
+		if !ok || m.Info != nil {
+			continue
+		}
+		if row.Quality.Valid && m.SelectedQuality != "" && row.Quality.String != m.SelectedQuality {
+			continue
+		}
+		m.Info = &model.MediaInfo{
+			Duration: row.Duration.Float64,
+			Width:    int(row.Width.Int64),
+			Height:   int(row.Height.Int64),
+			Codec:    row.Codec.String,
+			Bitrate:  row.Bitrate.Int64,
+		}
+	}
+	return nil
+}
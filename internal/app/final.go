@@ -14,6 +14,7 @@ import (
 	"strings"
 
 	"gofscraper/internal/config"
+	"gofscraper/internal/logging"
 )
 
 // ---------------------------------------------------------------------------
@@ -78,19 +79,21 @@ func runAfterActionScript(logger *slog.Logger) {
 
 // sendDiscordNotification sends a summary to the configured Discord webhook.
 func sendDiscordNotification(logger *slog.Logger, stats *Stats) {
+	notifyDiscord(logger, formatDiscordMessage(stats))
+}
+
+// notifyDiscord sends a message to the configured Discord webhook. Does
+// nothing when no webhook is configured; a failed send is only logged.
+func notifyDiscord(logger *slog.Logger, message string) {
 	webhook := config.GetDiscord()
 	if webhook == "" {
 		return
 	}
 
 	logger.Debug("sending Discord notification")
-
-	message := formatDiscordMessage(stats)
-	_ = message
-	_ = webhook
-
-	// TODO: Wire to HTTP POST to Discord webhook with the message payload.
-	logger.Debug("Discord notification placeholder", "webhook_configured", true)
+	if err := logging.SendDiscord(webhook, message); err != nil {
+		logger.Warn("Discord notification failed", "error", err)
+	}
 }
 
 // formatDiscordMessage builds a Discord-formatted summary message from stats.
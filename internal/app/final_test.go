@@ -0,0 +1,102 @@
+// =============================================================================
+// FILE: internal/app/final_test.go
+// PURPOSE: Tests for Discord notifications: alerts and run summaries are
+//          posted to the configured webhook with their markdown intact.
+// =============================================================================
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"gofscraper/internal/config"
+)
+
+// discordServer records the content of each webhook message it receives.
+type discordServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	messages []string
+}
+
+func newDiscordServer(t *testing.T, status int) *discordServer {
+	t.Helper()
+	s := &discordServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Content string `json:"content"`
+		}
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("webhook request %s with Content-Type %q", r.Method, r.Header.Get("Content-Type"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("decode webhook body: %v", err)
+		}
+		s.mu.Lock()
+		s.messages = append(s.messages, payload.Content)
+		s.mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// useWebhook loads a config whose discord webhook is url.
+func useWebhook(t *testing.T, url string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(fmt.Sprintf(`{"discord": %q}`, url)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.Init(path); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNotifyDiscord(t *testing.T) {
+	srv := newDiscordServer(t, http.StatusNoContent)
+	useWebhook(t, srv.URL)
+
+	alert := "**gofscraper paused downloads**\nFree space on `/data` is `1 GiB`."
+	notifyDiscord(slog.New(slog.DiscardHandler), alert)
+	long := strings.Repeat("x", 2500)
+	notifyDiscord(slog.New(slog.DiscardHandler), long)
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	want := []string{alert, long[:2000], long[2000:]}
+	if len(srv.messages) != len(want) {
+		t.Fatalf("webhook got %d messages, want %d", len(srv.messages), len(want))
+	}
+	for i := range want {
+		if srv.messages[i] != want[i] {
+			t.Errorf("message %d = %.40q, want %.40q", i, srv.messages[i], want[i])
+		}
+	}
+}
+
+func TestNotifyDiscordFailureLogged(t *testing.T) {
+	srv := newDiscordServer(t, http.StatusBadRequest)
+	useWebhook(t, srv.URL)
+
+	var logs strings.Builder
+	notifyDiscord(slog.New(slog.NewTextHandler(&logs, nil)), "alert")
+	if !strings.Contains(logs.String(), "Discord notification failed") || !strings.Contains(logs.String(), "status 400") {
+		t.Errorf("log = %q, want the failed send logged", logs.String())
+	}
+}
+
+func TestNotifyDiscordWithoutWebhook(t *testing.T) {
+	useWebhook(t, "")
+	// Nothing to post to; must return without error or panic.
+	notifyDiscord(slog.New(slog.DiscardHandler), "alert")
+}
@@ -10,7 +10,9 @@ package app
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"gofscraper/internal/api"
 	"gofscraper/internal/model"
 )
 
@@ -24,6 +26,12 @@ type ModelManager struct {
 	user    *model.User
 	areas   []string
 	actions []string
+
+	// after is the date floor; posts older than this are not fetched.
+	after time.Time
+
+	// posts collects every post fetched across all areas.
+	posts *PostCollection
 }
 
 // NewModelManager creates a ModelManager for the given user.
@@ -42,9 +50,27 @@ func NewModelManager(a *App, user *model.User, areas, actions []string) *ModelMa
 		user:    user,
 		areas:   areas,
 		actions: actions,
+		posts:   NewPostCollection(),
 	}
 }
 
+// SetAfter sets the date floor. Pagination stops once it reaches posts
+// older than after; the zero time fetches each area's full history.
+//
+// Parameters:
+//   - after: The earliest post date to fetch.
+func (mm *ModelManager) SetAfter(after time.Time) {
+	mm.after = after
+}
+
+// Posts returns the collection of posts fetched by Process.
+//
+// Returns:
+//   - The model's PostCollection.
+func (mm *ModelManager) Posts() *PostCollection {
+	return mm.posts
+}
+
 // Process runs the complete pipeline for this user: fetch content areas,
 // apply filters, and dispatch actions.
 //
@@ -87,10 +113,13 @@ func (mm *ModelManager) Process(ctx context.Context) (*ModelResult, error) {
 				"error", err,
 			)
 			result.Errors++
-			continue
+			if len(posts) == 0 {
+				continue
+			}
 		}
 
 		result.PostsFound += len(posts)
+		mm.posts.Add(posts...)
 
 		// Collect media from posts.
 		for _, post := range posts {
@@ -125,11 +154,34 @@ func (mm *ModelManager) Process(ctx context.Context) (*ModelResult, error) {
 	return result, nil
 }
 
-// fetchArea retrieves posts for a single content area.
-func (mm *ModelManager) fetchArea(_ context.Context, area string) ([]*model.Post, error) {
-	// TODO: Wire to API post fetcher for the given area.
-	_ = area
-	return nil, nil
+// fetchArea retrieves posts for a single content area, following the
+// endpoint's pagination to the end or to the date floor.
+func (mm *ModelManager) fetchArea(ctx context.Context, area string) ([]*model.Post, error) {
+	client := api.NewClient(mm.app.session)
+
+	fetched, err := FetchAreaPosts(ctx, client, area, mm.user.ID, mm.after)
+	if err != nil && len(fetched) == 0 {
+		return nil, fmt.Errorf("fetch %s: %w", area, err)
+	}
+
+	posts := make([]*model.Post, 0, len(fetched))
+	for i := range fetched {
+		post := &fetched[i]
+		post.Username = mm.user.Name
+		post.LinkMedia()
+		posts = append(posts, post)
+	}
+
+	mm.app.logger.Debug("fetched area",
+		"user", mm.user.Name,
+		"area", area,
+		"posts", len(posts),
+	)
+
+	if err != nil {
+		return posts, fmt.Errorf("fetch %s: %w", area, err)
+	}
+	return posts, nil
 }
 
 // ---------------------------------------------------------------------------
@@ -0,0 +1,147 @@
+// =============================================================================
+// FILE: internal/app/queue.go
+// PURPOSE: Persistent download queue wiring. Stores each download batch in
+//          the model database's download_queue, tracks items as workers pick
+//          them up and finish them, and resumes the leftover queue of an
+//          interrupted run.
+// =============================================================================
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"gofscraper/internal/db"
+	"gofscraper/internal/download"
+	"gofscraper/internal/model"
+)
+
+// ---------------------------------------------------------------------------
+// Resume
+// ---------------------------------------------------------------------------
+
+// ResumeQueue downloads the media left in a model's download queue by an
+// interrupted run. Items that were in flight are queued again first.
+//
+// Parameters:
+//   - ctx: Context for cancellation.
+//   - username: The model's username.
+//
+// Returns:
+//   - The resumed media and the batch Result (both nil when the queue is
+//     empty), and any error.
+func (a *App) ResumeQueue(ctx context.Context, username string) ([]*model.Media, *download.Result, error) {
+	conn, err := OpenModelDB(username)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open database: %w", err)
+	}
+
+	requeued, err := db.RequeueActive(ctx, conn)
+	if err != nil {
+		return nil, nil, fmt.Errorf("requeue interrupted downloads: %w", err)
+	}
+	rows, err := db.GetQueuedDownloads(ctx, conn)
+	if err != nil {
+		return nil, nil, fmt.Errorf("load download queue: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, nil, nil
+	}
+
+	media := make([]*model.Media, 0, len(rows))
+	for _, row := range rows {
+		m, err := queuedMedia(row)
+		if err != nil {
+			a.logger.Warn("dropping unreadable queue entry", "media_id", row.MediaID, "error", err)
+			db.SetQueueState(ctx, conn, row.MediaID, db.QueueDone)
+			continue
+		}
+		media = append(media, m)
+	}
+
+	a.logger.Info("resuming download queue", "user", username, "count", len(media), "interrupted", requeued)
+	result, err := a.runDownloads(ctx, conn, username, media)
+	return media, result, err
+}
+
+// ---------------------------------------------------------------------------
+// Queue tracking
+// ---------------------------------------------------------------------------
+
+// queueEntry is the JSON stored for a queued download. model.Media does not
+// encode its parent post, so the post is stored beside it for the sidecar,
+// embedded metadata, and path templates of a resumed download.
+type queueEntry struct {
+	*model.Media
+	Post *model.Post `json:"post,omitempty"`
+}
+
+// queuedMedia decodes a queue row into its media item, linked to its post.
+func queuedMedia(row db.QueueRow) (*model.Media, error) {
+	entry := queueEntry{Media: &model.Media{}}
+	if err := json.Unmarshal([]byte(row.Media), &entry); err != nil {
+		return nil, err
+	}
+	m := entry.Media
+	m.Post = entry.Post
+	m.FilePath = row.FilePath
+	return m, nil
+}
+
+// enqueueMedia stores media in the download queue as pending.
+func enqueueMedia(ctx context.Context, conn *db.Conn, media []*model.Media) error {
+	rows := make([]db.QueueRow, 0, len(media))
+	for _, m := range media {
+		entry := queueEntry{Media: m}
+		if m.Post != nil {
+			// The post's other media are queued as entries of their own.
+			post := *m.Post
+			post.AllMedia = nil
+			entry.Post = &post
+		}
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("encode media %d: %w", m.ID, err)
+		}
+		rows = append(rows, db.QueueRow{
+			MediaID:  m.ID,
+			PostID:   m.PostID,
+			ModelID:  m.ModelID,
+			FilePath: m.FilePath,
+			Media:    string(data),
+		})
+	}
+	return db.EnqueueDownloads(ctx, conn, rows)
+}
+
+// queueTracker implements download.Queue over a model's download_queue.
+// A successful download is recorded in medias before its queue entry is
+// marked done, so a crash never loses a finished file.
+type queueTracker struct {
+	conn   *db.Conn
+	logger *slog.Logger
+}
+
+// Started marks m as in flight.
+func (q *queueTracker) Started(ctx context.Context, m *model.Media) {
+	if err := db.SetQueueState(ctx, q.conn, m.ID, db.QueueActive); err != nil {
+		q.logger.Warn("failed to update download queue", "media_id", m.ID, "error", err)
+	}
+}
+
+// Finished records a successful download and marks m as done.
+func (q *queueTracker) Finished(ctx context.Context, m *model.Media) {
+	ctx = context.WithoutCancel(ctx)
+	if m.DownloadStatusString() == model.DownloadStatusSucceeded {
+		if err := db.UpsertMedia(ctx, q.conn, MediaRow(m, true)); err != nil {
+			q.logger.Warn("failed to record download", "media_id", m.ID, "error", err)
+			return
+		}
+	}
+	if err := db.SetQueueState(ctx, q.conn, m.ID, db.QueueDone); err != nil {
+		q.logger.Warn("failed to update download queue", "media_id", m.ID, "error", err)
+	}
+}
@@ -0,0 +1,80 @@
+// =============================================================================
+// FILE: internal/app/queue_test.go
+// PURPOSE: Tests for the persistent download queue: a resumed item comes
+//          back linked to its post, so sidecars, embedded metadata, and
+//          path templates see the same post fields as the original run.
+// =============================================================================
+
+package app
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"gofscraper/internal/db"
+	"gofscraper/internal/download"
+	"gofscraper/internal/model"
+)
+
+func TestQueueKeepsPost(t *testing.T) {
+	conn, err := db.Open("queue_test", filepath.Join(t.TempDir(), "user_data.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close("queue_test") })
+
+	post := &model.Post{
+		ID:           10,
+		ModelID:      7,
+		Username:     "queue_test",
+		RawText:      "caption",
+		Title:        "A title",
+		Price:        4.99,
+		Paid:         true,
+		ResponseType: model.ResponseTimeline,
+		PostedAt:     "2024-05-01T12:00:00+00:00",
+	}
+	media := []*model.Media{
+		{ID: 1, PostID: 10, ModelID: 7, Username: "queue_test", Type: "photo", RawURL: "https://cdn.test/1.jpg", FilePath: "/out/1.jpg", Post: post},
+		{ID: 2, PostID: 10, ModelID: 7, Username: "queue_test", Type: "video", RawURL: "https://cdn.test/2.mp4", FilePath: "/out/2.mp4", Post: post},
+	}
+	post.AllMedia = media
+
+	ctx := context.Background()
+	if err := enqueueMedia(ctx, conn, media); err != nil {
+		t.Fatalf("enqueueMedia: %v", err)
+	}
+	rows, err := db.GetQueuedDownloads(ctx, conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != len(media) {
+		t.Fatalf("queued %d rows, want %d", len(rows), len(media))
+	}
+
+	for _, row := range rows {
+		m, err := queuedMedia(row)
+		if err != nil {
+			t.Fatalf("queuedMedia(%d): %v", row.MediaID, err)
+		}
+		if m.Post == nil {
+			t.Fatalf("media %d resumed without its post", m.ID)
+		}
+		if m.FilePath != row.FilePath || m.RawURL == "" {
+			t.Errorf("media %d path %q url %q, want the queued values", m.ID, m.FilePath, m.RawURL)
+		}
+		p := m.Post
+		if p.ID != post.ID || p.Title != post.Title || p.RawText != post.RawText || p.Price != post.Price || !p.Paid {
+			t.Errorf("media %d post = %+v, want the original post fields", m.ID, p)
+		}
+		if len(p.AllMedia) != 0 {
+			t.Errorf("media %d post holds %d media, want none stored", m.ID, len(p.AllMedia))
+		}
+
+		sc := download.NewSidecar(m)
+		if sc.Title != "A title" || sc.Price == nil || *sc.Price != 4.99 {
+			t.Errorf("media %d sidecar title %q price %v, want the post's", m.ID, sc.Title, sc.Price)
+		}
+	}
+}
@@ -1,11 +1,14 @@
 // =============================================================================
 // FILE: internal/cli/accessors/actions.go
-// PURPOSE: Read action and daemon flag values from cobra commands.
+// PURPOSE: Read action, daemon, and daemon schedule flag values from cobra
+//          commands.
 // =============================================================================
 
 package accessors
 
 import (
+	"time"
+
 	"github.com/spf13/cobra"
 )
 
@@ -20,3 +23,39 @@ func GetDaemon(cmd *cobra.Command) bool {
 	v, _ := cmd.Flags().GetBool("daemon")
 	return v
 }
+
+// GetInterval returns the daemon interval flag value.
+func GetInterval(cmd *cobra.Command) time.Duration {
+	v, _ := cmd.Flags().GetDuration("interval")
+	return v
+}
+
+// GetCron returns the daemon cron expression flag value.
+func GetCron(cmd *cobra.Command) string {
+	v, _ := cmd.Flags().GetString("cron")
+	return v
+}
+
+// GetStartTime returns the daemon start-time flag value.
+func GetStartTime(cmd *cobra.Command) string {
+	v, _ := cmd.Flags().GetString("start-time")
+	return v
+}
+
+// GetQuietHours returns the daemon quiet-hours flag values.
+func GetQuietHours(cmd *cobra.Command) []string {
+	v, _ := cmd.Flags().GetStringSlice("quiet-hours")
+	return v
+}
+
+// GetJitter returns the daemon jitter flag value.
+func GetJitter(cmd *cobra.Command) time.Duration {
+	v, _ := cmd.Flags().GetDuration("jitter")
+	return v
+}
+
+// GetMaxRuns returns the daemon max-runs flag value.
+func GetMaxRuns(cmd *cobra.Command) int {
+	v, _ := cmd.Flags().GetInt("max-runs")
+	return v
+}
@@ -20,3 +20,10 @@ func GetPostsAreas(cmd *cobra.Command) []string {
 	v, _ := cmd.Flags().GetStringSlice("posts")
 	return v
 }
+
+// GetTableSort returns the check table sort column and direction.
+func GetTableSort(cmd *cobra.Command) (key string, desc bool) {
+	key, _ = cmd.Flags().GetString("table-sort")
+	desc, _ = cmd.Flags().GetBool("table-desc")
+	return key, desc
+}
@@ -20,3 +20,9 @@ func GetFileFormat(cmd *cobra.Command) string {
 	v, _ := cmd.Flags().GetString("file-format")
 	return v
 }
+
+// GetRetryFailed returns true if only previously failed downloads should run.
+func GetRetryFailed(cmd *cobra.Command) bool {
+	v, _ := cmd.Flags().GetBool("retry-failed")
+	return v
+}
@@ -9,12 +9,23 @@ import (
 	"github.com/spf13/cobra"
 )
 
-// GetQuality returns the quality flag value.
-func GetQuality(cmd *cobra.Command) string {
-	v, _ := cmd.Flags().GetString("quality")
+// GetQuality returns the quality preference list.
+func GetQuality(cmd *cobra.Command) []string {
+	v, _ := cmd.Flags().GetStringSlice("quality")
 	return v
 }
 
+// GetMediaSort returns the media-sort key and direction. The key is empty
+// unless --media-sort was given, leaving media in API order.
+func GetMediaSort(cmd *cobra.Command) (string, bool) {
+	desc, _ := cmd.Flags().GetBool("media-desc")
+	if !cmd.Flags().Changed("media-sort") {
+		return "", desc
+	}
+	v, _ := cmd.Flags().GetString("media-sort")
+	return v, desc
+}
+
 // GetMediaTypes returns the media-type flag values.
 func GetMediaTypes(cmd *cobra.Command) []string {
 	v, _ := cmd.Flags().GetStringSlice("media-type")
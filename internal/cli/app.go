@@ -0,0 +1,74 @@
+// =============================================================================
+// FILE: internal/cli/app.go
+// PURPOSE: Application bootstrap shared by subcommands. Creates and
+//          initializes the app instance a command runs against.
+// =============================================================================
+
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"gofscraper/internal/app"
+	"gofscraper/internal/cli/accessors"
+	"gofscraper/internal/utils"
+)
+
+// startApp creates and initializes the application. Callers must defer
+// Shutdown on the returned App.
+func startApp() (*app.App, error) {
+	a := app.New()
+	if err := a.Init(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// commandUsernames collects usernames from positional args, --usernames, and
+// --user, accepting profile URLs as well as plain names.
+func commandUsernames(cmd *cobra.Command, args []string) []string {
+	raw := append([]string{}, args...)
+	raw = append(raw, accessors.GetUsernames(cmd)...)
+	if users, err := cmd.Flags().GetStringSlice("user"); err == nil {
+		raw = append(raw, users...)
+	}
+
+	seen := make(map[string]bool)
+	var out []string
+	for _, name := range utils.ParseUsernames(raw) {
+		if !seen[name] {
+			seen[name] = true
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+// readListFile reads one entry per line, skipping blank lines and # comments.
+// Used for URL files and username lists.
+func readListFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open list file: %w", err)
+	}
+	defer f.Close()
+
+	var entries []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		entries = append(entries, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read list file: %w", err)
+	}
+	return entries, nil
+}
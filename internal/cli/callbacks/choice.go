@@ -7,7 +7,10 @@ package callbacks
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+
+	"gofscraper/internal/model"
 )
 
 // ValidateChoice checks that value is one of the allowed choices (case-insensitive).
@@ -26,9 +29,17 @@ func ValidateAction(value string) error {
 	return ValidateChoice(value, []string{"download", "like", "unlike"})
 }
 
-// ValidateQuality validates a quality flag value.
+// ValidateQuality validates a quality flag value: source, a legacy alias
+// (high, medium, low), or a height such as 720 or 720p.
 func ValidateQuality(value string) error {
-	return ValidateChoice(value, []string{"source", "high", "medium", "low"})
+	q := model.NormalizeQuality(value)
+	if q == model.QualitySource {
+		return nil
+	}
+	if _, err := strconv.Atoi(q); err == nil {
+		return nil
+	}
+	return fmt.Errorf("invalid quality %q: must be source, high, medium, low, or a height such as 720", value)
 }
 
 // ValidateKeyMode validates a key-mode flag value.
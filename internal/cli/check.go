@@ -0,0 +1,48 @@
+// =============================================================================
+// FILE: internal/cli/check.go
+// PURPOSE: Shared runner for the check subcommands (msg_check, story_check,
+//          paid_check, post_check). Ports Python utils/args check dispatch.
+// =============================================================================
+
+package cli
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"gofscraper/internal/cli/accessors"
+	"gofscraper/internal/commands"
+)
+
+// runCheck runs the given check type for the users named on the command.
+func runCheck(cmd *cobra.Command, args []string, checkType commands.CheckType) error {
+	a, err := startApp()
+	if err != nil {
+		return err
+	}
+	defer a.Shutdown()
+	a.SetRetryFailed(accessors.GetRetryFailed(cmd))
+	a.SetQuality(accessors.GetQuality(cmd))
+
+	check := commands.NewCheckCommand(a.Logger(), checkType)
+	check.SetAreas(accessors.GetAreas(cmd))
+	check.SetAfter(accessors.GetAfterDate(cmd))
+	check.SetSort(accessors.GetTableSort(cmd))
+	check.SetInteractive(interactiveTable(cmd))
+
+	return check.Run(a.Context(), a, commandUsernames(cmd, args))
+}
+
+// interactiveTable reports whether check results should open in the
+// triage TUI: stdout must be a terminal and --no-interactive unset.
+func interactiveTable(cmd *cobra.Command) bool {
+	if off, _ := cmd.Flags().GetBool("no-interactive"); off {
+		return false
+	}
+	fi, err := os.Stdout.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
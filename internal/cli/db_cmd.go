@@ -1,29 +1,182 @@
 // =============================================================================
 // FILE: internal/cli/db_cmd.go
-// PURPOSE: DB subcommand. Database management operations (backup, merge, etc.).
-//          Ports Python parse/commands/db.py.
+// PURPOSE: DB subcommand. Database management operations (backup, merge,
+//          diff, stats, restore, migrate, rebase, consolidate, import-legacy). Ports Python parse/commands/db.py.
 // =============================================================================
 
 package cli
 
 import (
-	"fmt"
-
 	"github.com/spf13/cobra"
+
+	"gofscraper/internal/commands"
 )
 
 var dbCmd = &cobra.Command{
 	Use:   "db",
 	Short: "Database management operations",
+	Long: `Manage per-model databases. Databases are named by model username
+or by a path to a .db file.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Legacy flag forms: --backup [user...], --merge SRC DEST.
+		if backup, _ := cmd.Flags().GetBool("backup"); backup {
+			return runDB(commands.DBOpBackup, args)
+		}
+		if src, _ := cmd.Flags().GetString("merge"); src != "" {
+			return runDB(commands.DBOpMerge, append([]string{src}, args...))
+		}
+		return cmd.Help()
+	},
+}
+
+var dbBackupCmd = &cobra.Command{
+	Use:   "backup [user...]",
+	Short: "Create timestamped backups (all models when none given)",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runDB(commands.DBOpBackup, args)
+	},
+}
+
+var dbMergeCmd = &cobra.Command{
+	Use:   "merge SOURCE DEST",
+	Short: "Merge SOURCE's database into DEST's (DEST is backed up first)",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runDB(commands.DBOpMerge, args)
+	},
+}
+
+var dbDiffCmd = &cobra.Command{
+	Use:   "diff SOURCE DEST",
+	Short: "Compare two databases table by table",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runDB(commands.DBOpDiff, args)
+	},
+}
+
+var dbStatsCmd = &cobra.Command{
+	Use:   "stats [user...]",
+	Short: "Show record and download counts (all models when none given)",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		fmt.Println("Database management mode...")
-		return nil
+		return runDB(commands.DBOpStats, args)
+	},
+}
+
+var dbRestoreCmd = &cobra.Command{
+	Use:   "restore USER [BACKUP]",
+	Short: "Restore a database from a backup (newest when BACKUP is omitted)",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runDB(commands.DBOpRestore, args)
+	},
+}
+
+var dbMigrateCmd = &cobra.Command{
+	Use:   "migrate [user...]",
+	Short: "Upgrade databases to the current schema (all models when none given)",
+	Long: `Applies pending schema migrations. Each database is backed up before
+its first pending step. With --dry-run the SQL is printed instead.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		a, err := startApp()
+		if err != nil {
+			return err
+		}
+		defer a.Shutdown()
+
+		dbc := commands.NewDBCommand(a.Logger(), commands.DBOpMigrate)
+		dbc.SetDryRun(dryRun)
+		return dbc.Run(a.Context(), a, args)
+	},
+}
+
+var dbRebaseCmd = &cobra.Command{
+	Use:   "rebase --from OLD --to NEW [user...]",
+	Short: "Rewrite recorded paths after the save location moves (all models when none given)",
+	Long: `Replaces the directory prefix OLD with NEW in every recorded media
+directory and queued download path, for when the archive has moved, e.g. from
+/mnt/old to /srv/archive. Only whole path components match, and directories
+recorded relative to save_location are left alone. With --dry-run the affected
+rows are counted instead.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, _ := cmd.Flags().GetString("from")
+		to, _ := cmd.Flags().GetString("to")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		a, err := startApp()
+		if err != nil {
+			return err
+		}
+		defer a.Shutdown()
+
+		dbc := commands.NewDBCommand(a.Logger(), commands.DBOpRebase)
+		dbc.SetRebase(from, to)
+		dbc.SetDryRun(dryRun)
+		return dbc.Run(a.Context(), a, args)
+	},
+}
+
+var dbConsolidateCmd = &cobra.Command{
+	Use:   "consolidate [user...]",
+	Short: "Fold per-model databases into the consolidated database (all models when none given)",
+	Long: `Copies each model's own database into the single database the metadata
+template selects when it names no per-model placeholder, e.g.
+"{configpath}/{profile}/.data/user_data.db". Every row is assigned to the
+model's ID, and a model already folded is skipped. The consolidated database is
+backed up first; the per-model files are left in place. With --dry-run the
+models and their IDs are listed instead.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		a, err := startApp()
+		if err != nil {
+			return err
+		}
+		defer a.Shutdown()
+
+		dbc := commands.NewDBCommand(a.Logger(), commands.DBOpConsolidate)
+		dbc.SetDryRun(dryRun)
+		return dbc.Run(a.Context(), a, args)
+	},
+}
+
+var dbImportLegacyCmd = &cobra.Command{
+	Use:   "import-legacy FILE [USER]",
+	Short: "Import a Python OF-Scraper database (USER inferred from FILE's path)",
+	Long: `Imports download history from a Python OF-Scraper user_data.db so
+already-downloaded media is not fetched again. Rows are de-duplicated by ID
+and the destination database is backed up first.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runDB(commands.DBOpImport, args)
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(dbCmd)
+	dbCmd.AddCommand(dbBackupCmd, dbMergeCmd, dbDiffCmd, dbStatsCmd, dbRestoreCmd, dbMigrateCmd,
+		dbImportLegacyCmd, dbRebaseCmd, dbConsolidateCmd)
 
 	dbCmd.Flags().Bool("backup", false, "Create a database backup")
 	dbCmd.Flags().String("merge", "", "Merge another database into the current one")
+	dbMigrateCmd.Flags().Bool("dry-run", false, "Print the migration SQL without applying it")
+	dbRebaseCmd.Flags().String("from", "", "Old path prefix")
+	dbRebaseCmd.Flags().String("to", "", "New path prefix")
+	dbRebaseCmd.Flags().Bool("dry-run", false, "Count the rows that would change without rewriting them")
+	_ = dbRebaseCmd.MarkFlagRequired("from")
+	_ = dbRebaseCmd.MarkFlagRequired("to")
+	dbConsolidateCmd.Flags().Bool("dry-run", false, "List the models that would be folded without copying them")
+}
+
+// runDB runs a DBCommand operation with a started app.
+func runDB(op commands.DBOperation, args []string) error {
+	a, err := startApp()
+	if err != nil {
+		return err
+	}
+	defer a.Shutdown()
+
+	return commands.NewDBCommand(a.Logger(), op).Run(a.Context(), a, args)
 }
@@ -1,11 +1,14 @@
 // =============================================================================
 // FILE: internal/cli/flags/automatic.go
-// PURPOSE: Automatic flag definitions: action, daemon.
+// PURPOSE: Automatic flag definitions: action, daemon, and the daemon
+//          schedule (interval, cron, start-time, quiet-hours, jitter, max-runs).
 // =============================================================================
 
 package flags
 
 import (
+	"time"
+
 	"github.com/spf13/cobra"
 )
 
@@ -14,4 +17,10 @@ func RegisterAutomaticFlags(cmd *cobra.Command) {
 	f := cmd.Flags()
 	f.StringSliceP("action", "a", []string{"download"}, "Actions to perform (download, like, unlike)")
 	f.BoolP("daemon", "d", false, "Run in daemon mode with periodic execution")
+	f.Duration("interval", 6*time.Hour, "Time between daemon runs")
+	f.String("cron", "", "Daemon schedule as a 5-field cron expression (overrides --interval)")
+	f.String("start-time", "", "Time of day (HH:MM) for the first daemon run")
+	f.StringSlice("quiet-hours", nil, "Windows (HH:MM-HH:MM) in which the daemon does not start runs")
+	f.Duration("jitter", 0, "Random delay of up to this long added to each daemon run")
+	f.Int("max-runs", 0, "Stop the daemon after this many runs (0 = unlimited)")
 }
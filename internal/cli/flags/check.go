@@ -1,7 +1,7 @@
 // =============================================================================
 // FILE: internal/cli/flags/check.go
 // PURPOSE: Check flag definitions: check-area, user, file, force,
-//          table-progress, table-name.
+//          table-progress, table-name, table-sort, table-desc.
 // =============================================================================
 
 package flags
@@ -19,4 +19,6 @@ func RegisterCheckFlags(cmd *cobra.Command) {
 	f.Bool("force", false, "Force check even if recently completed")
 	f.Bool("table-progress", true, "Show progress in table format")
 	f.String("table-name", "", "Custom table name for progress display")
+	f.String("table-sort", "date", "Sort check table by column (date, price, media, downloaded, text)")
+	f.Bool("table-desc", false, "Sort check table in descending order")
 }
@@ -1,6 +1,7 @@
 // =============================================================================
 // FILE: internal/cli/flags/download.go
-// PURPOSE: Download flag definitions: arrow, database, save-dir, download-limit.
+// PURPOSE: Download flag definitions: arrow, database, save-dir, download-limit,
+//          retry-failed.
 // =============================================================================
 
 package flags
@@ -16,4 +17,5 @@ func RegisterDownloadFlags(cmd *cobra.Command) {
 	f.String("database", "", "Path to the database file")
 	f.String("save-dir", "", "Directory where downloaded content is saved")
 	f.Int("download-limit", 0, "Maximum number of concurrent downloads (0 = unlimited)")
+	f.Bool("retry-failed", false, "Only download media whose last download attempt failed")
 }
@@ -15,7 +15,7 @@ import (
 // RegisterMediaFilterFlags adds media filtering flags to the given command.
 func RegisterMediaFilterFlags(cmd *cobra.Command) {
 	f := cmd.Flags()
-	f.String("quality", "source", "Preferred media quality (source, high, medium, low)")
+	f.StringSlice("quality", nil, "Preferred media qualities in order, e.g. 720,source (source, 720, 240, ...)")
 	f.StringSlice("media-type", nil, "Media types to include (images, videos, audio)")
 	f.Int64("size-max", 0, "Maximum file size in bytes (0 = no limit)")
 	f.Int64("size-min", 0, "Minimum file size in bytes")
@@ -23,7 +23,7 @@ func RegisterMediaFilterFlags(cmd *cobra.Command) {
 	f.Int("length-max", 0, "Maximum media duration in seconds (0 = no limit)")
 	f.Int("length-min", 0, "Minimum media duration in seconds")
 	f.Int("max-count", 0, "Maximum number of media items to process (0 = unlimited)")
-	f.String("media-sort", "date", "Sort media by field (date, size, type)")
+	f.String("media-sort", "date", "Sort media by field (date, id, type, size, duration, resolution, bitrate)")
 	f.Bool("media-desc", false, "Sort media in descending order")
 	f.StringSlice("excluded", nil, "Media IDs to exclude")
 	f.StringSlice("excluded-quality", nil, "Quality levels to exclude")
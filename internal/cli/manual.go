@@ -1,28 +1,58 @@
 // =============================================================================
 // FILE: internal/cli/manual.go
-// PURPOSE: Manual subcommand. Downloads content from direct URLs.
-//          Ports Python parse/commands/manual.py.
+// PURPOSE: Manual subcommand. Downloads content from post, message, and
+//          story URLs. Ports Python parse/commands/manual.py.
 // =============================================================================
 
 package cli
 
 import (
-	"fmt"
-
 	"github.com/spf13/cobra"
+
+	"gofscraper/internal/cli/accessors"
+	"gofscraper/internal/commands"
 )
 
 var manualCmd = &cobra.Command{
-	Use:   "manual",
+	Use:   "manual [url...]",
 	Short: "Download from direct URLs",
-	Long:  `Downloads content from specific URLs provided as arguments.`,
+	Long: `Downloads content from post, chat message, story, and highlight URLs
+given as arguments, with --url, or one per line in --url-file.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		fmt.Println("Manual download mode...")
-		return nil
+		urls, err := manualURLs(cmd, args)
+		if err != nil {
+			return err
+		}
+
+		a, err := startApp()
+		if err != nil {
+			return err
+		}
+		defer a.Shutdown()
+		a.SetQuality(accessors.GetQuality(cmd))
+
+		return commands.NewManualCommand(a.Logger()).Run(a.Context(), a, urls)
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(manualCmd)
 	manualCmd.Flags().StringSlice("url", nil, "URLs to download")
+	manualCmd.Flags().String("url-file", "", "File of newline-separated URLs to download")
+}
+
+// manualURLs merges URLs from args, --url, and --url-file.
+func manualURLs(cmd *cobra.Command, args []string) ([]string, error) {
+	urls := append([]string{}, args...)
+	flagURLs, _ := cmd.Flags().GetStringSlice("url")
+	urls = append(urls, flagURLs...)
+
+	if path, _ := cmd.Flags().GetString("url-file"); path != "" {
+		fileURLs, err := readListFile(path)
+		if err != nil {
+			return nil, err
+		}
+		urls = append(urls, fileURLs...)
+	}
+	return urls, nil
 }
@@ -7,20 +7,21 @@
 package cli
 
 import (
-	"fmt"
-
 	"github.com/spf13/cobra"
+
+	"gofscraper/internal/cli/bundles"
+	"gofscraper/internal/commands"
 )
 
 var msgCheckCmd = &cobra.Command{
 	Use:   "msg_check",
 	Short: "Check and list messages",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		fmt.Println("Message check mode...")
-		return nil
+		return runCheck(cmd, args, commands.CheckMessages)
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(msgCheckCmd)
+	bundles.RegisterMsgCheckBundle(msgCheckCmd)
 }
@@ -8,7 +8,7 @@ package mutators
 import (
 	"github.com/spf13/cobra"
 
-	"gofscraper/internal/cli/callbacks"
+	"gofscraper/internal/utils"
 )
 
 // MutateUsers parses and normalises the username and excluded-users flag
@@ -16,13 +16,13 @@ import (
 func MutateUsers(cmd *cobra.Command) {
 	// Parse usernames.
 	if raw, _ := cmd.Flags().GetStringSlice("usernames"); len(raw) > 0 {
-		parsed := callbacks.ParseUsernames(raw)
+		parsed := utils.ParseUsernames(raw)
 		_ = cmd.Flags().Set("usernames", sliceToCSV(parsed))
 	}
 
 	// Parse excluded users.
 	if raw, _ := cmd.Flags().GetStringSlice("excluded-users"); len(raw) > 0 {
-		parsed := callbacks.ParseUsernames(raw)
+		parsed := utils.ParseUsernames(raw)
 		_ = cmd.Flags().Set("excluded-users", sliceToCSV(parsed))
 	}
 }
@@ -7,20 +7,21 @@
 package cli
 
 import (
-	"fmt"
-
 	"github.com/spf13/cobra"
+
+	"gofscraper/internal/cli/bundles"
+	"gofscraper/internal/commands"
 )
 
 var paidCheckCmd = &cobra.Command{
 	Use:   "paid_check",
 	Short: "Check and list purchased content",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		fmt.Println("Paid check mode...")
-		return nil
+		return runCheck(cmd, args, commands.CheckPaid)
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(paidCheckCmd)
+	bundles.RegisterPaidCheckBundle(paidCheckCmd)
 }
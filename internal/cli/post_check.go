@@ -7,20 +7,21 @@
 package cli
 
 import (
-	"fmt"
-
 	"github.com/spf13/cobra"
+
+	"gofscraper/internal/cli/bundles"
+	"gofscraper/internal/commands"
 )
 
 var postCheckCmd = &cobra.Command{
 	Use:   "post_check",
 	Short: "Check and list posts",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		fmt.Println("Post check mode...")
-		return nil
+		return runCheck(cmd, args, commands.CheckPosts)
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(postCheckCmd)
+	bundles.RegisterPostCheckBundle(postCheckCmd)
 }
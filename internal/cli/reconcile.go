@@ -0,0 +1,45 @@
+// =============================================================================
+// FILE: internal/cli/reconcile.go
+// PURPOSE: Reconcile subcommand. Matches files on disk with database rows,
+//          reporting orphan files, missing downloads, and moved files.
+// =============================================================================
+
+package cli
+
+import (
+	"github.com/spf13/cobra"
+
+	"gofscraper/internal/commands"
+)
+
+var reconcileCmd = &cobra.Command{
+	Use:   "reconcile [user...]",
+	Short: "Match files on disk with database records",
+	Long: `Walks each model's directory under the save location (all models when none
+given) and matches the files with the model's media records, by recorded path,
+then by filename, then by the media ID in the filename. Reports files with no
+record (orphans), downloaded records whose file is gone (missing), records whose
+file moved, and files present for records not marked downloaded (found).
+
+With --fix, moved and found files are adopted into their records and missing
+records are marked not downloaded so the next scrape fetches them again.
+Orphans are only reported. Exits non-zero when discrepancies are left unfixed.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fix, _ := cmd.Flags().GetBool("fix")
+
+		a, err := startApp()
+		if err != nil {
+			return err
+		}
+		defer a.Shutdown()
+
+		r := commands.NewReconcileCommand(a.Logger())
+		r.SetFix(fix)
+		return r.Run(a.Context(), a, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(reconcileCmd)
+	reconcileCmd.Flags().Bool("fix", false, "Adopt moved and found files and reset missing downloads")
+}
@@ -0,0 +1,46 @@
+// =============================================================================
+// FILE: internal/cli/reorganize.go
+// PURPOSE: Reorganize subcommand. Moves downloaded files to the paths the
+//          current dir_format and file_format give them.
+// =============================================================================
+
+package cli
+
+import (
+	"github.com/spf13/cobra"
+
+	"gofscraper/internal/commands"
+)
+
+var reorganizeCmd = &cobra.Command{
+	Use:   "reorganize [user...]",
+	Short: "Move downloaded files to match the current path templates",
+	Long: `Re-renders the path of every downloaded media item with the current
+dir_format and file_format (all models when none given), prints the move plan,
+and moves each file whose path changed, together with its sidecar. Moves onto
+an existing file, or of two files onto one path, are reported as collisions and
+skipped. Emptied directories are removed and the new paths are recorded in one
+transaction per model.
+
+A journal in the model's directory records the plan while files are moved; if a
+run is interrupted, running reorganize again finishes it. With --dry-run only
+the plan is printed.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		a, err := startApp()
+		if err != nil {
+			return err
+		}
+		defer a.Shutdown()
+
+		r := commands.NewReorganizeCommand(a.Logger())
+		r.SetDryRun(dryRun)
+		return r.Run(a.Context(), a, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(reorganizeCmd)
+	reorganizeCmd.Flags().Bool("dry-run", false, "Print the move plan without moving files")
+}
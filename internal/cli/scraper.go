@@ -7,9 +7,12 @@
 package cli
 
 import (
-	"fmt"
-
 	"github.com/spf13/cobra"
+
+	"gofscraper/internal/cli/accessors"
+	"gofscraper/internal/cli/bundles"
+	"gofscraper/internal/commands/scraper"
+	"gofscraper/internal/utils"
 )
 
 // ---------------------------------------------------------------------------
@@ -17,23 +20,86 @@ import (
 // ---------------------------------------------------------------------------
 
 var scraperCmd = &cobra.Command{
-	Use:   "scraper",
+	Use:   "scraper [username...]",
 	Short: "Run the scraper to download content",
-	Long:  `Downloads media and text from OnlyFans creators based on configured settings.`,
+	Long: `Downloads media and text from OnlyFans creators based on configured settings.
+Users come from arguments, --usernames, and --user-list files; with none
+given, every active subscription is scraped. With --daemon the scrape
+repeats on --interval or --cron, resuming its schedule across restarts.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		fmt.Println("Starting scraper...")
-		// TODO: Wire to app.RunScraper()
-		return nil
+		sel, err := scraperSelection(cmd, args)
+		if err != nil {
+			return err
+		}
+
+		a, err := startApp()
+		if err != nil {
+			return err
+		}
+		defer a.Shutdown()
+		a.SetRetryFailed(accessors.GetRetryFailed(cmd))
+		a.SetQuality(accessors.GetQuality(cmd))
+
+		s := scraper.New(a.Logger(), accessors.GetAction(cmd), accessors.GetPostsAreas(cmd))
+		s.SetUsers(sel)
+		s.SetAfter(accessors.GetAfterDate(cmd))
+		s.SetMediaSort(accessors.GetMediaSort(cmd))
+		if accessors.GetDaemon(cmd) {
+			return s.RunDaemon(a.Context(), a, scraperSchedule(cmd))
+		}
+		return s.Run(a.Context(), a)
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(scraperCmd)
+	bundles.RegisterMainBundle(scraperCmd)
+}
+
+// scraperSelection builds the user selection from positional args,
+// --usernames, --user-list files, --excluded-users, and --blacklist files.
+func scraperSelection(cmd *cobra.Command, args []string) (scraper.UserSelection, error) {
+	users := commandUsernames(cmd, args)
+	listed, err := readListFiles(accessors.GetUserList(cmd))
+	if err != nil {
+		return scraper.UserSelection{}, err
+	}
+	users = append(users, utils.ParseUsernames(listed)...)
+
+	excluded := append([]string{}, accessors.GetExcludedUsers(cmd)...)
+	blocked, err := readListFiles(accessors.GetBlacklist(cmd))
+	if err != nil {
+		return scraper.UserSelection{}, err
+	}
+	excluded = append(excluded, blocked...)
+
+	return scraper.UserSelection{
+		Usernames: users,
+		Excluded:  utils.ParseUsernames(excluded),
+	}, nil
+}
+
+// scraperSchedule builds the daemon schedule from the schedule flags.
+func scraperSchedule(cmd *cobra.Command) scraper.ScheduleConfig {
+	return scraper.ScheduleConfig{
+		Interval:   accessors.GetInterval(cmd),
+		Cron:       accessors.GetCron(cmd),
+		StartTime:  accessors.GetStartTime(cmd),
+		QuietHours: accessors.GetQuietHours(cmd),
+		Jitter:     accessors.GetJitter(cmd),
+		MaxRuns:    accessors.GetMaxRuns(cmd),
+	}
+}
 
-	// Scraper-specific flags.
-	scraperCmd.Flags().StringSliceP("action", "a", []string{"download"}, "Actions to perform (download, like, unlike)")
-	scraperCmd.Flags().StringSliceP("posts", "o", nil, "Content areas (timeline, messages, archived, etc.)")
-	scraperCmd.Flags().StringSliceP("users", "u", nil, "Usernames to process")
-	scraperCmd.Flags().StringSlice("excluded-users", nil, "Usernames to exclude")
-	scraperCmd.Flags().BoolP("daemon", "d", false, "Run in daemon mode")
+// readListFiles reads and concatenates every list file in paths.
+func readListFiles(paths []string) ([]string, error) {
+	var entries []string
+	for _, path := range paths {
+		lines, err := readListFile(path)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, lines...)
+	}
+	return entries, nil
 }
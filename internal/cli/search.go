@@ -0,0 +1,104 @@
+// =============================================================================
+// FILE: internal/cli/search.go
+// PURPOSE: Search subcommand. Full-text search over the archived post and
+//          message text of one or all model databases.
+// =============================================================================
+
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"gofscraper/internal/cli/callbacks"
+	"gofscraper/internal/cli/flags"
+	"gofscraper/internal/commands"
+	"gofscraper/internal/db"
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search QUERY [user...]",
+	Short: "Search archived post and message text",
+	Long: `Searches the text of archived posts, messages, stories, and other post-shaped
+items of the given models (all models when none given). QUERY uses SQLite FTS5
+syntax: words match in any order, "quoted phrases" match exactly, prefix* matches
+word starts, and AND, OR, and NOT combine terms.
+
+Output is a table with the matched terms in [brackets] (default), JSON with each
+hit's full text and downloaded files, or the paths of the downloaded media of the
+matching posts, one per line.`,
+	Example: `  gofscraper search beach
+  gofscraper search '"red dress" OR swim*' alice --after 2024-01-01
+  gofscraper search sunset --area messages --min-price 5 --format paths`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		areas, _ := cmd.Flags().GetStringSlice("area")
+		after, _ := cmd.Flags().GetString("after")
+		before, _ := cmd.Flags().GetString("before")
+		minPrice, _ := cmd.Flags().GetFloat64("min-price")
+		maxPrice, _ := cmd.Flags().GetFloat64("max-price")
+		free, _ := cmd.Flags().GetBool("free")
+		limit, _ := cmd.Flags().GetInt("limit")
+		format, _ := cmd.Flags().GetString("format")
+
+		for i, area := range areas {
+			if err := callbacks.ValidateChoice(area, db.SearchAreas()); err != nil {
+				return fmt.Errorf("--area: %w", err)
+			}
+			areas[i] = strings.ToLower(strings.TrimSpace(area))
+		}
+		if err := callbacks.ValidateChoice(format, commands.SearchFormats); err != nil {
+			return fmt.Errorf("--format: %w", err)
+		}
+		if after != "" {
+			t, err := flags.ParseDate(after)
+			if err != nil {
+				return fmt.Errorf("--after: %w", err)
+			}
+			after = t.Format("2006-01-02")
+		}
+		if before != "" {
+			t, err := flags.ParseDate(before)
+			if err != nil {
+				return fmt.Errorf("--before: %w", err)
+			}
+			before = t.Format("2006-01-02")
+		}
+
+		a, err := startApp()
+		if err != nil {
+			return err
+		}
+		defer a.Shutdown()
+
+		s := commands.NewSearchCommand(a.Logger())
+		s.SetQuery(db.SearchQuery{
+			Match:          args[0],
+			Areas:          areas,
+			After:          after,
+			Before:         before,
+			MinPrice:       minPrice,
+			MaxPrice:       maxPrice,
+			FreeOnly:       free,
+			Limit:          limit,
+			HighlightStart: "[",
+			HighlightEnd:   "]",
+		})
+		s.SetFormat(strings.ToLower(format))
+		return s.Run(a.Context(), a, args[1:])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(searchCmd)
+	searchCmd.Flags().StringSlice("area", nil, "Restrict to areas: "+strings.Join(db.SearchAreas(), ", "))
+	searchCmd.Flags().String("after", "", "Only posts created on or after this date (YYYY-MM-DD)")
+	searchCmd.Flags().String("before", "", "Only posts created before this date (YYYY-MM-DD)")
+	searchCmd.Flags().Float64("min-price", 0, "Only posts priced at least this much")
+	searchCmd.Flags().Float64("max-price", 0, "Only posts priced at most this much (0 = no limit)")
+	searchCmd.Flags().Bool("free", false, "Only free posts")
+	searchCmd.Flags().Int("limit", 50, "Maximum number of hits (0 = no limit)")
+	searchCmd.Flags().String("format", commands.SearchFormatTable, "Output format: table, json, or paths")
+}
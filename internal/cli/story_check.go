@@ -7,20 +7,21 @@
 package cli
 
 import (
-	"fmt"
-
 	"github.com/spf13/cobra"
+
+	"gofscraper/internal/cli/bundles"
+	"gofscraper/internal/commands"
 )
 
 var storyCheckCmd = &cobra.Command{
 	Use:   "story_check",
 	Short: "Check and list stories",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		fmt.Println("Story check mode...")
-		return nil
+		return runCheck(cmd, args, commands.CheckStories)
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(storyCheckCmd)
+	bundles.RegisterStoryCheckBundle(storyCheckCmd)
 }
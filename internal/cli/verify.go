@@ -0,0 +1,40 @@
+// =============================================================================
+// FILE: internal/cli/verify.go
+// PURPOSE: Verify subcommand. Re-hashes downloaded files and reports
+//          missing, truncated, modified, and unknown files per model.
+// =============================================================================
+
+package cli
+
+import (
+	"github.com/spf13/cobra"
+
+	"gofscraper/internal/commands"
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify [user...]",
+	Short: "Check downloaded files against their recorded size and hash",
+	Long: `Re-hashes every downloaded file under the save location and compares it
+with the size and hash recorded in the model's database (all models when none
+given). Reports missing, truncated, and modified files, and files on disk the
+database does not know. Exits non-zero when any problem is found.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		record, _ := cmd.Flags().GetBool("record-hashes")
+
+		a, err := startApp()
+		if err != nil {
+			return err
+		}
+		defer a.Shutdown()
+
+		v := commands.NewVerifyCommand(a.Logger())
+		v.SetRecordHashes(record)
+		return v.Run(a.Context(), a, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(verifyCmd)
+	verifyCmd.Flags().Bool("record-hashes", false, "Store hashes for downloaded files that have none recorded")
+}
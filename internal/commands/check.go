@@ -12,9 +12,16 @@ import (
 	"fmt"
 	"log/slog"
 	"strings"
+	"time"
 
+	"gofscraper/internal/api"
 	"gofscraper/internal/app"
 	cmdutils "gofscraper/internal/commands/utils"
+	"gofscraper/internal/db"
+	"gofscraper/internal/model"
+	"gofscraper/internal/paths"
+	"gofscraper/internal/tui"
+	"gofscraper/internal/tui/sections"
 )
 
 // ---------------------------------------------------------------------------
@@ -43,9 +50,17 @@ func AllCheckTypes() []CheckType {
 // CheckCommand runs check operations that fetch and display content summaries.
 type CheckCommand struct {
 	cmdutils.CommandBase
-	checkType CheckType
+	checkType   CheckType
+	areas       []string  // Areas for post_check (timeline, pinned, archived, ...)
+	after       time.Time // Date floor; zero fetches full history
+	sortKey     string    // Table column to sort by
+	sortDesc    bool
+	interactive bool // Open results in the triage TUI instead of printing
 }
 
+// DefaultPostCheckAreas are the areas post_check covers when none are given.
+var DefaultPostCheckAreas = []string{"timeline", "pinned", "archived", "streams"}
+
 // NewCheckCommand creates a CheckCommand for the given check type.
 //
 // Parameters:
@@ -58,7 +73,36 @@ func NewCheckCommand(logger *slog.Logger, checkType CheckType) *CheckCommand {
 	return &CheckCommand{
 		CommandBase: cmdutils.NewCommandBase(logger),
 		checkType:   checkType,
+		areas:       DefaultPostCheckAreas,
+		sortKey:     "date",
+	}
+}
+
+// SetAreas sets the content areas fetched by post_check. Other check types
+// have a fixed area and ignore this.
+func (c *CheckCommand) SetAreas(areas []string) {
+	if len(areas) > 0 {
+		c.areas = areas
+	}
+}
+
+// SetAfter sets the date floor; content older than after is not fetched.
+func (c *CheckCommand) SetAfter(after time.Time) {
+	c.after = after
+}
+
+// SetSort sets the table column and direction used to order results.
+func (c *CheckCommand) SetSort(key string, desc bool) {
+	if key != "" {
+		c.sortKey = key
 	}
+	c.sortDesc = desc
+}
+
+// SetInteractive opens each user's results in the interactive triage table,
+// where rows can be filtered, marked, and queued for download.
+func (c *CheckCommand) SetInteractive(interactive bool) {
+	c.interactive = interactive
 }
 
 // Name returns the command name.
@@ -95,6 +139,12 @@ func (c *CheckCommand) Run(ctx context.Context, a *app.App, usernames []string)
 			continue
 		}
 
+		if c.interactive {
+			if err := c.runTable(ctx, a, username, results); err != nil {
+				return fmt.Errorf("check table: %w", err)
+			}
+			continue
+		}
 		c.printTable(username, results)
 	}
 
@@ -107,51 +157,214 @@ func (c *CheckCommand) Run(ctx context.Context, a *app.App, usernames []string)
 
 // CheckResult represents one item in the check results.
 type CheckResult struct {
-	ID       int64
-	Date     string
-	Text     string
-	Price    float64
-	HasMedia bool
-	Paid     bool
+	ID         int64
+	Date       string
+	Area       string
+	Text       string
+	Price      float64
+	HasMedia   bool
+	Paid       bool
+	MediaCount int         // Viewable media items on the post
+	MediaTypes []string    // Distinct media types: images, videos, audios
+	Downloaded int         // Media items already marked downloaded in the model's DB
+	Post       *model.Post // Source post, used to queue its media for download
 }
 
 // fetchCheckData retrieves check data for a user based on the check type.
 func (c *CheckCommand) fetchCheckData(ctx context.Context, a *app.App, username string) ([]CheckResult, error) {
-	_ = a.Session() // Will be used when API is wired.
+	client := api.NewClient(a.Session())
+
+	user, err := client.GetProfile(ctx, username)
+	if err != nil {
+		return nil, fmt.Errorf("lookup %s: %w", username, err)
+	}
 
-	// TODO: Wire to appropriate API endpoint based on c.checkType.
+	var posts []model.Post
 	switch c.checkType {
 	case CheckMessages:
-		return c.fetchMessages(ctx, username)
+		posts, err = c.fetchMessages(ctx, client, user.ID)
 	case CheckStories:
-		return c.fetchStories(ctx, username)
+		posts, err = c.fetchStories(ctx, client, user.ID)
 	case CheckPaid:
-		return c.fetchPaid(ctx, username)
+		posts, err = c.fetchPaid(ctx, client, user.ID)
 	case CheckPosts:
-		return c.fetchPosts(ctx, username)
+		posts, err = c.fetchPosts(ctx, client, user.ID)
 	default:
 		return nil, fmt.Errorf("unknown check type: %s", c.checkType)
 	}
+	if err != nil && len(posts) == 0 {
+		return nil, err
+	}
+	if err != nil {
+		c.Logger.Warn("check data incomplete", "user", username, "type", c.checkType, "error", err)
+	}
+
+	return c.buildResults(ctx, username, posts), nil
+}
+
+func (c *CheckCommand) fetchMessages(ctx context.Context, client *api.Client, modelID int64) ([]model.Post, error) {
+	return app.FetchAreaPosts(ctx, client, "messages", modelID, c.after)
+}
+
+func (c *CheckCommand) fetchStories(ctx context.Context, client *api.Client, modelID int64) ([]model.Post, error) {
+	return c.fetchAreas(ctx, client, modelID, []string{"stories", "highlights"})
+}
+
+func (c *CheckCommand) fetchPaid(ctx context.Context, client *api.Client, modelID int64) ([]model.Post, error) {
+	return app.FetchAreaPosts(ctx, client, "purchased", modelID, c.after)
+}
+
+func (c *CheckCommand) fetchPosts(ctx context.Context, client *api.Client, modelID int64) ([]model.Post, error) {
+	return c.fetchAreas(ctx, client, modelID, c.areas)
+}
+
+// fetchAreas fetches several areas and de-duplicates posts that appear in
+// more than one (pinned posts are also timeline posts).
+func (c *CheckCommand) fetchAreas(ctx context.Context, client *api.Client, modelID int64, areas []string) ([]model.Post, error) {
+	var all []model.Post
+	seen := make(map[int64]bool)
+	for _, area := range areas {
+		posts, err := app.FetchAreaPosts(ctx, client, area, modelID, c.after)
+		if err != nil {
+			return all, err
+		}
+		for _, p := range posts {
+			if seen[p.ID] {
+				continue
+			}
+			seen[p.ID] = true
+			all = append(all, p)
+		}
+	}
+	return all, nil
+}
+
+// buildResults converts posts into check rows, marking how much of each
+// post's media is already downloaded according to the model's database.
+func (c *CheckCommand) buildResults(ctx context.Context, username string, posts []model.Post) []CheckResult {
+	conn := c.openModelDB(username)
+	if conn != nil {
+		defer db.Close(username)
+	}
+
+	results := make([]CheckResult, 0, len(posts))
+	for i := range posts {
+		p := &posts[i]
+		p.Username = username
+		p.LinkMedia()
+		media := p.ViewableMedia()
+		r := CheckResult{
+			ID:         p.ID,
+			Date:       p.FormattedDate(),
+			Area:       string(p.ResponseType),
+			Text:       p.DBText(true),
+			Price:      p.Price,
+			HasMedia:   len(media) > 0,
+			Paid:       p.Paid,
+			MediaCount: len(media),
+			MediaTypes: mediaTypes(media),
+			Downloaded: c.downloadedCount(ctx, conn, p.ID),
+			Post:       p,
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+// downloadedCount returns how many of a post's media items are marked
+// downloaded in the model's database. A nil conn counts as none.
+func (c *CheckCommand) downloadedCount(ctx context.Context, conn *db.Conn, postID int64) int {
+	if conn == nil {
+		return 0
+	}
+	rows, err := db.GetMediaByPostID(ctx, conn, postID)
+	if err != nil {
+		c.Logger.Debug("media lookup failed", "user", conn.Username, "post_id", postID, "error", err)
+	}
+	count := 0
+	for _, row := range rows {
+		if row.Downloaded {
+			count++
+		}
+	}
+	return count
 }
 
-func (c *CheckCommand) fetchMessages(_ context.Context, _ string) ([]CheckResult, error) {
-	// TODO: Wire to messages API.
-	return nil, nil
+// mediaTypes returns the distinct media type names in media, in order of
+// first appearance.
+func mediaTypes(media []*model.Media) []string {
+	var types []string
+	seen := make(map[model.MediaType]bool)
+	for _, m := range media {
+		t := m.MediaType()
+		if !seen[t] {
+			seen[t] = true
+			types = append(types, string(t))
+		}
+	}
+	return types
+}
+
+// openModelDB opens the model's database if one exists. A check never
+// creates a database, so a model that was never scraped yields nil. The
+// caller releases a non-nil connection with db.Close.
+func (c *CheckCommand) openModelDB(username string) *db.Conn {
+	dbPath := paths.DBPath(username)
+	if !paths.Exists(dbPath) {
+		return nil
+	}
+	conn, err := db.Open(username, dbPath)
+	if err != nil {
+		c.Logger.Warn("failed to open model database", "user", username, "error", err)
+		return nil
+	}
+	return conn
 }
 
-func (c *CheckCommand) fetchStories(_ context.Context, _ string) ([]CheckResult, error) {
-	// TODO: Wire to stories API.
-	return nil, nil
+// ---------------------------------------------------------------------------
+// Table output
+// ---------------------------------------------------------------------------
+
+// CheckColumns are the columns of the check results table.
+var CheckColumns = []sections.Column{
+	{Key: "id", Title: "ID", Width: 12},
+	{Key: "date", Title: "Date", Width: 19},
+	{Key: "area", Title: "Area", Width: 10},
+	{Key: "price", Title: "Price", Width: 8},
+	{Key: "media", Title: "Media", Width: 5},
+	{Key: "downloaded", Title: "Downloaded", Width: 10},
+	{Key: "text", Title: "Text", Width: 50},
 }
 
-func (c *CheckCommand) fetchPaid(_ context.Context, _ string) ([]CheckResult, error) {
-	// TODO: Wire to paid content API.
-	return nil, nil
+// CheckRows converts check results into table rows keyed by CheckColumns.
+func CheckRows(results []CheckResult) []sections.Row {
+	rows := make([]sections.Row, 0, len(results))
+	for _, r := range results {
+		rows = append(rows, sections.Row{
+			"id":          fmt.Sprintf("%d", r.ID),
+			"date":        r.Date,
+			"area":        r.Area,
+			"price":       fmt.Sprintf("$%.2f", r.Price),
+			"media":       fmt.Sprintf("%d", r.MediaCount),
+			"media_types": strings.Join(r.MediaTypes, ","),
+			"downloaded":  fmt.Sprintf("%d", r.Downloaded),
+			"text":        r.Text,
+		})
+	}
+	return rows
 }
 
-func (c *CheckCommand) fetchPosts(_ context.Context, _ string) ([]CheckResult, error) {
-	// TODO: Wire to posts API.
-	return nil, nil
+// newCheckTable builds a sorted table section from check results.
+func (c *CheckCommand) newCheckTable(results []CheckResult) *sections.TableSection {
+	table := sections.NewTableSection(CheckColumns)
+	table.SetRows(CheckRows(results))
+
+	order := sections.SortAsc
+	if c.sortDesc {
+		order = sections.SortDesc
+	}
+	table.SortBy(c.sortKey, order)
+	return table
 }
 
 // printTable displays check results in a formatted table.
@@ -163,20 +376,62 @@ func (c *CheckCommand) printTable(username string, results []CheckResult) {
 
 	c.Logger.Info(fmt.Sprintf("Check results for %s (%s): %d items", username, c.checkType, len(results)))
 
-	header := fmt.Sprintf("%-12s %-20s %-8s %-8s %-50s", "ID", "Date", "Price", "Media", "Text")
-	c.Logger.Info(header)
-	c.Logger.Info(strings.Repeat("-", 100))
+	table := c.newCheckTable(results)
+	console := sections.NewConsoleSection(CheckColumns)
+	console.SetRows(table.Rows())
+	console.Print()
+}
 
-	for _, r := range results {
-		media := "no"
-		if r.HasMedia {
-			media = "yes"
+// ---------------------------------------------------------------------------
+// Interactive table
+// ---------------------------------------------------------------------------
+
+// runTable opens check results in the triage TUI. Marked rows are downloaded
+// through the app's download orchestrator and their downloaded counts are
+// refreshed from the model database.
+func (c *CheckCommand) runTable(ctx context.Context, a *app.App, username string, results []CheckResult) error {
+	if len(results) == 0 {
+		c.Logger.Info("no results found", "user", username, "type", c.checkType)
+		return nil
+	}
+
+	byID := make(map[string]*CheckResult, len(results))
+	for i := range results {
+		byID[fmt.Sprintf("%d", results[i].ID)] = &results[i]
+	}
+
+	download := func(ctx context.Context, rows []sections.Row) (string, map[string]sections.Row, error) {
+		var media []*model.Media
+		for _, row := range rows {
+			if r, ok := byID[row[tui.KeyID]]; ok && r.Post != nil {
+				media = append(media, r.Post.ViewableMedia()...)
+			}
+		}
+		if len(media) == 0 {
+			return "marked posts have no downloadable media", nil, nil
 		}
-		text := r.Text
-		if len(text) > 47 {
-			text = text[:47] + "..."
+
+		result, err := a.DownloadMedia(ctx, username, media)
+		if err != nil {
+			return "", nil, err
 		}
-		line := fmt.Sprintf("%-12d %-20s $%-7.2f %-8s %-50s", r.ID, r.Date, r.Price, media, text)
-		c.Logger.Info(line)
+
+		conn := c.openModelDB(username)
+		if conn != nil {
+			defer db.Close(username)
+		}
+		updates := make(map[string]sections.Row, len(rows))
+		for _, row := range rows {
+			r, ok := byID[row[tui.KeyID]]
+			if !ok {
+				continue
+			}
+			r.Downloaded = c.downloadedCount(ctx, conn, r.ID)
+			updates[row[tui.KeyID]] = sections.Row{tui.KeyDownloaded: fmt.Sprintf("%d", r.Downloaded)}
+		}
+		return result.Summary(), updates, nil
 	}
+
+	title := fmt.Sprintf("%s: %s (%d items)", c.checkType, username, len(results))
+	return tui.RunTable(ctx, title, CheckColumns, c.newCheckTable(results).Rows(), download, a.Bandwidth())
 }
@@ -1,19 +1,28 @@
 // =============================================================================
 // FILE: internal/commands/db.go
-// PURPOSE: Database management command. Provides backup and merge operations
-//          for model databases. Ports Python runner/db.py.
+// PURPOSE: Database management command. Provides backup, merge, diff, stats,
+//          restore, migrate, rebase, consolidate, and legacy import
+//          operations for model databases. Ports Python runner/db.py.
 // =============================================================================
 
 package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 
 	"gofscraper/internal/app"
 	cmdutils "gofscraper/internal/commands/utils"
 	"gofscraper/internal/db"
+	"gofscraper/internal/download/progress"
+	"gofscraper/internal/paths"
+	"gofscraper/internal/tui/sections"
 )
 
 // ---------------------------------------------------------------------------
@@ -24,10 +33,29 @@ import (
 type DBOperation string
 
 const (
-	DBOpBackup DBOperation = "backup"
-	DBOpMerge  DBOperation = "merge"
+	DBOpBackup  DBOperation = "backup"
+	DBOpMerge   DBOperation = "merge"
+	DBOpDiff    DBOperation = "diff"
+	DBOpStats   DBOperation = "stats"
+	DBOpRestore DBOperation = "restore"
+	DBOpMigrate DBOperation = "migrate"
+	DBOpImport  DBOperation = "import-legacy"
+	DBOpRebase  DBOperation = "rebase"
+
+	DBOpConsolidate DBOperation = "consolidate"
 )
 
+// diffTables lists the tables compared by diff, with their unique ID column.
+var diffTables = []struct {
+	table string
+	idCol string
+}{
+	{"posts", "post_id"},
+	{"messages", "post_id"},
+	{"stories", "post_id"},
+	{"medias", "media_id"},
+}
+
 // ---------------------------------------------------------------------------
 // DBCommand
 // ---------------------------------------------------------------------------
@@ -35,7 +63,10 @@ const (
 // DBCommand handles database management operations.
 type DBCommand struct {
 	cmdutils.CommandBase
-	operation DBOperation
+	operation  DBOperation
+	dryRun     bool
+	rebaseFrom string
+	rebaseTo   string
 }
 
 // NewDBCommand creates a DBCommand for the given operation.
@@ -53,6 +84,19 @@ func NewDBCommand(logger *slog.Logger, operation DBOperation) *DBCommand {
 	}
 }
 
+// SetDryRun makes migrate print the pending SQL instead of applying it,
+// rebase count the affected rows instead of rewriting them, and
+// consolidate list the models it would fold.
+func (d *DBCommand) SetDryRun(dryRun bool) {
+	d.dryRun = dryRun
+}
+
+// SetRebase sets the old and new path prefixes for rebase.
+func (d *DBCommand) SetRebase(from, to string) {
+	d.rebaseFrom = from
+	d.rebaseTo = to
+}
+
 // Name returns the command name.
 func (d *DBCommand) Name() string {
 	return fmt.Sprintf("db_%s", d.operation)
@@ -63,7 +107,17 @@ func (d *DBCommand) Name() string {
 // Parameters:
 //   - ctx: Context for cancellation.
 //   - a: The application instance providing config.
-//   - args: Command arguments (usernames for backup, source+dest for merge).
+//   - args: Command arguments. Each database argument is a model username
+//     or a path to a .db file:
+//   - backup, stats, migrate, rebase: databases to process (all models
+//     when empty).
+//   - merge, diff: source and destination.
+//   - restore: a username and, optionally, the backup file to restore
+//     (the newest backup when omitted).
+//   - import-legacy: a Python OF-Scraper database file and, optionally,
+//     the destination (inferred from the file's directory when omitted).
+//   - consolidate: models whose own database to fold into the
+//     consolidated database (every per-model database when empty).
 //
 // Returns:
 //   - Error if the operation fails.
@@ -76,66 +130,202 @@ func (d *DBCommand) Run(ctx context.Context, a *app.App, args []string) error {
 		return d.runBackup(ctx, a, args)
 	case DBOpMerge:
 		return d.runMerge(ctx, a, args)
+	case DBOpDiff:
+		return d.runDiff(ctx, a, args)
+	case DBOpStats:
+		return d.runStats(ctx, a, args)
+	case DBOpRestore:
+		return d.runRestore(ctx, a, args)
+	case DBOpMigrate:
+		return d.runMigrate(ctx, a, args)
+	case DBOpImport:
+		return d.runImportLegacy(ctx, a, args)
+	case DBOpRebase:
+		return d.runRebase(ctx, a, args)
+	case DBOpConsolidate:
+		return d.runConsolidate(ctx, a, args)
 	default:
 		return fmt.Errorf("unknown db operation: %s", d.operation)
 	}
 }
 
-// runBackup creates backups for the specified model databases.
-func (d *DBCommand) runBackup(_ context.Context, _ *app.App, usernames []string) error {
-	if len(usernames) == 0 {
-		return fmt.Errorf("no usernames specified for backup")
+// ---------------------------------------------------------------------------
+// Database resolution
+// ---------------------------------------------------------------------------
+
+// resolveDBPath maps a username or .db file path to a pool key and path.
+func resolveDBPath(arg string) (key, dbPath string) {
+	if filepath.Ext(arg) == ".db" && paths.Exists(arg) {
+		abs, err := filepath.Abs(arg)
+		if err != nil {
+			abs = arg
+		}
+		return abs, abs
 	}
+	return arg, paths.DBPath(arg)
+}
 
-	var succeeded, failed int
-	for _, username := range usernames {
-		conn := db.GetConn(username)
-		if conn == nil {
-			d.Logger.Warn("no open database for user", "user", username)
-			failed++
-			continue
+// openExisting opens a database by username or path without creating it.
+func openExisting(arg string) (*db.Conn, error) {
+	key, dbPath := resolveDBPath(arg)
+	if !paths.Exists(dbPath) {
+		return nil, fmt.Errorf("no database for %q at %s", arg, dbPath)
+	}
+	conn, err := db.Open(key, dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", dbPath, err)
+	}
+	return conn, nil
+}
+
+// targetDBs returns the databases named by args, or every model database
+// under the save location when args is empty, keyed by name. Once the
+// consolidated database exists, every model registered in it is returned
+// instead, each mapped to the shared file.
+func targetDBs(ctx context.Context, args []string) (map[string]string, error) {
+	if len(args) == 0 {
+		if path, ok := paths.ConsolidatedDBPath(); ok && paths.Exists(path) {
+			return consolidatedModels(ctx, path)
+		}
+		all, err := paths.AllDBPaths()
+		if err != nil {
+			return nil, fmt.Errorf("scan databases: %w", err)
+		}
+		return all, nil
+	}
+
+	targets := make(map[string]string, len(args))
+	for _, arg := range args {
+		_, dbPath := resolveDBPath(arg)
+		if !paths.Exists(dbPath) {
+			return nil, fmt.Errorf("no database for %q at %s", arg, dbPath)
+		}
+		targets[arg] = dbPath
+	}
+	return targets, nil
+}
+
+// consolidatedModels maps every model registered in the consolidated
+// database at dbPath to it.
+func consolidatedModels(ctx context.Context, dbPath string) (map[string]string, error) {
+	conn, err := db.Open(dbPath, dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", dbPath, err)
+	}
+	models, err := db.ListModels(ctx, conn)
+	if err != nil {
+		return nil, fmt.Errorf("list models: %w", err)
+	}
+	targets := make(map[string]string, len(models))
+	for _, m := range models {
+		if m.Username != "" {
+			targets[m.Username] = dbPath
 		}
+	}
+	return targets, nil
+}
 
-		backupPath, err := db.Backup(conn.Path)
+// distinctFiles returns the sorted keys of targets, keeping only the first
+// key of each file so a consolidated database is processed once.
+func distinctFiles(targets map[string]string) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, name := range sortedKeys(targets) {
+		if !seen[targets[name]] {
+			seen[targets[name]] = true
+			keys = append(keys, name)
+		}
+	}
+	return keys
+}
+
+// modelDir returns the directory holding the downloads a connection's rows
+// describe: the database's own directory for a per-model file, the model's
+// directory under the save location for a model of the consolidated
+// database, or the whole save location for a connection spanning it.
+func modelDir(conn *db.Conn) string {
+	switch {
+	case conn.Partitioned:
+		return paths.ModelDir(conn.Username)
+	case db.IsConsolidated(conn):
+		return paths.SaveLocation()
+	}
+	return filepath.Dir(conn.Path)
+}
+
+// sortedKeys returns the keys of m in sorted order.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ---------------------------------------------------------------------------
+// Backup
+// ---------------------------------------------------------------------------
+
+// runBackup creates backups for the specified model databases.
+func (d *DBCommand) runBackup(ctx context.Context, _ *app.App, args []string) error {
+	targets, err := targetDBs(ctx, args)
+	if err != nil {
+		return err
+	}
+	if len(targets) == 0 {
+		return fmt.Errorf("no databases found to back up")
+	}
+
+	var succeeded int
+	var errs []error
+	for _, name := range distinctFiles(targets) {
+		backupPath, err := db.Backup(targets[name])
 		if err != nil {
-			d.Logger.Error("backup failed", "user", username, "error", err)
-			failed++
+			d.Logger.Error("backup failed", "user", name, "error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
 			continue
 		}
 
-		d.Logger.Info("backup created", "user", username, "path", backupPath)
+		d.Logger.Info("backup created", "user", name, "path", backupPath)
 		succeeded++
 	}
 
 	d.Logger.Info("backup operation complete",
 		"succeeded", succeeded,
-		"failed", failed,
+		"failed", len(errs),
 	)
-	return nil
+	return errors.Join(errs...)
 }
 
+// ---------------------------------------------------------------------------
+// Merge
+// ---------------------------------------------------------------------------
+
 // runMerge merges one model's database into another.
 func (d *DBCommand) runMerge(ctx context.Context, _ *app.App, args []string) error {
 	if len(args) < 2 {
 		return fmt.Errorf("merge requires source and destination usernames")
 	}
 
-	srcUsername := args[0]
-	dstUsername := args[1]
-
-	srcConn := db.GetConn(srcUsername)
-	if srcConn == nil {
-		return fmt.Errorf("no open database for source user %q", srcUsername)
+	srcConn, err := openExisting(args[0])
+	if err != nil {
+		return fmt.Errorf("source: %w", err)
 	}
 
-	dstConn := db.GetConn(dstUsername)
-	if dstConn == nil {
-		return fmt.Errorf("no open database for destination user %q", dstUsername)
+	// The destination is created if it does not exist yet.
+	dstKey, dstPath := resolveDBPath(args[1])
+	dstConn, err := db.Open(dstKey, dstPath)
+	if err != nil {
+		return fmt.Errorf("destination: %w", err)
+	}
+	if srcConn.Path == dstConn.Path {
+		return fmt.Errorf("source and destination are the same database: %s", dstConn.Path)
 	}
 
 	d.Logger.Info("starting database merge",
-		"source", srcUsername,
-		"destination", dstUsername,
+		"source", srcConn.Path,
+		"destination", dstConn.Path,
 	)
 
 	result, err := db.MergeDatabases(ctx, srcConn, dstConn)
@@ -153,3 +343,522 @@ func (d *DBCommand) runMerge(ctx context.Context, _ *app.App, args []string) err
 
 	return nil
 }
+
+// ---------------------------------------------------------------------------
+// Diff
+// ---------------------------------------------------------------------------
+
+// diffColumns are the columns of the diff report.
+var diffColumns = []sections.Column{
+	{Key: "table", Title: "Table", Width: 10},
+	{Key: "both", Title: "In Both", Width: 8},
+	{Key: "src", Title: "Only Source", Width: 11},
+	{Key: "dst", Title: "Only Dest", Width: 11},
+	{Key: "sample", Title: "Sample IDs (source only)", Width: 40},
+}
+
+// runDiff compares two databases table by table.
+func (d *DBCommand) runDiff(ctx context.Context, _ *app.App, args []string) error {
+	if len(args) < 2 {
+		return fmt.Errorf("diff requires source and destination usernames")
+	}
+
+	srcConn, err := openExisting(args[0])
+	if err != nil {
+		return fmt.Errorf("source: %w", err)
+	}
+	dstConn, err := openExisting(args[1])
+	if err != nil {
+		return fmt.Errorf("destination: %w", err)
+	}
+
+	var rows []sections.Row
+	for _, t := range diffTables {
+		result, err := db.DiffTable(ctx, srcConn.DB, dstConn.DB, t.table, t.idCol)
+		if err != nil {
+			return fmt.Errorf("diff %s: %w", t.table, err)
+		}
+		rows = append(rows, sections.Row{
+			"table":  t.table,
+			"both":   fmt.Sprintf("%d", result.InBoth),
+			"src":    fmt.Sprintf("%d", len(result.OnlyInSource)),
+			"dst":    fmt.Sprintf("%d", len(result.OnlyInDest)),
+			"sample": sampleIDs(result.OnlyInSource, 5),
+		})
+	}
+
+	fmt.Printf("Diff %s -> %s\n", srcConn.Path, dstConn.Path)
+	console := sections.NewConsoleSection(diffColumns)
+	console.SetRows(rows)
+	console.Print()
+	return nil
+}
+
+// sampleIDs formats up to n IDs for display.
+func sampleIDs(ids []int64, n int) string {
+	if len(ids) == 0 {
+		return "-"
+	}
+	s := ""
+	for i, id := range ids {
+		if i == n {
+			return s + fmt.Sprintf(", ... (+%d)", len(ids)-n)
+		}
+		if i > 0 {
+			s += ", "
+		}
+		s += fmt.Sprintf("%d", id)
+	}
+	return s
+}
+
+// ---------------------------------------------------------------------------
+// Stats
+// ---------------------------------------------------------------------------
+
+// statsColumns are the columns of the stats report.
+var statsColumns = []sections.Column{
+	{Key: "user", Title: "User", Width: 20},
+	{Key: "posts", Title: "Posts", Width: 7},
+	{Key: "messages", Title: "Messages", Width: 8},
+	{Key: "stories", Title: "Stories", Width: 7},
+	{Key: "labels", Title: "Labels", Width: 6},
+	{Key: "media", Title: "Media", Width: 7},
+	{Key: "downloaded", Title: "Downloaded", Width: 10},
+	{Key: "failed", Title: "Failed", Width: 6},
+	{Key: "size", Title: "Size", Width: 10},
+}
+
+// runStats prints aggregate counts for each database.
+func (d *DBCommand) runStats(ctx context.Context, _ *app.App, args []string) error {
+	targets, err := targetDBs(ctx, args)
+	if err != nil {
+		return err
+	}
+	if len(targets) == 0 {
+		return fmt.Errorf("no databases found")
+	}
+
+	var rows []sections.Row
+	for _, name := range sortedKeys(targets) {
+		conn, err := db.Open(name, targets[name])
+		if err != nil {
+			d.Logger.Error("stats failed", "user", name, "error", err)
+			continue
+		}
+
+		s, err := db.GetStats(ctx, conn)
+		if err != nil {
+			d.Logger.Error("stats failed", "user", name, "error", err)
+			continue
+		}
+		failed, err := db.GetFailedMediaIDs(ctx, conn)
+		if err != nil {
+			d.Logger.Error("stats failed", "user", name, "error", err)
+			continue
+		}
+		rows = append(rows, sections.Row{
+			"user":       name,
+			"posts":      fmt.Sprintf("%d", s.PostCount),
+			"messages":   fmt.Sprintf("%d", s.MessageCount),
+			"stories":    fmt.Sprintf("%d", s.StoryCount),
+			"labels":     fmt.Sprintf("%d", s.LabelCount),
+			"media":      fmt.Sprintf("%d", s.MediaCount),
+			"downloaded": fmt.Sprintf("%d", s.Downloaded),
+			"failed":     fmt.Sprintf("%d", len(failed)),
+			"size":       progress.FormatBytes(s.TotalSize),
+		})
+	}
+
+	console := sections.NewConsoleSection(statsColumns)
+	console.SetRows(rows)
+	console.Print()
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// Restore
+// ---------------------------------------------------------------------------
+
+// runRestore replaces a model's database with one of its backups.
+func (d *DBCommand) runRestore(_ context.Context, _ *app.App, args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("restore requires a username")
+	}
+
+	key, dbPath := resolveDBPath(args[0])
+	backups, err := db.ListBackups(dbPath)
+	if err != nil {
+		return fmt.Errorf("list backups: %w", err)
+	}
+
+	var backupPath string
+	if len(args) > 1 {
+		backupPath = args[1]
+		if !paths.Exists(backupPath) {
+			// Allow a bare backup file name from the model's directory.
+			backupPath = filepath.Join(filepath.Dir(dbPath), args[1])
+		}
+	} else {
+		if len(backups) == 0 {
+			return fmt.Errorf("no backups found for %s", dbPath)
+		}
+		backupPath = backups[0]
+		d.Logger.Info("restoring newest backup", "backup", backupPath, "available", len(backups))
+	}
+
+	previous, err := db.Restore(key, dbPath, backupPath)
+	if err != nil {
+		return fmt.Errorf("restore failed: %w", err)
+	}
+
+	d.Logger.Info("restore complete",
+		"database", dbPath,
+		"from", backupPath,
+		"previous_saved_as", previous,
+	)
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// Migrate
+// ---------------------------------------------------------------------------
+
+// runMigrate brings databases up to the current schema version, or with
+// dry-run prints the SQL each would run.
+func (d *DBCommand) runMigrate(ctx context.Context, _ *app.App, args []string) error {
+	targets, err := targetDBs(ctx, args)
+	if err != nil {
+		return err
+	}
+	if len(targets) == 0 {
+		return fmt.Errorf("no databases found to migrate")
+	}
+
+	var failed int
+	for _, name := range distinctFiles(targets) {
+		dbPath := targets[name]
+		version, pending, err := db.PlanMigrations(dbPath)
+		if err != nil {
+			d.Logger.Error("migration check failed", "user", name, "error", err)
+			failed++
+			continue
+		}
+		if len(pending) == 0 {
+			d.Logger.Info("database is up to date", "user", name, "version", version)
+			continue
+		}
+
+		if d.dryRun {
+			fmt.Printf("-- %s: %s (v%d -> v%d)\n", name, dbPath, version, db.CurrentSchemaVersion())
+			if err := db.WriteMigrationSQL(os.Stdout, pending); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// A pooled connection for name would hide the pending steps.
+		if err := db.Migrate(dbPath); err != nil {
+			d.Logger.Error("migration failed", "user", name, "error", err)
+			failed++
+			continue
+		}
+		d.Logger.Info("database migrated", "user", name,
+			"from", version,
+			"to", db.CurrentSchemaVersion(),
+		)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d database(s) failed to migrate", failed)
+	}
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// Rebase
+// ---------------------------------------------------------------------------
+
+// rebaseColumns are the columns of the rebase report.
+var rebaseColumns = []sections.Column{
+	{Key: "user", Title: "User", Width: 20},
+	{Key: "media", Title: "Media Rows", Width: 10},
+	{Key: "queued", Title: "Queued Downloads", Width: 16},
+}
+
+// runRebase rewrites the recorded path prefix in each database, or with
+// dry-run counts the rows that would change.
+func (d *DBCommand) runRebase(ctx context.Context, _ *app.App, args []string) error {
+	if d.rebaseFrom == "" || d.rebaseTo == "" {
+		return fmt.Errorf("rebase requires --from and --to")
+	}
+	targets, err := targetDBs(ctx, args)
+	if err != nil {
+		return err
+	}
+	if len(targets) == 0 {
+		return fmt.Errorf("no databases found to rebase")
+	}
+
+	var rows []sections.Row
+	var failed int
+	for _, name := range sortedKeys(targets) {
+		conn, err := db.Open(name, targets[name])
+		if err != nil {
+			d.Logger.Error("rebase failed", "user", name, "error", err)
+			failed++
+			continue
+		}
+		result, err := db.RebasePaths(ctx, conn, d.rebaseFrom, d.rebaseTo, d.dryRun)
+		if err != nil {
+			d.Logger.Error("rebase failed", "user", name, "error", err)
+			failed++
+			continue
+		}
+		rows = append(rows, sections.Row{
+			"user":   name,
+			"media":  fmt.Sprintf("%d", result.Media),
+			"queued": fmt.Sprintf("%d", result.Queued),
+		})
+	}
+
+	if d.dryRun {
+		fmt.Printf("Dry run: rows under %s that would move to %s\n", d.rebaseFrom, d.rebaseTo)
+	} else {
+		fmt.Printf("Rebased %s -> %s\n", d.rebaseFrom, d.rebaseTo)
+	}
+	console := sections.NewConsoleSection(rebaseColumns)
+	console.SetRows(rows)
+	console.Print()
+
+	if failed > 0 {
+		return fmt.Errorf("%d database(s) failed to rebase", failed)
+	}
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// Consolidate
+// ---------------------------------------------------------------------------
+
+// consolidateColumns are the columns of the consolidate report.
+var consolidateColumns = []sections.Column{
+	{Key: "user", Title: "User", Width: 20},
+	{Key: "model_id", Title: "Model ID", Width: 12},
+	{Key: "posts", Title: "Posts", Width: 7},
+	{Key: "messages", Title: "Messages", Width: 8},
+	{Key: "stories", Title: "Stories", Width: 7},
+	{Key: "media", Title: "Media", Width: 7},
+	{Key: "other", Title: "Other Rows", Width: 10},
+	{Key: "status", Title: "Status", Width: 30},
+}
+
+// runConsolidate folds per-model databases into the consolidated database
+// the metadata template selects. The per-model files are left in place.
+func (d *DBCommand) runConsolidate(ctx context.Context, _ *app.App, args []string) error {
+	dstPath, ok := paths.ConsolidatedDBPath()
+	if !ok {
+		return fmt.Errorf("the metadata template names a per-model placeholder; set it to a shared path such as {configpath}/{profile}/.data/user_data.db")
+	}
+
+	sources := make(map[string]string)
+	if len(args) == 0 {
+		all, err := paths.AllDBPaths()
+		if err != nil {
+			return fmt.Errorf("scan databases: %w", err)
+		}
+		sources = all
+	}
+	for _, arg := range args {
+		srcPath := paths.ModelDBPath(arg)
+		if !paths.Exists(srcPath) {
+			return fmt.Errorf("no database for %q at %s", arg, srcPath)
+		}
+		sources[arg] = srcPath
+	}
+	for name, srcPath := range sources {
+		if srcPath == dstPath {
+			delete(sources, name)
+		}
+	}
+	if len(sources) == 0 {
+		return fmt.Errorf("no per-model databases found to consolidate")
+	}
+
+	var dstConn *db.Conn
+	if !d.dryRun {
+		if paths.Exists(dstPath) {
+			backupPath, err := db.Backup(dstPath)
+			if err != nil {
+				return fmt.Errorf("backup %s: %w", dstPath, err)
+			}
+			d.Logger.Info("backed up consolidated database", "backup", backupPath)
+		}
+		var err error
+		if dstConn, err = db.OpenConsolidated(dstPath, dstPath); err != nil {
+			return fmt.Errorf("destination: %w", err)
+		}
+	}
+
+	var rows []sections.Row
+	var failed int
+	for _, name := range sortedKeys(sources) {
+		row, err := d.consolidateModel(ctx, name, sources[name], dstConn)
+		if err != nil {
+			d.Logger.Error("consolidate failed", "user", name, "error", err)
+			row["status"] = "failed: " + err.Error()
+			failed++
+		}
+		rows = append(rows, row)
+	}
+
+	if d.dryRun {
+		fmt.Printf("Dry run: databases that would be folded into %s\n", dstPath)
+	} else {
+		fmt.Printf("Consolidated into %s\n", dstPath)
+	}
+	console := sections.NewConsoleSection(consolidateColumns)
+	console.SetRows(rows)
+	console.Print()
+
+	if failed > 0 {
+		return fmt.Errorf("%d database(s) failed to consolidate", failed)
+	}
+	return nil
+}
+
+// consolidateModel folds one model's own database into dstConn, or with a
+// nil dstConn (dry run) only reports its model ID.
+func (d *DBCommand) consolidateModel(ctx context.Context, name, srcPath string, dstConn *db.Conn) (sections.Row, error) {
+	row := sections.Row{"user": name, "model_id": "-", "posts": "-", "messages": "-",
+		"stories": "-", "media": "-", "other": "-"}
+
+	// The source is pooled under its path: its username names the model's
+	// partition of the consolidated database.
+	srcConn, err := db.Open(srcPath, srcPath)
+	if err != nil {
+		return row, fmt.Errorf("open %s: %w", srcPath, err)
+	}
+	defer db.Close(srcPath)
+
+	modelID, err := db.ModelIDOf(ctx, srcConn, name)
+	if err != nil {
+		return row, err
+	}
+	if modelID == 0 {
+		return row, fmt.Errorf("model ID unknown; scrape the model once first")
+	}
+	row["model_id"] = fmt.Sprintf("%d", modelID)
+
+	if dstConn == nil {
+		row["status"] = "would fold"
+		return row, nil
+	}
+	result, err := db.ConsolidateModel(ctx, srcConn, dstConn, modelID, name)
+	if errors.Is(err, db.ErrAlreadyConsolidated) {
+		row["status"] = "already consolidated"
+		return row, nil
+	}
+	if err != nil {
+		return row, err
+	}
+	row["posts"] = fmt.Sprintf("%d", result.PostsMerged)
+	row["messages"] = fmt.Sprintf("%d", result.MessagesMerged)
+	row["stories"] = fmt.Sprintf("%d", result.StoriesMerged)
+	row["media"] = fmt.Sprintf("%d", result.MediaMerged)
+	row["other"] = fmt.Sprintf("%d", result.LabelsMerged+result.OthersMerged)
+	row["status"] = "folded"
+	return row, nil
+}
+
+// ---------------------------------------------------------------------------
+// Import legacy
+// ---------------------------------------------------------------------------
+
+// importColumns are the columns of the legacy import report.
+var importColumns = []sections.Column{
+	{Key: "table", Title: "Table", Width: 10},
+	{Key: "imported", Title: "Imported", Width: 8},
+	{Key: "skipped", Title: "Skipped", Width: 7},
+	{Key: "unmapped", Title: "Unmapped Columns", Width: 40},
+}
+
+// runImportLegacy imports a Python OF-Scraper database into a model database.
+func (d *DBCommand) runImportLegacy(ctx context.Context, _ *app.App, args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("import-legacy requires a legacy database file")
+	}
+	srcPath, err := filepath.Abs(args[0])
+	if err != nil {
+		return fmt.Errorf("source: %w", err)
+	}
+	if !paths.Exists(srcPath) {
+		return fmt.Errorf("legacy database not found: %s", srcPath)
+	}
+
+	var dest string
+	if len(args) > 1 {
+		dest = args[1]
+	} else if dest = legacyOwner(srcPath); dest == "" {
+		return fmt.Errorf("cannot infer the model from %s; pass the destination username", srcPath)
+	}
+
+	dstKey, dstPath := resolveDBPath(dest)
+	if abs, err := filepath.Abs(dstPath); err == nil && abs == srcPath {
+		return fmt.Errorf("source and destination are the same database: %s", srcPath)
+	}
+	dstConn, err := db.Open(dstKey, dstPath)
+	if err != nil {
+		return fmt.Errorf("destination: %w", err)
+	}
+
+	d.Logger.Info("importing legacy database",
+		"source", srcPath,
+		"destination", dstConn.Path,
+	)
+
+	result, err := db.ImportLegacy(ctx, srcPath, dstConn)
+	if err != nil {
+		return fmt.Errorf("import failed: %w", err)
+	}
+
+	var rows []sections.Row
+	for _, table := range result.Tables {
+		unmapped := strings.Join(result.Unmapped[table], ", ")
+		if unmapped == "" {
+			unmapped = "-"
+		}
+		rows = append(rows, sections.Row{
+			"table":    table,
+			"imported": fmt.Sprintf("%d", result.Imported[table]),
+			"skipped":  fmt.Sprintf("%d", result.Skipped[table]),
+			"unmapped": unmapped,
+		})
+	}
+
+	fmt.Printf("Import %s -> %s\n", srcPath, dstConn.Path)
+	console := sections.NewConsoleSection(importColumns)
+	console.SetRows(rows)
+	console.Print()
+
+	if len(result.UnknownTables) > 0 {
+		d.Logger.Warn("legacy tables not imported", "tables", result.UnknownTables)
+	}
+	d.Logger.Info("import complete", "backup", result.BackupPath)
+	return nil
+}
+
+// legacyOwner infers the model username from a legacy database path, which
+// the Python scraper keeps at {save}/{username}/.data/user_data.db or
+// {save}/{username}/user_data.db.
+func legacyOwner(dbPath string) string {
+	dir := filepath.Dir(dbPath)
+	if filepath.Base(dir) == ".data" {
+		dir = filepath.Dir(dir)
+	}
+	name := filepath.Base(dir)
+	if name == "." || name == string(filepath.Separator) {
+		return ""
+	}
+	return name
+}
@@ -1,7 +1,8 @@
 // =============================================================================
 // FILE: internal/commands/manual.go
 // PURPOSE: Manual download command. Handles URL-based downloads where the user
-//          provides direct media URLs as CLI arguments. Ports Python
+//          provides post, message, or story links as CLI arguments, and
+//          reports a per-URL result table. Ports Python
 //          runner/manual/manual.py.
 // =============================================================================
 
@@ -12,10 +13,14 @@ import (
 	"fmt"
 	"log/slog"
 	"net/url"
+	"strconv"
 	"strings"
 
+	"gofscraper/internal/api"
 	"gofscraper/internal/app"
 	cmdutils "gofscraper/internal/commands/utils"
+	"gofscraper/internal/model"
+	"gofscraper/internal/tui/sections"
 )
 
 // ---------------------------------------------------------------------------
@@ -70,7 +75,11 @@ func (m *ManualCommand) Run(ctx context.Context, a *app.App, urls []string) erro
 
 	m.Logger.Info("starting manual download", "url_count", len(parsed))
 
+	client := api.NewClient(a.Session())
+	usernames := make(map[int64]string)
+
 	// Process each URL.
+	var results []ManualResult
 	var succeeded, failed int
 	for i, u := range parsed {
 		if ctx.Err() != nil {
@@ -79,12 +88,14 @@ func (m *ManualCommand) Run(ctx context.Context, a *app.App, urls []string) erro
 
 		m.Logger.Info(fmt.Sprintf("downloading %d/%d: %s", i+1, len(parsed), u.String()))
 
-		if err := m.downloadURL(ctx, a, u); err != nil {
-			m.Logger.Error("download failed", "url", u.String(), "error", err)
+		r := m.downloadURL(ctx, a, client, usernames, u)
+		if r.Err != nil {
+			m.Logger.Error("download failed", "url", u.String(), "error", r.Err)
 			failed++
-			continue
+		} else {
+			succeeded++
 		}
-		succeeded++
+		results = append(results, r)
 	}
 
 	m.Logger.Info("manual download complete",
@@ -92,6 +103,7 @@ func (m *ManualCommand) Run(ctx context.Context, a *app.App, urls []string) erro
 		"failed", failed,
 		"total", len(parsed),
 	)
+	m.printResults(results)
 
 	return nil
 }
@@ -125,12 +137,163 @@ func (m *ManualCommand) validateURLs(urls []string) ([]*url.URL, error) {
 	return parsed, nil
 }
 
-// downloadURL downloads a single URL.
-func (m *ManualCommand) downloadURL(ctx context.Context, a *app.App, u *url.URL) error {
-	_ = a.Session() // Will be used when download is wired.
-	_ = ctx
+// ---------------------------------------------------------------------------
+// ManualResult
+// ---------------------------------------------------------------------------
 
-	// TODO: Wire to download handler - resolve filename, download content.
-	m.Logger.Debug("download placeholder", "url", u.String())
-	return nil
+// ManualResult records the outcome of one manual URL.
+type ManualResult struct {
+	URL       string
+	Kind      ManualKind
+	Username  string
+	ID        int64
+	Media     int // Media items that passed the filter chain
+	Succeeded int
+	Failed    int
+	Skipped   int
+	Err       error
+}
+
+// downloadURL resolves a single URL to its post(s), filters their media,
+// and downloads it to the scraper's configured paths.
+func (m *ManualCommand) downloadURL(ctx context.Context, a *app.App, client *api.Client, usernames map[int64]string, u *url.URL) ManualResult {
+	target, err := ParseManualURL(u)
+	r := ManualResult{URL: u.String(), Kind: target.Kind, Username: target.Username, ID: target.ID}
+	if err != nil {
+		r.Err = err
+		return r
+	}
+
+	posts, err := m.fetchTarget(ctx, client, target)
+	if err != nil {
+		r.Err = err
+		return r
+	}
+	if len(posts) == 0 {
+		r.Err = fmt.Errorf("no content found")
+		return r
+	}
+
+	username := target.Username
+	if username == "" {
+		username, err = m.lookupUsername(ctx, client, usernames, posts[0].ModelID)
+		if err != nil {
+			r.Err = err
+			return r
+		}
+	}
+	r.Username = username
+
+	var media []*model.Media
+	for i := range posts {
+		posts[i].Username = username
+		posts[i].LinkMedia()
+		media = append(media, posts[i].AllMedia...)
+	}
+	media = app.MediaFilters()(media)
+	r.Media = len(media)
+	if len(media) == 0 {
+		return r
+	}
+
+	result, err := a.DownloadMedia(ctx, username, media)
+	if result != nil {
+		r.Succeeded, r.Failed, r.Skipped = result.Succeeded, result.Failed, result.Skipped
+	}
+	if err != nil {
+		r.Err = err
+	} else if r.Failed > 0 {
+		r.Err = fmt.Errorf("%d of %d media failed", r.Failed, r.Media)
+	}
+	return r
+}
+
+// fetchTarget fetches the post(s) a manual URL points at.
+func (m *ManualCommand) fetchTarget(ctx context.Context, client *api.Client, t ManualTarget) ([]model.Post, error) {
+	switch t.Kind {
+	case ManualPost:
+		p, err := client.GetPost(ctx, t.ID)
+		if err != nil {
+			return nil, err
+		}
+		return []model.Post{p}, nil
+	case ManualMessage:
+		p, err := client.GetMessage(ctx, t.ModelID, t.ID)
+		if err != nil {
+			return nil, err
+		}
+		p.ModelID = t.ModelID
+		return []model.Post{p}, nil
+	case ManualStory:
+		p, err := client.GetStory(ctx, t.ID)
+		if err != nil {
+			return nil, err
+		}
+		return []model.Post{p}, nil
+	case ManualHighlight:
+		return client.GetHighlight(ctx, t.ID)
+	default:
+		return nil, fmt.Errorf("unsupported URL type %q", t.Kind)
+	}
+}
+
+// lookupUsername resolves a model ID to a username, caching the result for
+// later URLs from the same model.
+func (m *ManualCommand) lookupUsername(ctx context.Context, client *api.Client, cache map[int64]string, modelID int64) (string, error) {
+	if modelID == 0 {
+		return "", fmt.Errorf("content has no owner ID")
+	}
+	if name, ok := cache[modelID]; ok {
+		return name, nil
+	}
+	user, err := client.GetProfile(ctx, strconv.FormatInt(modelID, 10))
+	if err != nil {
+		return "", fmt.Errorf("lookup model %d: %w", modelID, err)
+	}
+	cache[modelID] = user.Name
+	return user.Name, nil
+}
+
+// ---------------------------------------------------------------------------
+// Result table
+// ---------------------------------------------------------------------------
+
+// ManualColumns are the columns of the manual download result table.
+var ManualColumns = []sections.Column{
+	{Key: "url", Title: "URL", Width: 50},
+	{Key: "type", Title: "Type", Width: 9},
+	{Key: "user", Title: "User", Width: 16},
+	{Key: "id", Title: "ID", Width: 12},
+	{Key: "media", Title: "Media", Width: 5},
+	{Key: "ok", Title: "OK", Width: 4},
+	{Key: "failed", Title: "Failed", Width: 6},
+	{Key: "status", Title: "Status", Width: 40},
+}
+
+// printResults prints one table row per manual URL.
+func (m *ManualCommand) printResults(results []ManualResult) {
+	rows := make([]sections.Row, 0, len(results))
+	for _, r := range results {
+		status := "ok"
+		switch {
+		case r.Err != nil:
+			status = r.Err.Error()
+		case r.Media == 0:
+			status = "no media after filters"
+		}
+		rows = append(rows, sections.Row{
+			"url":    r.URL,
+			"type":   string(r.Kind),
+			"user":   r.Username,
+			"id":     fmt.Sprintf("%d", r.ID),
+			"media":  fmt.Sprintf("%d", r.Media),
+			"ok":     fmt.Sprintf("%d", r.Succeeded),
+			"failed": fmt.Sprintf("%d", r.Failed),
+			"status": status,
+		})
+	}
+
+	console := sections.NewConsoleSection(ManualColumns)
+	console.SetRows(rows)
+	console.Print()
 }
@@ -0,0 +1,104 @@
+// =============================================================================
+// FILE: internal/commands/manual_url.go
+// PURPOSE: Manual URL resolution. Classifies OnlyFans links (posts, chat
+//          messages, stories, highlights) and extracts the IDs needed to
+//          fetch them. Ports Python runner/manual/manual.py get_info.
+// =============================================================================
+
+package commands
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"gofscraper/internal/utils"
+)
+
+// ---------------------------------------------------------------------------
+// ManualTarget
+// ---------------------------------------------------------------------------
+
+// ManualKind identifies what a manual URL points at.
+type ManualKind string
+
+const (
+	ManualPost      ManualKind = "post"
+	ManualMessage   ManualKind = "message"
+	ManualStory     ManualKind = "story"
+	ManualHighlight ManualKind = "highlight"
+)
+
+// ManualTarget is a parsed manual download URL.
+type ManualTarget struct {
+	URL      string
+	Kind     ManualKind
+	Username string // Set for post URLs
+	ModelID  int64  // Set for message URLs (the chat ID)
+	ID       int64  // Post, message, story, or highlight ID
+}
+
+// ParseManualURL classifies a link and extracts its IDs. Recognised forms:
+//
+//	/{post_id}/{username}                 timeline post
+//	/my/chats/chat/{model_id}/?firstId={id}  chat message
+//	/stories/highlights/{highlight_id}    highlight
+//	/stories/{story_id}                   story
+//
+// Parameters:
+//   - u: The parsed URL.
+//
+// Returns:
+//   - The resolved target, or an error if the URL is not recognised.
+func ParseManualURL(u *url.URL) (ManualTarget, error) {
+	t := ManualTarget{URL: u.String()}
+	segs := strings.Split(strings.Trim(u.Path, "/"), "/")
+
+	switch {
+	case len(segs) >= 4 && segs[0] == "my" && segs[1] == "chats" && segs[2] == "chat":
+		modelID, err := strconv.ParseInt(segs[3], 10, 64)
+		if err != nil {
+			return t, fmt.Errorf("invalid chat ID %q", segs[3])
+		}
+		raw := u.Query().Get("firstId")
+		if raw == "" {
+			raw = u.Query().Get("id")
+		}
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return t, fmt.Errorf("chat URL has no message ID (firstId)")
+		}
+		t.Kind, t.ModelID, t.ID = ManualMessage, modelID, id
+
+	case len(segs) == 3 && segs[0] == "stories" && segs[1] == "highlights":
+		id, err := strconv.ParseInt(segs[2], 10, 64)
+		if err != nil {
+			return t, fmt.Errorf("invalid highlight ID %q", segs[2])
+		}
+		t.Kind, t.ID = ManualHighlight, id
+
+	case len(segs) == 2 && segs[0] == "stories":
+		id, err := strconv.ParseInt(segs[1], 10, 64)
+		if err != nil {
+			return t, fmt.Errorf("invalid story ID %q", segs[1])
+		}
+		t.Kind, t.ID = ManualStory, id
+
+	case len(segs) == 2:
+		id, err := strconv.ParseInt(segs[0], 10, 64)
+		if err != nil {
+			return t, fmt.Errorf("unrecognised URL path %q", u.Path)
+		}
+		t.Username = utils.ParseUsername(segs[1])
+		if t.Username == "" {
+			return t, fmt.Errorf("post URL has no username")
+		}
+		t.Kind, t.ID = ManualPost, id
+
+	default:
+		return t, fmt.Errorf("unrecognised URL path %q", u.Path)
+	}
+
+	return t, nil
+}
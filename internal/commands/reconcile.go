@@ -0,0 +1,389 @@
+// =============================================================================
+// FILE: internal/commands/reconcile.go
+// PURPOSE: Reconcile command. Matches the files under each model's directory
+//          with the model's medias rows, reporting files the database does
+//          not know, downloaded rows whose files are gone, and rows whose
+//          files moved. With --fix, moved and found files are adopted and
+//          missing rows are marked not downloaded so they are fetched again.
+// =============================================================================
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gofscraper/internal/app"
+	cmdutils "gofscraper/internal/commands/utils"
+	"gofscraper/internal/db"
+	"gofscraper/internal/download"
+	"gofscraper/internal/paths"
+	"gofscraper/internal/tui/sections"
+)
+
+// ---------------------------------------------------------------------------
+// Reconcile status
+// ---------------------------------------------------------------------------
+
+// ReconcileStatus classifies one file or row.
+type ReconcileStatus string
+
+const (
+	ReconcileMatched ReconcileStatus = "matched" // File is where its row says
+	ReconcileMoved   ReconcileStatus = "moved"   // Row's file found at another path
+	ReconcileFound   ReconcileStatus = "found"   // File at its row's path, row not marked downloaded
+	ReconcileMissing ReconcileStatus = "missing" // Row marked downloaded, file not found
+	ReconcileOrphan  ReconcileStatus = "orphan"  // File with no matching row
+)
+
+// mediaIDPattern finds digit runs in a filename that may be a media ID.
+var mediaIDPattern = regexp.MustCompile(`\d+`)
+
+// ---------------------------------------------------------------------------
+// ReconcileCommand
+// ---------------------------------------------------------------------------
+
+// ReconcileCommand reconciles model databases with the files on disk.
+type ReconcileCommand struct {
+	cmdutils.CommandBase
+	fix bool
+}
+
+// NewReconcileCommand creates a new ReconcileCommand.
+//
+// Parameters:
+//   - logger: Structured logger for output.
+//
+// Returns:
+//   - A configured ReconcileCommand.
+func NewReconcileCommand(logger *slog.Logger) *ReconcileCommand {
+	return &ReconcileCommand{
+		CommandBase: cmdutils.NewCommandBase(logger),
+	}
+}
+
+// SetFix makes reconcile update the database: moved and found files are
+// adopted and missing files are marked not downloaded.
+func (r *ReconcileCommand) SetFix(fix bool) {
+	r.fix = fix
+}
+
+// Name returns the command name.
+func (r *ReconcileCommand) Name() string {
+	return "reconcile"
+}
+
+// reconcileResult collects the outcome for one model.
+type reconcileResult struct {
+	user    string
+	counts  map[ReconcileStatus]int
+	fixed   int
+	entries []reconcileEntry
+}
+
+// reconcileEntry is one discrepancy.
+type reconcileEntry struct {
+	status  ReconcileStatus
+	mediaID int64
+	path    string // File on disk, or the recorded path when missing
+	oldPath string // Recorded path of a moved file
+}
+
+// Run reconciles the given models.
+//
+// Parameters:
+//   - ctx: Context for cancellation.
+//   - a: The application instance providing config.
+//   - args: Model usernames or .db paths (all models when empty).
+//
+// Returns:
+//   - Error if reconciliation could not run, or if discrepancies were found
+//     and --fix was not given.
+func (r *ReconcileCommand) Run(ctx context.Context, _ *app.App, args []string) error {
+	r.LogStart(r.Name(), args)
+	defer r.LogDone(r.Name())
+
+	targets, err := targetDBs(ctx, args)
+	if err != nil {
+		return err
+	}
+	if len(targets) == 0 {
+		return fmt.Errorf("no databases found to reconcile")
+	}
+
+	var results []*reconcileResult
+	for _, name := range sortedKeys(targets) {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		res, err := r.reconcileModel(ctx, name, targets[name])
+		if err != nil {
+			r.Logger.Error("reconcile failed", "user", name, "error", err)
+			continue
+		}
+		results = append(results, res)
+	}
+
+	pending := r.printResults(results)
+	if pending > 0 && !r.fix {
+		return fmt.Errorf("reconcile found %d discrepancies (run with --fix to repair)", pending)
+	}
+	return nil
+}
+
+// reconcileModel matches one model's files and rows, fixing them if asked.
+func (r *ReconcileCommand) reconcileModel(ctx context.Context, name, dbPath string) (*reconcileResult, error) {
+	conn, err := db.Open(name, dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", dbPath, err)
+	}
+	rows, err := db.GetAllMedia(ctx, conn)
+	if err != nil {
+		return nil, fmt.Errorf("load media: %w", err)
+	}
+
+	res := &reconcileResult{user: name, counts: make(map[ReconcileStatus]int)}
+
+	// Index rows by recorded path and ID.
+	byPath := make(map[string]int)
+	byID := make(map[int64]int)
+	for i, row := range rows {
+		byID[row.MediaID] = i
+		if path, ok := rowPath(row); ok {
+			byPath[path] = i
+		}
+	}
+
+	// Walk the model's directory; files at a recorded path are matched.
+	root := modelDir(conn)
+	present := make(map[int]bool)
+	var unmatched []string
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !isMediaCandidate(d.Name()) || isSidecarFile(path) {
+			return nil
+		}
+		if i, ok := byPath[path]; ok {
+			present[i] = true
+			return nil
+		}
+		unmatched = append(unmatched, path)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan %s: %w", root, err)
+	}
+
+	// Rows whose recorded file is missing (recorded paths may lie outside
+	// the model's directory) are candidates for the unmatched files.
+	lost := make(map[int]bool)
+	for i, row := range rows {
+		if present[i] {
+			continue
+		}
+		if path, ok := rowPath(row); ok {
+			if _, err := os.Stat(path); err == nil {
+				present[i] = true
+				continue
+			}
+		}
+		lost[i] = true
+	}
+
+	// Present files are matched; those of rows not marked downloaded are
+	// found and adopted in place.
+	for i := range rows {
+		if !present[i] {
+			continue
+		}
+		if rows[i].Downloaded {
+			res.counts[ReconcileMatched]++
+			continue
+		}
+		path, _ := rowPath(rows[i])
+		res.add(reconcileEntry{status: ReconcileFound, mediaID: rows[i].MediaID, path: path})
+		if r.fix {
+			if err := r.adopt(ctx, conn, rows[i], path); err != nil {
+				return nil, err
+			}
+			res.fixed++
+		}
+	}
+
+	// Unmatched files: same filename as a lost row, else an embedded ID.
+	for _, path := range unmatched {
+		i, ok := matchLostRow(path, rows, lost, byID)
+		if !ok {
+			res.add(reconcileEntry{status: ReconcileOrphan, path: path})
+			continue
+		}
+		delete(lost, i)
+		old, _ := rowPath(rows[i])
+		res.add(reconcileEntry{status: ReconcileMoved, mediaID: rows[i].MediaID, path: path, oldPath: old})
+		if r.fix {
+			if err := r.adopt(ctx, conn, rows[i], path); err != nil {
+				return nil, err
+			}
+			res.fixed++
+		}
+	}
+
+	// Lost rows left unclaimed are missing if they claim to be downloaded.
+	for i := range rows {
+		if !lost[i] || !rows[i].Downloaded {
+			continue
+		}
+		path, _ := rowPath(rows[i])
+		res.add(reconcileEntry{status: ReconcileMissing, mediaID: rows[i].MediaID, path: path})
+		if r.fix {
+			row := rows[i]
+			row.Downloaded = false
+			if err := db.UpsertMedia(ctx, conn, row); err != nil {
+				return nil, fmt.Errorf("reset media %d: %w", row.MediaID, err)
+			}
+			res.fixed++
+		}
+	}
+	return res, nil
+}
+
+// adopt points a row at a file on disk and marks it downloaded.
+func (r *ReconcileCommand) adopt(ctx context.Context, conn *db.Conn, row db.MediaRow, path string) error {
+	row.Directory = db.NullString(paths.StoredDir(filepath.Dir(path)))
+	row.Filename = db.NullString(filepath.Base(path))
+	row.Downloaded = true
+	if err := db.UpsertMedia(ctx, conn, row); err != nil {
+		return fmt.Errorf("adopt %s: %w", path, err)
+	}
+	return nil
+}
+
+// matchLostRow finds the lost row an unmatched file belongs to: the only
+// lost row with the same filename, else the only lost row whose media ID
+// appears in the filename.
+func matchLostRow(path string, rows []db.MediaRow, lost map[int]bool, byID map[int64]int) (int, bool) {
+	base := filepath.Base(path)
+	match, n := -1, 0
+	for i := range lost {
+		if rows[i].Filename.Valid && rows[i].Filename.String == base {
+			match, n = i, n+1
+		}
+	}
+	if n == 1 {
+		return match, true
+	}
+
+	match, n = -1, 0
+	stem := strings.TrimSuffix(base, filepath.Ext(base))
+	for _, digits := range mediaIDPattern.FindAllString(stem, -1) {
+		id, err := strconv.ParseInt(digits, 10, 64)
+		if err != nil {
+			continue
+		}
+		if i, ok := byID[id]; ok && lost[i] && i != match {
+			match, n = i, n+1
+		}
+	}
+	return match, n == 1
+}
+
+// rowPath returns the absolute file path a row records, if any.
+func rowPath(row db.MediaRow) (string, bool) {
+	if !row.Directory.Valid || !row.Filename.Valid {
+		return "", false
+	}
+	return filepath.Join(paths.ResolveStoredDir(row.Directory.String), row.Filename.String), true
+}
+
+// isSidecarFile reports whether path is a metadata sidecar of a file
+// beside it.
+func isSidecarFile(path string) bool {
+	base, ok := strings.CutSuffix(path, download.SidecarExt)
+	if !ok {
+		return false
+	}
+	info, err := os.Stat(base)
+	return err == nil && !info.IsDir()
+}
+
+// add counts an entry and keeps it for the report.
+func (r *reconcileResult) add(e reconcileEntry) {
+	r.counts[e.status]++
+	r.entries = append(r.entries, e)
+}
+
+// ---------------------------------------------------------------------------
+// Report
+// ---------------------------------------------------------------------------
+
+// reconcileColumns are the columns of the per-model summary.
+var reconcileColumns = []sections.Column{
+	{Key: "user", Title: "User", Width: 20},
+	{Key: "matched", Title: "Matched", Width: 8},
+	{Key: "moved", Title: "Moved", Width: 8},
+	{Key: "found", Title: "Found", Width: 8},
+	{Key: "missing", Title: "Missing", Width: 8},
+	{Key: "orphan", Title: "Orphans", Width: 8},
+	{Key: "fixed", Title: "Fixed", Width: 8},
+}
+
+// reconcileEntryColumns are the columns of the discrepancy list.
+var reconcileEntryColumns = []sections.Column{
+	{Key: "user", Title: "User", Width: 20},
+	{Key: "status", Title: "Status", Width: 8},
+	{Key: "media_id", Title: "Media ID", Width: 20},
+	{Key: "path", Title: "Path", Width: 80},
+}
+
+// printResults prints the summary and discrepancy tables and returns the
+// number of discrepancies left unfixed.
+func (r *ReconcileCommand) printResults(results []*reconcileResult) int {
+	var summary, entries []sections.Row
+	var pending int
+	for _, res := range results {
+		row := sections.Row{"user": res.user, "fixed": fmt.Sprintf("%d", res.fixed)}
+		for _, s := range []ReconcileStatus{ReconcileMatched, ReconcileMoved, ReconcileFound, ReconcileMissing, ReconcileOrphan} {
+			row[string(s)] = fmt.Sprintf("%d", res.counts[s])
+		}
+		summary = append(summary, row)
+
+		for _, e := range res.entries {
+			id := ""
+			if e.mediaID != 0 {
+				id = fmt.Sprintf("%d", e.mediaID)
+			}
+			path := e.path
+			if e.status == ReconcileMoved && e.oldPath != "" {
+				path = e.oldPath + " -> " + e.path
+			}
+			entries = append(entries, sections.Row{
+				"user":     res.user,
+				"status":   string(e.status),
+				"media_id": id,
+				"path":     path,
+			})
+		}
+		pending += len(res.entries) - res.fixed
+	}
+
+	console := sections.NewConsoleSection(reconcileColumns)
+	console.SetRows(summary)
+	console.Print()
+
+	if len(entries) > 0 {
+		fmt.Println()
+		console = sections.NewConsoleSection(reconcileEntryColumns)
+		console.SetRows(entries)
+		console.Print()
+	}
+	return pending
+}
@@ -0,0 +1,438 @@
+// =============================================================================
+// FILE: internal/commands/reorganize.go
+// PURPOSE: Reorganize command. Re-renders the path of every downloaded media
+//          item with the current dir_format and file_format, moves files
+//          whose path changed, prunes emptied directories, and records the
+//          new paths in one transaction per model. A journal in the model's
+//          directory lets an interrupted run resume.
+// =============================================================================
+
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gofscraper/internal/app"
+	cmdutils "gofscraper/internal/commands/utils"
+	"gofscraper/internal/db"
+	"gofscraper/internal/download"
+	"gofscraper/internal/model"
+	"gofscraper/internal/paths"
+	"gofscraper/internal/tui/sections"
+)
+
+// reorganizeJournalName is the journal file kept in a model's directory
+// while its files are being moved.
+const reorganizeJournalName = ".reorganize.journal"
+
+// ---------------------------------------------------------------------------
+// Plan
+// ---------------------------------------------------------------------------
+
+// ReorganizeStatus classifies one entry of a reorganize plan.
+type ReorganizeStatus string
+
+const (
+	ReorganizeMove      ReorganizeStatus = "move"      // File will be (or was) moved
+	ReorganizeCollision ReorganizeStatus = "collision" // Target taken; file left in place
+	ReorganizeMissing   ReorganizeStatus = "missing"   // Recorded file not on disk
+)
+
+// reorganizeMove is one planned file move. It is also the journal format.
+type reorganizeMove struct {
+	MediaID int64  `json:"media_id"`
+	From    string `json:"from"`
+	To      string `json:"to"`
+}
+
+// reorganizeJournal lists the moves of a run in progress.
+type reorganizeJournal struct {
+	Moves []reorganizeMove `json:"moves"`
+}
+
+// reorganizeEntry is one line of the printed plan.
+type reorganizeEntry struct {
+	status ReorganizeStatus
+	move   reorganizeMove
+	reason string
+}
+
+// reorganizeResult collects the outcome for one model.
+type reorganizeResult struct {
+	user      string
+	unchanged int
+	moves     []reorganizeMove
+	entries   []reorganizeEntry
+	moved     int
+	pruned    int
+	resumed   bool
+}
+
+// ---------------------------------------------------------------------------
+// ReorganizeCommand
+// ---------------------------------------------------------------------------
+
+// ReorganizeCommand moves downloaded files to the paths the current
+// templates give them.
+type ReorganizeCommand struct {
+	cmdutils.CommandBase
+	dryRun bool
+}
+
+// NewReorganizeCommand creates a new ReorganizeCommand.
+//
+// Parameters:
+//   - logger: Structured logger for output.
+//
+// Returns:
+//   - A configured ReorganizeCommand.
+func NewReorganizeCommand(logger *slog.Logger) *ReorganizeCommand {
+	return &ReorganizeCommand{
+		CommandBase: cmdutils.NewCommandBase(logger),
+	}
+}
+
+// SetDryRun makes reorganize print the move plan without moving anything.
+func (r *ReorganizeCommand) SetDryRun(dryRun bool) {
+	r.dryRun = dryRun
+}
+
+// Name returns the command name.
+func (r *ReorganizeCommand) Name() string {
+	return "reorganize"
+}
+
+// Run reorganizes the given models.
+//
+// Parameters:
+//   - ctx: Context for cancellation.
+//   - a: The application instance providing config.
+//   - args: Model usernames or .db paths (all models when empty).
+//
+// Returns:
+//   - Error if any model could not be reorganized.
+func (r *ReorganizeCommand) Run(ctx context.Context, _ *app.App, args []string) error {
+	r.LogStart(r.Name(), args)
+	defer r.LogDone(r.Name())
+
+	targets, err := targetDBs(ctx, args)
+	if err != nil {
+		return err
+	}
+	if len(targets) == 0 {
+		return fmt.Errorf("no databases found to reorganize")
+	}
+
+	var results []*reorganizeResult
+	failed := 0
+	for _, name := range sortedKeys(targets) {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		res, err := r.reorganizeModel(ctx, name, targets[name])
+		if err != nil {
+			r.Logger.Error("reorganize failed", "user", name, "error", err)
+			failed++
+		}
+		if res != nil {
+			results = append(results, res)
+		}
+	}
+
+	r.printResults(results)
+	if failed > 0 {
+		return fmt.Errorf("reorganize failed for %d models (run it again to resume)", failed)
+	}
+	return nil
+}
+
+// reorganizeModel plans and, unless dry-running, applies the moves of one
+// model. A journal left by an interrupted run is resumed instead of
+// planning anew.
+func (r *ReorganizeCommand) reorganizeModel(ctx context.Context, name, dbPath string) (*reorganizeResult, error) {
+	conn, err := db.Open(name, dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", dbPath, err)
+	}
+
+	res := &reorganizeResult{user: name}
+	root := modelDir(conn)
+	journalPath := filepath.Join(root, reorganizeJournalName)
+	journal, err := readReorganizeJournal(journalPath)
+	switch {
+	case err == nil:
+		res.resumed = true
+		for _, mv := range journal.Moves {
+			res.addMove(mv)
+		}
+	case errors.Is(err, os.ErrNotExist):
+		if err := r.plan(ctx, conn, name, res); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+
+	if r.dryRun || len(res.moves) == 0 {
+		return res, nil
+	}
+	if !res.resumed {
+		if err := writeReorganizeJournal(journalPath, reorganizeJournal{Moves: res.moves}); err != nil {
+			return res, err
+		}
+	}
+
+	// Moves are idempotent, so a resumed journal is simply replayed.
+	updates := make([]db.MediaPath, 0, len(res.moves))
+	for _, mv := range res.moves {
+		if ctx.Err() != nil {
+			return res, ctx.Err()
+		}
+		if err := applyReorganizeMove(mv); err != nil {
+			return res, err
+		}
+		updates = append(updates, db.MediaPath{
+			MediaID:   mv.MediaID,
+			Directory: paths.StoredDir(filepath.Dir(mv.To)),
+			Filename:  filepath.Base(mv.To),
+		})
+		res.moved++
+	}
+	if err := db.UpdateMediaPaths(ctx, conn, updates); err != nil {
+		return res, fmt.Errorf("record new paths: %w", err)
+	}
+
+	// Prune up to the directory holding the model directories: the save
+	// location.
+	saveLocation := filepath.Dir(root)
+	if !conn.Partitioned && db.IsConsolidated(conn) {
+		saveLocation = root
+	}
+	for _, mv := range res.moves {
+		res.pruned += paths.PruneEmptyDirs(filepath.Dir(mv.From), saveLocation)
+	}
+	if err := os.Remove(journalPath); err != nil {
+		return res, fmt.Errorf("remove journal: %w", err)
+	}
+	return res, nil
+}
+
+// plan renders the current path of every downloaded media item and sorts
+// the changed ones into moves, collisions, and missing files.
+func (r *ReorganizeCommand) plan(ctx context.Context, conn *db.Conn, name string, res *reorganizeResult) error {
+	rows, err := db.GetDownloadedMedia(ctx, conn)
+	if err != nil {
+		return fmt.Errorf("load media: %w", err)
+	}
+	posts, err := reorganizePosts(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	// Render every target first so two media bound for one path are both
+	// caught, whichever comes first.
+	var planned []reorganizeMove
+	targets := make(map[string]int)
+	for _, row := range rows {
+		if !row.Directory.Valid || !row.Filename.Valid {
+			continue
+		}
+		m := app.MediaFromRow(row, name, posts[reorganizePostKey(row)])
+		from := m.FilePath
+		m.FilePath = ""
+		to := app.ResolveMediaPath(m)
+		if to == from {
+			res.unchanged++
+			continue
+		}
+		planned = append(planned, reorganizeMove{MediaID: row.MediaID, From: from, To: to})
+		targets[to]++
+	}
+
+	for _, mv := range planned {
+		switch {
+		case !paths.IsFile(mv.From):
+			res.entries = append(res.entries, reorganizeEntry{status: ReorganizeMissing, move: mv})
+		case targets[mv.To] > 1:
+			res.entries = append(res.entries, reorganizeEntry{status: ReorganizeCollision, move: mv, reason: "shared target"})
+		case paths.Exists(mv.To):
+			res.entries = append(res.entries, reorganizeEntry{status: ReorganizeCollision, move: mv, reason: "target exists"})
+		default:
+			res.addMove(mv)
+		}
+	}
+	return nil
+}
+
+// reorganizePosts loads the post, message, and story rows the path
+// templates draw text and price from, keyed by reorganizePostKey.
+func reorganizePosts(ctx context.Context, conn *db.Conn) (map[string]*db.PostRow, error) {
+	posts := make(map[string]*db.PostRow)
+	tables := []struct {
+		area string
+		load func(context.Context, *db.Conn) ([]db.PostRow, error)
+	}{
+		{"posts", db.GetAllPosts},
+		{string(model.ResponseMessages), db.GetAllMessages},
+		{string(model.ResponseStories), db.GetAllStories},
+	}
+	for _, t := range tables {
+		rows, err := t.load(ctx, conn)
+		if err != nil {
+			return nil, fmt.Errorf("load %s: %w", t.area, err)
+		}
+		for i := range rows {
+			posts[fmt.Sprintf("%s/%d", t.area, rows[i].PostID)] = &rows[i]
+		}
+	}
+	return posts, nil
+}
+
+// reorganizePostKey returns the reorganizePosts key of a media row's post.
+func reorganizePostKey(row db.MediaRow) string {
+	return fmt.Sprintf("%s/%d", postTable(row.APIType.String), row.PostID)
+}
+
+// addMove adds a move to the plan.
+func (r *reorganizeResult) addMove(mv reorganizeMove) {
+	r.moves = append(r.moves, mv)
+	r.entries = append(r.entries, reorganizeEntry{status: ReorganizeMove, move: mv})
+}
+
+// ---------------------------------------------------------------------------
+// Moves and journal
+// ---------------------------------------------------------------------------
+
+// applyReorganizeMove moves a file and its sidecar. A move whose source is
+// gone and whose target exists was done by an earlier, interrupted run.
+func applyReorganizeMove(mv reorganizeMove) error {
+	for _, ext := range []string{"", download.SidecarExt} {
+		from, to := mv.From+ext, mv.To+ext
+		switch {
+		case paths.IsFile(from) && !paths.Exists(to):
+			if err := paths.MoveFile(from, to); err != nil {
+				return err
+			}
+		case !paths.Exists(from) && paths.IsFile(to):
+			// Already moved.
+		case ext == download.SidecarExt && !paths.Exists(from):
+			// No sidecar.
+		case paths.Exists(to):
+			return fmt.Errorf("move media %d: %s already exists", mv.MediaID, to)
+		default:
+			return fmt.Errorf("move media %d: %s not found", mv.MediaID, from)
+		}
+	}
+	return nil
+}
+
+// readReorganizeJournal loads a journal left by an interrupted run.
+func readReorganizeJournal(path string) (*reorganizeJournal, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var j reorganizeJournal
+	if err := json.Unmarshal(data, &j); err != nil {
+		return nil, fmt.Errorf("parse journal %s: %w", path, err)
+	}
+	return &j, nil
+}
+
+// writeReorganizeJournal writes the journal through a temporary file so an
+// interruption never leaves a partial one.
+func writeReorganizeJournal(path string, j reorganizeJournal) error {
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode journal: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("write journal: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("write journal: %w", err)
+	}
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// Report
+// ---------------------------------------------------------------------------
+
+// reorganizeColumns are the columns of the per-model summary.
+var reorganizeColumns = []sections.Column{
+	{Key: "user", Title: "User", Width: 20},
+	{Key: "unchanged", Title: "Unchanged", Width: 10},
+	{Key: "planned", Title: "Planned", Width: 8},
+	{Key: "moved", Title: "Moved", Width: 8},
+	{Key: "collision", Title: "Collisions", Width: 10},
+	{Key: "missing", Title: "Missing", Width: 8},
+	{Key: "pruned", Title: "Dirs Pruned", Width: 11},
+}
+
+// reorganizePlanColumns are the columns of the move plan.
+var reorganizePlanColumns = []sections.Column{
+	{Key: "user", Title: "User", Width: 20},
+	{Key: "status", Title: "Status", Width: 10},
+	{Key: "media_id", Title: "Media ID", Width: 20},
+	{Key: "from", Title: "From", Width: 60},
+	{Key: "to", Title: "To", Width: 60},
+}
+
+// printResults prints the move plan and the per-model summary.
+func (r *ReorganizeCommand) printResults(results []*reorganizeResult) {
+	var summary, plan []sections.Row
+	for _, res := range results {
+		counts := make(map[ReorganizeStatus]int)
+		entries := append([]reorganizeEntry(nil), res.entries...)
+		sort.SliceStable(entries, func(i, j int) bool { return entries[i].status < entries[j].status })
+		for _, e := range entries {
+			counts[e.status]++
+			status := string(e.status)
+			if e.reason != "" {
+				status += " (" + e.reason + ")"
+			}
+			plan = append(plan, sections.Row{
+				"user":     res.user,
+				"status":   status,
+				"media_id": fmt.Sprintf("%d", e.move.MediaID),
+				"from":     e.move.From,
+				"to":       e.move.To,
+			})
+		}
+		user := res.user
+		if res.resumed {
+			user += " (resumed)"
+		}
+		summary = append(summary, sections.Row{
+			"user":      user,
+			"unchanged": fmt.Sprintf("%d", res.unchanged),
+			"planned":   fmt.Sprintf("%d", counts[ReorganizeMove]),
+			"moved":     fmt.Sprintf("%d", res.moved),
+			"collision": fmt.Sprintf("%d", counts[ReorganizeCollision]),
+			"missing":   fmt.Sprintf("%d", counts[ReorganizeMissing]),
+			"pruned":    fmt.Sprintf("%d", res.pruned),
+		})
+	}
+
+	if len(plan) > 0 {
+		console := sections.NewConsoleSection(reorganizePlanColumns)
+		console.SetRows(plan)
+		console.Print()
+		fmt.Println()
+	}
+	console := sections.NewConsoleSection(reorganizeColumns)
+	console.SetRows(summary)
+	console.Print()
+	if r.dryRun {
+		fmt.Println("Dry run: no files were moved.")
+	}
+}
@@ -0,0 +1,241 @@
+// =============================================================================
+// FILE: internal/commands/scraper/cron.go
+// PURPOSE: Standard 5-field cron expressions for daemon schedules. Parses
+//          minute, hour, day-of-month, month, and day-of-week fields with
+//          lists, ranges, steps, and the common @ shortcuts, and computes the
+//          next matching time.
+// =============================================================================
+
+package scraper
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ---------------------------------------------------------------------------
+// CronSchedule
+// ---------------------------------------------------------------------------
+
+// CronSchedule is a parsed cron expression. Each field is a bitset of the
+// values it matches.
+type CronSchedule struct {
+	expr    string
+	minute  uint64
+	hour    uint64
+	dom     uint64
+	month   uint64
+	dow     uint64
+	domStar bool
+	dowStar bool
+}
+
+// cronShortcuts maps @ shortcuts to their 5-field equivalents.
+var cronShortcuts = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// cronField describes the valid range of one cron field.
+type cronField struct {
+	name     string
+	min, max int
+}
+
+var cronFields = [5]cronField{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day-of-month", 1, 31},
+	{"month", 1, 12},
+	{"day-of-week", 0, 7},
+}
+
+// ParseCron parses a standard 5-field cron expression
+// ("minute hour day-of-month month day-of-week") or an @ shortcut such as
+// "@daily". Day-of-week accepts 0-7, where both 0 and 7 mean Sunday.
+//
+// Parameters:
+//   - expr: The cron expression.
+//
+// Returns:
+//   - The parsed schedule, or an error if the expression is invalid.
+func ParseCron(expr string) (*CronSchedule, error) {
+	expr = strings.TrimSpace(expr)
+	spec := expr
+	if full, ok := cronShortcuts[strings.ToLower(expr)]; ok {
+		spec = full
+	}
+
+	parts := strings.Fields(spec)
+	if len(parts) != 5 {
+		return nil, fmt.Errorf("cron %q: expected 5 fields, got %d", expr, len(parts))
+	}
+
+	var sets [5]uint64
+	for i, part := range parts {
+		set, err := parseCronField(part, cronFields[i])
+		if err != nil {
+			return nil, fmt.Errorf("cron %q: %w", expr, err)
+		}
+		sets[i] = set
+	}
+
+	// Sunday may be written as 7; fold it onto 0.
+	if sets[4]&(1<<7) != 0 {
+		sets[4] = sets[4]&^(1<<7) | 1
+	}
+
+	return &CronSchedule{
+		expr:    expr,
+		minute:  sets[0],
+		hour:    sets[1],
+		dom:     sets[2],
+		month:   sets[3],
+		dow:     sets[4],
+		domStar: parts[2] == "*" || parts[2] == "?",
+		dowStar: parts[4] == "*" || parts[4] == "?",
+	}, nil
+}
+
+// parseCronField parses one comma-separated field into a bitset.
+func parseCronField(s string, f cronField) (uint64, error) {
+	var set uint64
+	for _, item := range strings.Split(s, ",") {
+		lo, hi, step := f.min, f.max, 1
+
+		rangePart := item
+		if i := strings.Index(item, "/"); i >= 0 {
+			n, err := strconv.Atoi(item[i+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("%s: invalid step in %q", f.name, item)
+			}
+			step = n
+			rangePart = item[:i]
+		}
+
+		switch {
+		case rangePart == "*" || rangePart == "?":
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			a, errA := strconv.Atoi(bounds[0])
+			b, errB := strconv.Atoi(bounds[1])
+			if errA != nil || errB != nil {
+				return 0, fmt.Errorf("%s: invalid range %q", f.name, item)
+			}
+			lo, hi = a, b
+		default:
+			n, err := strconv.Atoi(rangePart)
+			if err != nil {
+				return 0, fmt.Errorf("%s: invalid value %q", f.name, item)
+			}
+			lo = n
+			if step == 1 {
+				hi = n
+			}
+		}
+
+		if lo < f.min || hi > f.max || lo > hi {
+			return 0, fmt.Errorf("%s: %q out of range %d-%d", f.name, item, f.min, f.max)
+		}
+		for v := lo; v <= hi; v += step {
+			set |= 1 << uint(v)
+		}
+	}
+	return set, nil
+}
+
+// ---------------------------------------------------------------------------
+// Next run
+// ---------------------------------------------------------------------------
+
+// Next returns the first matching minute strictly after t, in t's location.
+// Matching is on the wall clock: a time that repeats when clocks go back
+// matches once, on its first occurrence, and a time skipped when they go
+// forward matches the first minute after the change. Returns the zero time
+// if nothing matches within five years (for example "0 0 31 2 *").
+//
+// Parameters:
+//   - t: The reference time.
+//
+// Returns:
+//   - The next matching time.
+func (c *CronSchedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	w := wallClock(t)
+	limit := w.AddDate(5, 0, 0)
+
+	for {
+		if w = c.nextWall(w, limit); w.IsZero() {
+			return time.Time{}
+		}
+		next := time.Date(w.Year(), w.Month(), w.Day(), w.Hour(), w.Minute(), 0, 0, loc)
+		// A wall time inside a daylight-saving gap resolves to before the
+		// gap; move to the first minute after it.
+		for wallClock(next).Before(w) {
+			next = next.Add(time.Minute)
+		}
+		if next.After(t) {
+			return next
+		}
+	}
+}
+
+// nextWall returns the first matching wall-clock minute after w, or the
+// zero time if there is none before limit. Wall-clock times are held in
+// UTC, which has no daylight-saving changes.
+func (c *CronSchedule) nextWall(w, limit time.Time) time.Time {
+	w = w.Add(time.Minute)
+	for w.Before(limit) {
+		if c.month&(1<<uint(w.Month())) == 0 {
+			w = time.Date(w.Year(), w.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if !c.dayMatches(w) {
+			w = time.Date(w.Year(), w.Month(), w.Day()+1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if c.hour&(1<<uint(w.Hour())) == 0 {
+			w = time.Date(w.Year(), w.Month(), w.Day(), w.Hour()+1, 0, 0, 0, time.UTC)
+			continue
+		}
+		if c.minute&(1<<uint(w.Minute())) == 0 {
+			w = w.Add(time.Minute)
+			continue
+		}
+		return w
+	}
+	return time.Time{}
+}
+
+// wallClock returns t's wall-clock time to the minute, as a UTC time.
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, time.UTC)
+}
+
+// dayMatches applies cron's day rule: when both day-of-month and
+// day-of-week are restricted, a day matching either one qualifies.
+func (c *CronSchedule) dayMatches(t time.Time) bool {
+	domOK := c.dom&(1<<uint(t.Day())) != 0
+	dowOK := c.dow&(1<<uint(t.Weekday())) != 0
+	switch {
+	case c.domStar && c.dowStar:
+		return true
+	case c.domStar:
+		return dowOK
+	case c.dowStar:
+		return domOK
+	}
+	return domOK || dowOK
+}
+
+// String returns the expression the schedule was parsed from.
+func (c *CronSchedule) String() string {
+	return c.expr
+}
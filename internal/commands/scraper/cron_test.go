@@ -0,0 +1,202 @@
+// =============================================================================
+// FILE: internal/commands/scraper/cron_test.go
+// PURPOSE: Tests for cron expressions: field parsing (lists, ranges, steps,
+//          wildcards, shortcuts, and invalid input) and next-run computation
+//          across month and year ends, the day-of-month/day-of-week rule,
+//          and daylight-saving changes.
+// =============================================================================
+
+package scraper
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+// bits returns the bitset of the given field values.
+func bits(values ...int) uint64 {
+	var set uint64
+	for _, v := range values {
+		set |= 1 << uint(v)
+	}
+	return set
+}
+
+// span returns the bitset of lo through hi.
+func span(lo, hi int) uint64 {
+	var set uint64
+	for v := lo; v <= hi; v++ {
+		set |= 1 << uint(v)
+	}
+	return set
+}
+
+func TestParseCron(t *testing.T) {
+	tests := []struct {
+		expr                          string
+		minute, hour, dom, month, dow uint64
+		domStar, dowStar              bool
+	}{
+		{"* * * * *", span(0, 59), span(0, 23), span(1, 31), span(1, 12), span(0, 6), true, true},
+		{"*/15 * * * *", bits(0, 15, 30, 45), span(0, 23), span(1, 31), span(1, 12), span(0, 6), true, true},
+		{"5-50/15 * * * *", bits(5, 20, 35, 50), span(0, 23), span(1, 31), span(1, 12), span(0, 6), true, true},
+		{"10/20 * * * *", bits(10, 30, 50), span(0, 23), span(1, 31), span(1, 12), span(0, 6), true, true},
+		{"0,30 8,20 * * *", bits(0, 30), bits(8, 20), span(1, 31), span(1, 12), span(0, 6), true, true},
+		{"0 9-17 * * 1-5", bits(0), span(9, 17), span(1, 31), span(1, 12), span(1, 5), true, false},
+		{"0 0 1,15 */3 *", bits(0), bits(0), bits(1, 15), bits(1, 4, 7, 10), span(0, 6), false, true},
+		{"0 0 * * 7", bits(0), bits(0), span(1, 31), span(1, 12), bits(0), true, false},
+		{"0 0 * * 5-7", bits(0), bits(0), span(1, 31), span(1, 12), bits(0, 5, 6), true, false},
+		{"0 12 ? * ?", bits(0), bits(12), span(1, 31), span(1, 12), span(0, 6), true, true},
+		{"@daily", bits(0), bits(0), span(1, 31), span(1, 12), span(0, 6), true, true},
+		{"@weekly", bits(0), bits(0), span(1, 31), span(1, 12), bits(0), true, false},
+		{"@yearly", bits(0), bits(0), bits(1), bits(1), span(0, 6), false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			c, err := ParseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseCron: %v", err)
+			}
+			// 0-7 day-of-week sets fold 7 onto Sunday.
+			dow := c.dow & span(0, 6)
+			if c.minute != tt.minute || c.hour != tt.hour || c.dom != tt.dom || c.month != tt.month || dow != tt.dow {
+				t.Errorf("fields = %b %b %b %b %b", c.minute, c.hour, c.dom, c.month, c.dow)
+			}
+			if c.domStar != tt.domStar || c.dowStar != tt.dowStar {
+				t.Errorf("domStar, dowStar = %v, %v; want %v, %v", c.domStar, c.dowStar, tt.domStar, tt.dowStar)
+			}
+			if c.String() != tt.expr {
+				t.Errorf("String() = %q, want %q", c.String(), tt.expr)
+			}
+		})
+	}
+}
+
+func TestParseCronInvalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"0 0 0 * *",
+		"0 0 32 * *",
+		"0 0 * 13 *",
+		"0 0 * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"*/x * * * *",
+		"a * * * *",
+		"1-x * * * *",
+		"@sometimes",
+	} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("ParseCron(%q) succeeded, want an error", expr)
+		}
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	at := func(y int, m time.Month, d, h, min int) time.Time {
+		return time.Date(y, m, d, h, min, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name string
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{"step", "*/15 * * * *", at(2024, 9, 4, 10, 7).Add(30 * time.Second), at(2024, 9, 4, 10, 15)},
+		{"strictly after", "0 * * * *", at(2024, 9, 4, 10, 0), at(2024, 9, 4, 11, 0)},
+		{"list", "0,30 8,20 * * *", at(2024, 9, 4, 8, 45), at(2024, 9, 4, 20, 0)},
+		{"range wraps to next day", "0 9-17 * * *", at(2024, 9, 4, 17, 30), at(2024, 9, 5, 9, 0)},
+		{"hour rolls over day", "@hourly", at(2024, 12, 31, 23, 59), at(2025, 1, 1, 0, 0)},
+		{"month rollover", "0 0 1 * *", at(2024, 1, 31, 12, 0), at(2024, 2, 1, 0, 0)},
+		{"year rollover", "0 0 1 1 *", at(2024, 6, 1, 0, 0), at(2025, 1, 1, 0, 0)},
+		{"skips short months", "0 0 31 * *", at(2024, 4, 1, 0, 0), at(2024, 5, 31, 0, 0)},
+		{"leap day", "0 0 29 2 *", at(2024, 3, 1, 0, 0), at(2028, 2, 29, 0, 0)},
+		{"month range", "0 0 1 6-8 *", at(2024, 9, 1, 0, 0), at(2025, 6, 1, 0, 0)},
+		{"day of week", "0 12 * * 1", at(2024, 9, 4, 0, 0), at(2024, 9, 9, 12, 0)},
+		{"sunday as 7", "0 12 * * 7", at(2024, 9, 4, 0, 0), at(2024, 9, 8, 12, 0)},
+		{"day of month", "30 6 15 * *", at(2024, 9, 15, 7, 0), at(2024, 10, 15, 6, 30)},
+		{"either day field matches", "0 0 13 * 5", at(2024, 9, 1, 0, 0), at(2024, 9, 6, 0, 0)},
+		{"day of month when weekday later", "0 0 2 * 5", at(2024, 9, 1, 0, 0), at(2024, 9, 2, 0, 0)},
+		{"never matches", "0 0 31 2 *", at(2024, 1, 1, 0, 0), time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := ParseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseCron: %v", err)
+			}
+			if got := c.Next(tt.from); !got.Equal(tt.want) {
+				t.Errorf("Next(%s) = %s, want %s", tt.from, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCronNextDaylightSaving(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(m time.Month, d, h, min int, zone string) time.Time {
+		offset := -5 * time.Hour
+		if zone == "EDT" {
+			offset = -4 * time.Hour
+		}
+		return time.Date(2024, m, d, h, min, 0, 0, time.UTC).Add(-offset).In(ny)
+	}
+
+	// Clocks go forward on 2024-03-10 at 02:00 EST and back on 2024-11-03
+	// at 02:00 EDT.
+	tests := []struct {
+		name string
+		expr string
+		from time.Time
+		want []time.Time // Successive runs
+	}{
+		{
+			name: "skipped time runs after the gap",
+			expr: "30 2 * * *",
+			from: at(3, 10, 0, 0, "EST"),
+			want: []time.Time{at(3, 10, 3, 0, "EDT"), at(3, 11, 2, 30, "EDT")},
+		},
+		{
+			name: "hourly across the gap",
+			expr: "0 * * * *",
+			from: at(3, 10, 1, 30, "EST"),
+			want: []time.Time{at(3, 10, 3, 0, "EDT"), at(3, 10, 4, 0, "EDT")},
+		},
+		{
+			name: "repeated time runs once",
+			expr: "30 1 * * *",
+			from: at(11, 3, 0, 0, "EDT"),
+			want: []time.Time{at(11, 3, 1, 30, "EDT"), at(11, 4, 1, 30, "EST")},
+		},
+		{
+			name: "inside the repeated hour",
+			expr: "*/20 * * * *",
+			from: at(11, 3, 1, 10, "EST"),
+			want: []time.Time{at(11, 3, 2, 0, "EST")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := ParseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseCron: %v", err)
+			}
+			from := tt.from
+			for _, want := range tt.want {
+				got := c.Next(from)
+				if !got.Equal(want) {
+					t.Fatalf("Next(%s) = %s, want %s", from, got, want)
+				}
+				from = got
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+// =============================================================================
+// FILE: internal/commands/scraper/daemon.go
+// PURPOSE: Daemon mode for the scraper. Runs the scraper's actions on a
+//          ScheduleConfig through app.RunDaemon, starting a fresh scrape run
+//          (and summary) for each scheduled action.
+//          Ports Python runner/manager/daemon.py.
+// =============================================================================
+
+package scraper
+
+import (
+	"context"
+	"fmt"
+
+	"gofscraper/internal/app"
+)
+
+// ---------------------------------------------------------------------------
+// Daemon
+// ---------------------------------------------------------------------------
+
+// RunDaemon runs the scraper repeatedly on the given schedule. Each action
+// keeps its own persisted schedule, so a restart resumes where it left off.
+// Blocks until the context is cancelled or every action has run sc.MaxRuns
+// times, counting runs of an interrupted daemon with the same limit.
+//
+// Parameters:
+//   - ctx: Context for cancellation.
+//   - a: The application instance providing session and config.
+//   - sc: The schedule to run on.
+//
+// Returns:
+//   - Error if the schedule is invalid or the daemon fails to start.
+func (s *Scraper) RunDaemon(ctx context.Context, a *app.App, sc ScheduleConfig) error {
+	if err := sc.Validate(); err != nil {
+		return fmt.Errorf("invalid schedule: %w", err)
+	}
+
+	return a.RunDaemon(ctx, app.DaemonConfig{
+		Schedule: sc,
+		MaxRuns:  sc.MaxRuns,
+		Actions:  s.actions,
+		Areas:    s.areas,
+		Run: func(ctx context.Context, action string) error {
+			return s.forAction(action).Run(ctx, a)
+		},
+	})
+}
@@ -0,0 +1,40 @@
+// =============================================================================
+// FILE: internal/commands/scraper/daemon_test.go
+// PURPOSE: Tests for daemon runs: each scheduled run keeps every option the
+//          daemon's scraper was configured with.
+// =============================================================================
+
+package scraper
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestForActionKeepsOptions(t *testing.T) {
+	s := New(nil, []string{"download", "like"}, []string{"timeline"})
+	sel := UserSelection{Usernames: []string{"alice"}, Excluded: []string{"bob"}}
+	after := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	s.SetUsers(sel)
+	s.SetAfter(after)
+	s.SetMediaSort("date", true)
+
+	run := s.forAction("like")
+	if !slices.Equal(run.actions, []string{"like"}) {
+		t.Errorf("actions = %v, want [like]", run.actions)
+	}
+	if !slices.Equal(run.areas, s.areas) ||
+		!slices.Equal(run.selection.Usernames, sel.Usernames) ||
+		!slices.Equal(run.selection.Excluded, sel.Excluded) ||
+		!run.after.Equal(after) ||
+		run.sortBy != "date" || !run.sortDesc {
+		t.Errorf("run = %+v, want the options of %+v", run, s)
+	}
+	if run.scrCtx == s.scrCtx {
+		t.Error("run shares the daemon's scrape context")
+	}
+	if !slices.Equal(s.actions, []string{"download", "like"}) {
+		t.Errorf("daemon actions changed to %v", s.actions)
+	}
+}
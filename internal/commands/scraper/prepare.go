@@ -1,8 +1,8 @@
 // =============================================================================
 // FILE: internal/commands/scraper/prepare.go
 // PURPOSE: Data preparation for scrape runs. Resolves usernames to user IDs,
-//          fetches subscription lists, and applies user filters (blacklist,
-//          price, activity). Ports Python data/models/selector.py and
+//          fetches subscription lists, and applies user filters (user list,
+//          blacklist). Ports Python data/models/selector.py and
 //          utils/args/accessors/areas.py data loading.
 // =============================================================================
 
@@ -10,9 +10,11 @@ package scraper
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"strings"
 
+	"gofscraper/internal/api"
 	"gofscraper/internal/app"
 	"gofscraper/internal/model"
 )
@@ -21,34 +23,61 @@ import (
 // PrepareData
 // ---------------------------------------------------------------------------
 
-// PrepareData resolves the list of users to scrape by fetching subscriptions
-// and applying configured filters (user list, blacklist, price, activity).
+// UserSelection names the users a scrape run should process.
+type UserSelection struct {
+	Usernames []string // Users to scrape; empty or "ALL" means every subscription
+	Excluded  []string // Users to skip
+}
+
+// PrepareData resolves the list of users to scrape. Named users are looked
+// up by profile; otherwise every subscription is fetched. Excluded users are
+// then removed.
 //
 // Parameters:
 //   - ctx: Context for cancellation.
 //   - a: The application instance providing config and session.
 //   - logger: Structured logger.
+//   - sel: The users to include and exclude.
 //
 // Returns:
 //   - A filtered slice of User pointers ready for processing, and any error.
-func PrepareData(ctx context.Context, a *app.App, logger *slog.Logger) ([]*model.User, error) {
-	cfg := a.Config()
-
-	// Step 1: Determine which usernames to fetch.
-	userList := cfg.Advanced.DefaultUserList
-	blackList := cfg.Advanced.DefaultBlackList
-
+func PrepareData(ctx context.Context, a *app.App, logger *slog.Logger, sel UserSelection) ([]*model.User, error) {
 	logger.Info("preparing user data",
-		"user_list", userList,
-		"blacklist", blackList,
+		"users", sel.Usernames,
+		"excluded", sel.Excluded,
 	)
 
-	// Step 2: Fetch subscriptions.
-	// TODO: Wire to API subscription fetcher based on retrieval mode.
+	client := api.NewClient(a.Session())
+
+	// Step 1: Fetch the named profiles, or all subscriptions.
 	var allUsers []*model.User
+	if selectsAll(sel.Usernames) {
+		subs, err := client.GetSubscriptions(ctx, "all")
+		if err != nil && len(subs) == 0 {
+			return nil, fmt.Errorf("fetch subscriptions: %w", err)
+		}
+		if err != nil {
+			logger.Warn("subscription list incomplete", "error", err)
+		}
+		for i := range subs {
+			allUsers = append(allUsers, &subs[i])
+		}
+	} else {
+		for _, name := range sel.Usernames {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			user, err := client.GetProfile(ctx, name)
+			if err != nil {
+				logger.Error("failed to look up user", "user", name, "error", err)
+				continue
+			}
+			allUsers = append(allUsers, &user)
+		}
+	}
 
-	// Step 3: Apply filters.
-	filtered := filterUsers(allUsers, userList, blackList)
+	// Step 2: Apply filters.
+	filtered := filterUsers(allUsers, sel.Usernames, sel.Excluded)
 
 	logger.Info("user preparation complete",
 		"total_fetched", len(allUsers),
@@ -58,6 +87,19 @@ func PrepareData(ctx context.Context, a *app.App, logger *slog.Logger) ([]*model
 	return filtered, nil
 }
 
+// selectsAll reports whether a username list means every subscription.
+func selectsAll(usernames []string) bool {
+	if len(usernames) == 0 {
+		return true
+	}
+	for _, u := range usernames {
+		if strings.ToUpper(u) == "ALL" {
+			return true
+		}
+	}
+	return false
+}
+
 // ---------------------------------------------------------------------------
 // User filtering
 // ---------------------------------------------------------------------------
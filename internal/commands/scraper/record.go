@@ -0,0 +1,71 @@
+// =============================================================================
+// FILE: internal/commands/scraper/record.go
+// PURPOSE: Database recording for scrape runs. Upserts every fetched post
+//          into its area's table and every selected media item into medias,
+//          without clearing the downloaded flag of earlier downloads.
+//          Ports Python db/operations_/posts.py and media.py batch writes.
+// =============================================================================
+
+package scraper
+
+import (
+	"context"
+	"fmt"
+
+	"gofscraper/internal/app"
+	"gofscraper/internal/db"
+	"gofscraper/internal/model"
+)
+
+// ---------------------------------------------------------------------------
+// Posts
+// ---------------------------------------------------------------------------
+
+// recordPosts upserts posts into the table matching their area: messages,
+// stories (including highlights), or posts.
+func recordPosts(ctx context.Context, conn *db.Conn, posts []*model.Post) error {
+	for _, p := range posts {
+		upsert := db.UpsertPost
+		switch p.ResponseType {
+		case model.ResponseMessages:
+			upsert = db.UpsertMessage
+		case model.ResponseStories, model.ResponseHighlights:
+			upsert = db.UpsertStory
+		}
+		if err := upsert(ctx, conn, p.ID, p.DBText(true), p.Price, p.Paid, p.Archived, p.Date(), p.ModelID); err != nil {
+			return fmt.Errorf("upsert post %d: %w", p.ID, err)
+		}
+	}
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// Media
+// ---------------------------------------------------------------------------
+
+// recordMedia upserts media rows as not yet downloaded. Media already marked
+// downloaded are left alone so their flag is not cleared.
+func recordMedia(ctx context.Context, conn *db.Conn, media []*model.Media, downloaded map[int64]bool) error {
+	for _, m := range media {
+		if downloaded[m.ID] {
+			continue
+		}
+		if err := db.UpsertMedia(ctx, conn, app.MediaRow(m, false)); err != nil {
+			return fmt.Errorf("upsert media %d: %w", m.ID, err)
+		}
+	}
+	return nil
+}
+
+// downloadedIDs returns the IDs of media marked downloaded in the database.
+func downloadedIDs(ctx context.Context, conn *db.Conn) (map[int64]bool, error) {
+	rows, err := db.GetDownloadedMedia(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[int64]bool, len(rows))
+	for _, r := range rows {
+		ids[r.MediaID] = true
+	}
+	return ids, nil
+}
@@ -1,7 +1,8 @@
 // =============================================================================
 // FILE: internal/commands/scraper/schedule.go
 // PURPOSE: Schedule configuration and helpers for daemon mode. Defines
-//          scheduling intervals, next-run computation, and schedule validation.
+//          scheduling intervals and cron expressions, quiet-hour windows,
+//          jitter, next-run computation, and schedule validation.
 //          Ports Python runner/manager/daemon.py scheduling logic.
 // =============================================================================
 
@@ -9,6 +10,8 @@ package scraper
 
 import (
 	"fmt"
+	"math/rand/v2"
+	"strings"
 	"time"
 )
 
@@ -18,14 +21,28 @@ import (
 
 // ScheduleConfig holds the scheduling parameters for daemon mode runs.
 type ScheduleConfig struct {
-	// Interval is the duration between scrape runs.
+	// Interval is the duration between scrape runs. Ignored when Cron is set.
 	Interval time.Duration
 
+	// Cron is an optional 5-field cron expression (e.g., "0 3 * * *").
+	// When set it replaces Interval and StartTime.
+	Cron string
+
+	// QuietHours lists "HH:MM-HH:MM" windows during which no run starts.
+	// A window may wrap past midnight (e.g., "23:00-07:00"). A run that
+	// falls inside a window is pushed to the window's end.
+	QuietHours []string
+
+	// Jitter adds a random delay in [0, Jitter) to every scheduled run so
+	// runs do not start at exactly the same time each cycle.
+	Jitter time.Duration
+
 	// StartTime optionally specifies a fixed time-of-day for the first run
 	// (e.g., "03:00" for 3 AM). Empty means start immediately.
 	StartTime string
 
-	// MaxRuns limits the total number of runs (0 = unlimited).
+	// MaxRuns limits the number of runs of each action, including runs of
+	// an interrupted daemon with the same limit (0 = unlimited).
 	MaxRuns int
 }
 
@@ -49,7 +66,11 @@ func DefaultSchedule() ScheduleConfig {
 // Returns:
 //   - Error if the configuration is invalid.
 func (sc ScheduleConfig) Validate() error {
-	if sc.Interval < 1*time.Minute {
+	if sc.Cron != "" {
+		if _, err := ParseCron(sc.Cron); err != nil {
+			return err
+		}
+	} else if sc.Interval < 1*time.Minute {
 		return fmt.Errorf("schedule interval must be at least 1 minute, got %s", sc.Interval)
 	}
 	if sc.StartTime != "" {
@@ -57,6 +78,12 @@ func (sc ScheduleConfig) Validate() error {
 			return fmt.Errorf("invalid start_time %q: %w", sc.StartTime, err)
 		}
 	}
+	if _, err := parseQuietHours(sc.QuietHours); err != nil {
+		return err
+	}
+	if sc.Jitter < 0 {
+		return fmt.Errorf("jitter must be non-negative, got %s", sc.Jitter)
+	}
 	if sc.MaxRuns < 0 {
 		return fmt.Errorf("max_runs must be non-negative, got %d", sc.MaxRuns)
 	}
@@ -67,7 +94,10 @@ func (sc ScheduleConfig) Validate() error {
 // Next run computation
 // ---------------------------------------------------------------------------
 
-// NextRun calculates the time of the next scheduled run.
+// NextRun calculates the time of the next scheduled run: the next cron
+// match or lastRun plus the interval, plus jitter, moved out of any quiet
+// window so jitter never lands a run inside one. An invalid configuration
+// falls back to running now; call Validate first.
 //
 // Parameters:
 //   - lastRun: The time the last run started. Zero value means no previous run.
@@ -75,6 +105,33 @@ func (sc ScheduleConfig) Validate() error {
 // Returns:
 //   - The time.Time of the next run.
 func (sc ScheduleConfig) NextRun(lastRun time.Time) time.Time {
+	next := sc.baseNextRun(lastRun)
+	if sc.Jitter > 0 {
+		next = next.Add(rand.N(sc.Jitter))
+	}
+	if windows, err := parseQuietHours(sc.QuietHours); err == nil {
+		next = leaveQuietHours(next, windows)
+	}
+	return next
+}
+
+// baseNextRun computes the next run from the cron expression or interval
+// alone.
+func (sc ScheduleConfig) baseNextRun(lastRun time.Time) time.Time {
+	if sc.Cron != "" {
+		cron, err := ParseCron(sc.Cron)
+		if err != nil {
+			return time.Now()
+		}
+		from := lastRun
+		if from.IsZero() {
+			from = time.Now()
+		}
+		if next := cron.Next(from); !next.IsZero() {
+			return next
+		}
+		return time.Now()
+	}
 	if lastRun.IsZero() && sc.StartTime != "" {
 		return nextTimeOfDay(sc.StartTime)
 	}
@@ -84,6 +141,28 @@ func (sc ScheduleConfig) NextRun(lastRun time.Time) time.Time {
 	return lastRun.Add(sc.Interval)
 }
 
+// String describes the schedule. Two configurations with the same string
+// compute the same run times, so it is used to detect schedule changes
+// between daemon restarts.
+func (sc ScheduleConfig) String() string {
+	var b strings.Builder
+	if sc.Cron != "" {
+		fmt.Fprintf(&b, "cron %s", sc.Cron)
+	} else {
+		fmt.Fprintf(&b, "every %s", sc.Interval)
+		if sc.StartTime != "" {
+			fmt.Fprintf(&b, " from %s", sc.StartTime)
+		}
+	}
+	if len(sc.QuietHours) > 0 {
+		fmt.Fprintf(&b, ", quiet %s", strings.Join(sc.QuietHours, ","))
+	}
+	if sc.Jitter > 0 {
+		fmt.Fprintf(&b, ", jitter %s", sc.Jitter)
+	}
+	return b.String()
+}
+
 // SleepDuration calculates how long to sleep before the next run.
 //
 // Parameters:
@@ -127,3 +206,82 @@ func nextTimeOfDay(s string) time.Time {
 	}
 	return next
 }
+
+// ---------------------------------------------------------------------------
+// Quiet hours
+// ---------------------------------------------------------------------------
+
+// quietWindow is a daily window given as minutes since midnight. When end
+// is before start the window wraps past midnight.
+type quietWindow struct {
+	start, end int
+}
+
+// parseQuietHours parses "HH:MM-HH:MM" window strings.
+func parseQuietHours(specs []string) ([]quietWindow, error) {
+	windows := make([]quietWindow, 0, len(specs))
+	for _, spec := range specs {
+		from, to, ok := strings.Cut(strings.TrimSpace(spec), "-")
+		if !ok {
+			return nil, fmt.Errorf("invalid quiet hours %q: expected HH:MM-HH:MM", spec)
+		}
+		start, err := parseTimeOfDay(strings.TrimSpace(from))
+		if err != nil {
+			return nil, fmt.Errorf("invalid quiet hours %q: %w", spec, err)
+		}
+		end, err := parseTimeOfDay(strings.TrimSpace(to))
+		if err != nil {
+			return nil, fmt.Errorf("invalid quiet hours %q: %w", spec, err)
+		}
+		w := quietWindow{
+			start: start.Hour()*60 + start.Minute(),
+			end:   end.Hour()*60 + end.Minute(),
+		}
+		if w.start == w.end {
+			return nil, fmt.Errorf("invalid quiet hours %q: window is empty", spec)
+		}
+		windows = append(windows, w)
+	}
+	return windows, nil
+}
+
+// leaveQuietHours moves t to the end of the quiet window containing it,
+// repeating while the new time falls in another window.
+func leaveQuietHours(t time.Time, windows []quietWindow) time.Time {
+	for range len(windows) + 1 {
+		moved := false
+		for _, w := range windows {
+			if end, ok := w.endAfter(t); ok {
+				t = end
+				moved = true
+			}
+		}
+		if !moved {
+			break
+		}
+	}
+	return t
+}
+
+// endAfter reports whether t falls inside the window and, if so, the time
+// the window ends.
+func (w quietWindow) endAfter(t time.Time) (time.Time, bool) {
+	m := t.Hour()*60 + t.Minute()
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	endToday := midnight.Add(time.Duration(w.end) * time.Minute)
+
+	if w.start < w.end {
+		if m >= w.start && m < w.end {
+			return endToday, true
+		}
+		return t, false
+	}
+	// Wrapping window, e.g. 23:00-07:00.
+	switch {
+	case m >= w.start:
+		return endToday.AddDate(0, 0, 1), true
+	case m < w.end:
+		return endToday, true
+	}
+	return t, false
+}
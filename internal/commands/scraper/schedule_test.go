@@ -0,0 +1,31 @@
+// =============================================================================
+// FILE: internal/commands/scraper/schedule_test.go
+// PURPOSE: Tests for schedule computation: jitter is applied before quiet
+//          hours, so a jittered run never lands inside a quiet window.
+// =============================================================================
+
+package scraper
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextRunJitterRespectsQuietHours(t *testing.T) {
+	sc := ScheduleConfig{
+		Interval:   50 * time.Minute,
+		Jitter:     30 * time.Minute,
+		QuietHours: []string{"23:00-07:00"},
+	}
+	last := time.Date(2024, 5, 1, 22, 0, 0, 0, time.Local)
+	quietStart := time.Date(2024, 5, 1, 23, 0, 0, 0, time.Local)
+	quietEnd := time.Date(2024, 5, 2, 7, 0, 0, 0, time.Local)
+
+	// The base run is 22:50; jitter of up to 30 minutes crosses 23:00.
+	for range 500 {
+		next := sc.NextRun(last)
+		if !next.Before(quietStart) && next.Before(quietEnd) {
+			t.Fatalf("NextRun = %s, inside quiet hours", next.Format(time.RFC3339))
+		}
+	}
+}
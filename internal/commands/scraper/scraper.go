@@ -10,11 +10,18 @@ package scraper
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
+	"strings"
+	"time"
 
+	"gofscraper/internal/api"
 	"gofscraper/internal/app"
 	cmdutils "gofscraper/internal/commands/utils"
+	"gofscraper/internal/db"
+	"gofscraper/internal/filter"
 	"gofscraper/internal/model"
 )
 
@@ -22,12 +29,22 @@ import (
 // Scraper
 // ---------------------------------------------------------------------------
 
+// DefaultAreas are the content areas scraped when none are given.
+var DefaultAreas = []string{
+	"timeline", "pinned", "archived", "streams",
+	"stories", "highlights", "messages", "purchased",
+}
+
 // Scraper orchestrates a complete scrape run across one or more users.
 type Scraper struct {
-	logger  *slog.Logger
-	scrCtx  *cmdutils.ScrapeContext
-	actions []string
-	areas   []string
+	logger    *slog.Logger
+	scrCtx    *cmdutils.ScrapeContext
+	actions   []string
+	areas     []string
+	selection UserSelection
+	after     time.Time // Date floor; zero fetches full history
+	sortBy    string    // Media sort key; empty keeps API order
+	sortDesc  bool
 }
 
 // New creates a new Scraper with the given configuration.
@@ -43,6 +60,12 @@ func New(logger *slog.Logger, actions, areas []string) *Scraper {
 	if logger == nil {
 		logger = slog.Default()
 	}
+	if len(actions) == 0 {
+		actions = []string{"download"}
+	}
+	if len(areas) == 0 || (len(areas) == 1 && strings.EqualFold(areas[0], "all")) {
+		areas = DefaultAreas
+	}
 	return &Scraper{
 		logger:  logger,
 		scrCtx:  cmdutils.NewScrapeContext(),
@@ -51,6 +74,31 @@ func New(logger *slog.Logger, actions, areas []string) *Scraper {
 	}
 }
 
+// SetUsers sets which users are scraped and which are skipped.
+func (s *Scraper) SetUsers(sel UserSelection) {
+	s.selection = sel
+}
+
+// SetAfter sets the date floor; content older than after is not fetched.
+func (s *Scraper) SetAfter(after time.Time) {
+	s.after = after
+}
+
+// SetMediaSort sets the order media are processed in.
+func (s *Scraper) SetMediaSort(sortBy string, descending bool) {
+	s.sortBy = sortBy
+	s.sortDesc = descending
+}
+
+// forAction returns a fresh Scraper for one action with every other option
+// of s, as the daemon starts for each scheduled run.
+func (s *Scraper) forAction(action string) *Scraper {
+	run := *s
+	run.scrCtx = cmdutils.NewScrapeContext()
+	run.actions = []string{action}
+	return &run
+}
+
 // Run executes the full scrape pipeline.
 //
 // Parameters:
@@ -66,7 +114,7 @@ func (s *Scraper) Run(ctx context.Context, a *app.App) error {
 	)
 
 	// Stage 1: Prepare data — resolve users and apply filters.
-	users, err := PrepareData(ctx, a, s.logger)
+	users, err := PrepareData(ctx, a, s.logger, s.selection)
 	if err != nil {
 		return fmt.Errorf("prepare data: %w", err)
 	}
@@ -101,36 +149,94 @@ func (s *Scraper) Run(ctx context.Context, a *app.App) error {
 	return nil
 }
 
-// processUser handles the scrape pipeline for a single user:
-// fetch posts, filter, and dispatch actions.
+// processUser handles the scrape pipeline for a single user: resume any
+// leftover download queue, fetch posts, filter posts and media, record them
+// in the model's database, and dispatch actions.
 func (s *Scraper) processUser(ctx context.Context, a *app.App, user *model.User) error {
+	client := api.NewClient(a.Session())
+
+	// Finish an interrupted run's downloads before fetching new content.
+	if slices.Contains(s.actions, "download") {
+		if err := s.resumeQueue(ctx, a, user); err != nil {
+			return fmt.Errorf("resume download queue: %w", err)
+		}
+	}
+
 	// Fetch posts for all configured areas.
-	var posts []*model.Post
-	for _, area := range s.areas {
-		s.logger.Debug(fmt.Sprintf(cmdutils.MsgFetchingPosts, area, user.Name))
-		// TODO: Wire to API post fetcher per area.
-		_ = area
+	fetched, err := s.fetchPosts(ctx, client, user)
+	if err != nil && len(fetched) == 0 {
+		return fmt.Errorf("fetch posts: %w", err)
+	}
+	if err != nil {
+		s.logger.Warn("post data incomplete", "user", user.Name, "error", err)
 	}
 
-	if len(posts) == 0 {
+	fetched = filter.ChainPosts(
+		filter.ByPostDupe(),
+		filter.ByPostDate(s.after, time.Time{}),
+	)(fetched)
+	if len(fetched) == 0 {
 		s.logger.Info(fmt.Sprintf(cmdutils.MsgNoPosts, user.Name))
 		return nil
 	}
 
+	posts := make([]*model.Post, len(fetched))
+	for i := range fetched {
+		p := &fetched[i]
+		p.Username = user.Name
+		p.LinkMedia()
+		posts[i] = p
+	}
 	s.scrCtx.AddPosts(posts)
 
-	// Collect all media from posts.
+	// Collect and filter media from posts.
 	var allMedia []*model.Media
 	for _, post := range posts {
 		allMedia = append(allMedia, post.ViewableMedia()...)
 	}
 	s.scrCtx.MediaFound.Add(int64(len(allMedia)))
+	conn, err := app.OpenModelDB(user.Name)
+	if err != nil {
+		return fmt.Errorf("open database: %w", err)
+	}
+	if err := db.RegisterModel(ctx, conn, user.ID); err != nil {
+		return fmt.Errorf("register model: %w", err)
+	}
+	a.SelectQuality(allMedia)
+	if err := app.LoadMediaInfo(ctx, conn, allMedia); err != nil {
+		s.logger.Warn("probed media info unavailable", "user", user.Name, "error", err)
+	}
+	media := filter.ChainMedia(
+		app.MediaFilters(),
+		filter.SortMedia(s.sortBy, s.sortDesc),
+	)(allMedia)
+	for _, m := range media {
+		app.ResolveMediaPath(m)
+	}
+
+	// Record everything found before downloading.
+	downloaded, err := downloadedIDs(ctx, conn)
+	if err != nil {
+		return fmt.Errorf("load downloaded media: %w", err)
+	}
+	if err := recordPosts(ctx, conn, posts); err != nil {
+		return fmt.Errorf("record posts: %w", err)
+	}
+	if err := recordMedia(ctx, conn, media, downloaded); err != nil {
+		return fmt.Errorf("record media: %w", err)
+	}
 
 	// Dispatch configured actions.
 	for _, action := range s.actions {
 		if ctx.Err() != nil {
 			return ctx.Err()
 		}
+		if action == "download" {
+			if err := s.download(ctx, a, user, media, downloaded); err != nil {
+				return fmt.Errorf("download for user %s: %w", user.Name, err)
+			}
+			continue
+		}
 		if err := a.RunAction(ctx, action, s.areas, []string{user.Name}); err != nil {
 			return fmt.Errorf("action %s for user %s: %w", action, user.Name, err)
 		}
@@ -139,6 +245,60 @@ func (s *Scraper) processUser(ctx context.Context, a *app.App, user *model.User)
 	return nil
 }
 
+// fetchPosts fetches every configured area for a user. A failing area does
+// not stop the others; its error is joined into the returned error.
+func (s *Scraper) fetchPosts(ctx context.Context, client *api.Client, user *model.User) ([]model.Post, error) {
+	var all []model.Post
+	var errs []error
+	for _, area := range s.areas {
+		if ctx.Err() != nil {
+			return all, ctx.Err()
+		}
+		s.logger.Debug(fmt.Sprintf(cmdutils.MsgFetchingPosts, area, user.Name))
+		posts, err := app.FetchAreaPosts(ctx, client, area, user.ID, s.after)
+		all = append(all, posts...)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", area, err))
+		}
+	}
+	return all, errors.Join(errs...)
+}
+
+// download fetches the media not already downloaded and tallies each
+// item's outcome in the scrape context.
+func (s *Scraper) download(ctx context.Context, a *app.App, user *model.User, media []*model.Media, downloaded map[int64]bool) error {
+	pending := media
+	if f := filter.ByPreviousDownload(downloaded, true); f != nil {
+		pending = f(media)
+	}
+	s.scrCtx.MediaSkipped.Add(int64(len(media) - len(pending)))
+	if len(pending) == 0 {
+		return nil
+	}
+
+	if _, err := a.DownloadMedia(ctx, user.Name, pending); err != nil {
+		return err
+	}
+	for _, m := range pending {
+		s.scrCtx.RecordMediaResult(m.DownloadStatusString())
+	}
+	return nil
+}
+
+// resumeQueue downloads the media left queued by an interrupted run and
+// tallies their outcomes.
+func (s *Scraper) resumeQueue(ctx context.Context, a *app.App, user *model.User) error {
+	media, _, err := a.ResumeQueue(ctx, user.Name)
+	if err != nil {
+		return err
+	}
+	s.scrCtx.MediaFound.Add(int64(len(media)))
+	for _, m := range media {
+		s.scrCtx.RecordMediaResult(m.DownloadStatusString())
+	}
+	return nil
+}
+
 // Context returns the scrape context with accumulated results.
 //
 // Returns:
@@ -0,0 +1,279 @@
+// =============================================================================
+// FILE: internal/commands/scraper/scraper_test.go
+// PURPOSE: Integration tests of the per-user scrape pipeline against the fake
+//          API: an interrupted run's queue is resumed before new posts are
+//          fetched, unviewable and already downloaded media are skipped, and
+//          posts, media and downloads are recorded in the model's database.
+//          A download batch cancelled mid-run leaves its queue for the next.
+// =============================================================================
+
+package scraper
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"gofscraper/internal/api/apitest"
+	"gofscraper/internal/app"
+	"gofscraper/internal/db"
+	"gofscraper/internal/model"
+)
+
+func TestProcessUser(t *testing.T) {
+	ctx := context.Background()
+	srv := apitest.NewServer(t)
+	srv.Page(t, "/api2/v2/users/77/posts", "", "scrape_timeline.json")
+	for _, id := range []string{"9001", "9002", "9003", "9100"} {
+		srv.File("/files/"+id+".jpg", []byte("img"+id))
+	}
+	a, conn, save := newTestApp(t)
+
+	// Seed the database as an interrupted run left it: media 9001 already
+	// downloaded, media 9100 in flight in the download queue.
+	if err := db.UpsertMedia(ctx, conn, db.MediaRow{MediaID: 9001, PostID: 8001, ModelID: 77, Downloaded: true}); err != nil {
+		t.Fatalf("UpsertMedia: %v", err)
+	}
+	queued := testMedia(srv, save, 8000, 9100)
+	mediaJSON, err := json.Marshal(queued)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.EnqueueDownloads(ctx, conn, []db.QueueRow{{
+		MediaID: 9100, PostID: 8000, ModelID: 77, FilePath: queued.FilePath, Media: string(mediaJSON),
+	}}); err != nil {
+		t.Fatalf("EnqueueDownloads: %v", err)
+	}
+	if err := db.SetQueueState(ctx, conn, 9100, db.QueueActive); err != nil {
+		t.Fatalf("SetQueueState: %v", err)
+	}
+
+	s := New(nil, []string{"download"}, []string{"timeline"})
+	if err := s.processUser(ctx, a, &model.User{ID: 77, Name: "alice"}); err != nil {
+		t.Fatalf("processUser: %v", err)
+	}
+
+	// The queue is drained before the timeline is fetched.
+	reqs := srv.Requests()
+	checkResumedFirst(t, reqs, "/files/9100.jpg")
+	for _, skipped := range []string{"/files/9001.jpg", "/files/9004.mp4"} {
+		if slices.Contains(reqs, skipped) {
+			t.Errorf("requested %s, want it skipped", skipped)
+		}
+	}
+
+	for _, id := range []int64{8001, 8002} {
+		if _, err := db.GetPost(ctx, conn, id); err != nil {
+			t.Errorf("post %d not recorded: %v", id, err)
+		}
+	}
+
+	for postID, want := range map[int64][]int64{8000: {9100}, 8001: {9001, 9002}, 8002: {9003}} {
+		rows, err := db.GetMediaByPostID(ctx, conn, postID)
+		if err != nil {
+			t.Fatalf("GetMediaByPostID(%d): %v", postID, err)
+		}
+		var got []int64
+		for _, r := range rows {
+			got = append(got, r.MediaID)
+			if !r.Downloaded {
+				t.Errorf("media %d not marked downloaded", r.MediaID)
+			}
+			if r.MediaID == 9001 {
+				continue
+			}
+			path := filepath.Join(r.Directory.String, r.Filename.String)
+			if data, err := os.ReadFile(path); err != nil || string(data) != "img"+r.Filename.String[:4] {
+				t.Errorf("media %d file %s: %q, %v", r.MediaID, path, data, err)
+			}
+		}
+		slices.Sort(got)
+		if !slices.Equal(got, want) {
+			t.Errorf("post %d media = %v, want %v", postID, got, want)
+		}
+	}
+
+	checkQueueStates(t, conn, nil)
+}
+
+func TestCancelledBatchResumesBeforeFetch(t *testing.T) {
+	srv := apitest.NewServer(t)
+	srv.Page(t, "/api2/v2/users/77/posts", "", "scrape_timeline.json")
+	for _, id := range []string{"9002", "9003", "9201", "9203", "9204"} {
+		srv.File("/files/"+id+".jpg", []byte("img"+id))
+	}
+	// The first request for 9202 stalls until the batch is cancelled; the
+	// resumed run gets the file.
+	stalled := make(chan struct{})
+	var stalls atomic.Int32
+	srv.Handle("/files/9202.jpg", func(w http.ResponseWriter, r *http.Request) {
+		if stalls.Add(1) == 1 {
+			close(stalled)
+			<-r.Context().Done()
+			return
+		}
+		w.Write([]byte("img9202"))
+	})
+	a, conn, save := newTestApp(t)
+
+	media := []*model.Media{
+		testMedia(srv, save, 8100, 9201),
+		testMedia(srv, save, 8100, 9202),
+		testMedia(srv, save, 8100, 9203),
+		testMedia(srv, save, 8100, 9204),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		a.DownloadMedia(ctx, "alice", media)
+	}()
+	<-stalled
+	cancel()
+	<-done
+	before := len(srv.Requests())
+
+	// The finished download is recorded; the in-flight and unstarted ones
+	// stay queued.
+	checkQueueStates(t, conn, map[int64]db.QueueState{
+		9202: db.QueueActive,
+		9203: db.QueuePending,
+		9204: db.QueuePending,
+	})
+
+	s := New(nil, []string{"download"}, []string{"timeline"})
+	if err := s.processUser(context.Background(), a, &model.User{ID: 77, Name: "alice"}); err != nil {
+		t.Fatalf("processUser: %v", err)
+	}
+
+	// The in-flight download was re-queued and every queued one ran before
+	// the timeline was fetched.
+	reqs := srv.Requests()
+	checkResumedFirst(t, reqs, "/files/9202.jpg", "/files/9203.jpg", "/files/9204.jpg")
+	if slices.Contains(reqs[before:], "/files/9201.jpg") {
+		t.Errorf("requests = %q, want the finished download not fetched again", reqs[before:])
+	}
+	rows, err := db.GetMediaByPostID(context.Background(), conn, 8100)
+	if err != nil {
+		t.Fatalf("GetMediaByPostID: %v", err)
+	}
+	if len(rows) != 4 {
+		t.Errorf("recorded %d media, want 4", len(rows))
+	}
+	for _, r := range rows {
+		if !r.Downloaded {
+			t.Errorf("media %d not marked downloaded", r.MediaID)
+		}
+	}
+	checkQueueStates(t, conn, nil)
+}
+
+// newTestApp initializes an App whose config saves under a temporary
+// directory, and opens the database of model "alice".
+func newTestApp(t *testing.T) (*app.App, *db.Conn, string) {
+	t.Helper()
+	dir := t.TempDir()
+	save := filepath.Join(dir, "save")
+	writeConfig(t, dir, map[string]any{
+		"metadata":            filepath.Join(dir, "data", "{model_username}"),
+		"file_options":        map[string]any{"save_location": save},
+		"performance_options": map[string]any{"download_sems": 1},
+		"advanced_options":    map[string]any{"temp_dir": filepath.Join(dir, "tmp")},
+	})
+	a := app.New()
+	if err := a.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	conn, err := app.OpenModelDB("alice")
+	if err != nil {
+		t.Fatalf("OpenModelDB: %v", err)
+	}
+	t.Cleanup(func() { db.Close("alice") })
+	return a, conn, save
+}
+
+// testMedia returns a photo of model alice served by srv at /files/<id>.jpg.
+func testMedia(srv *apitest.Server, save string, postID, id int64) *model.Media {
+	name := strconv.FormatInt(id, 10) + ".jpg"
+	return &model.Media{
+		ID:       id,
+		PostID:   postID,
+		ModelID:  77,
+		Username: "alice",
+		Type:     "photo",
+		CanView:  true,
+		RawURL:   srv.URL + "/files/" + name,
+		FilePath: filepath.Join(save, "alice", name),
+	}
+}
+
+// checkResumedFirst checks every path in resumed was requested before the
+// timeline.
+func checkResumedFirst(t *testing.T, reqs []string, resumed ...string) {
+	t.Helper()
+	fetched := slices.IndexFunc(reqs, func(r string) bool {
+		return strings.HasPrefix(r, "/api2/v2/users/77/posts")
+	})
+	for _, path := range resumed {
+		if i := lastIndex(reqs, path); i < 0 || fetched < 0 || i > fetched {
+			t.Errorf("requests = %q, want %s before the timeline fetch", reqs, path)
+		}
+	}
+}
+
+// lastIndex returns the index of the last request for path, or -1.
+func lastIndex(reqs []string, path string) int {
+	for i := len(reqs) - 1; i >= 0; i-- {
+		if reqs[i] == path {
+			return i
+		}
+	}
+	return -1
+}
+
+// checkQueueStates checks the unfinished download queue entries and their
+// states; nil wants the queue drained.
+func checkQueueStates(t *testing.T, conn *db.Conn, want map[int64]db.QueueState) {
+	t.Helper()
+	rows, err := db.GetQueuedDownloads(context.Background(), conn)
+	if err != nil {
+		t.Fatalf("GetQueuedDownloads: %v", err)
+	}
+	got := make(map[int64]db.QueueState, len(rows))
+	for _, r := range rows {
+		got[r.MediaID] = r.State
+	}
+	if len(got) != len(want) {
+		t.Errorf("download queue = %v, want %v", got, want)
+		return
+	}
+	for id, state := range want {
+		if got[id] != state {
+			t.Errorf("download queue = %v, want %v", got, want)
+			return
+		}
+	}
+}
+
+// writeConfig writes cfg as the config file under dir and points the config
+// loader at it.
+func writeConfig(t *testing.T, dir string, cfg map[string]any) {
+	t.Helper()
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("OF_CONFIG_DIR", dir)
+	t.Setenv("OF_CONFIG_FILE", path)
+}
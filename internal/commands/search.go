@@ -0,0 +1,246 @@
+// =============================================================================
+// FILE: internal/commands/search.go
+// PURPOSE: Search command. Runs a full-text query over the archived post and
+//          message text of one or all model databases and prints the hits
+//          as a table, as JSON, or as the paths of their downloaded media.
+// =============================================================================
+
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+
+	"gofscraper/internal/app"
+	cmdutils "gofscraper/internal/commands/utils"
+	"gofscraper/internal/db"
+	"gofscraper/internal/model"
+	"gofscraper/internal/tui/sections"
+)
+
+// Search output formats.
+const (
+	SearchFormatTable = "table"
+	SearchFormatJSON  = "json"
+	SearchFormatPaths = "paths"
+)
+
+// SearchFormats lists the accepted output formats.
+var SearchFormats = []string{SearchFormatTable, SearchFormatJSON, SearchFormatPaths}
+
+// ---------------------------------------------------------------------------
+// SearchCommand
+// ---------------------------------------------------------------------------
+
+// SearchCommand searches archived post text.
+type SearchCommand struct {
+	cmdutils.CommandBase
+	query  db.SearchQuery
+	format string
+	out    io.Writer // Where results are printed (os.Stdout)
+}
+
+// NewSearchCommand creates a new SearchCommand.
+//
+// Parameters:
+//   - logger: Structured logger for output.
+//
+// Returns:
+//   - A configured SearchCommand.
+func NewSearchCommand(logger *slog.Logger) *SearchCommand {
+	return &SearchCommand{
+		CommandBase: cmdutils.NewCommandBase(logger),
+		format:      SearchFormatTable,
+		out:         os.Stdout,
+	}
+}
+
+// SetQuery sets the text query and filters.
+func (s *SearchCommand) SetQuery(q db.SearchQuery) {
+	s.query = q
+}
+
+// SetFormat sets the output format: table, json, or paths.
+func (s *SearchCommand) SetFormat(format string) {
+	s.format = format
+}
+
+// Name returns the command name.
+func (s *SearchCommand) Name() string {
+	return "search"
+}
+
+// searchResult is one hit with the model it came from and its downloaded
+// media.
+type searchResult struct {
+	User      string   `json:"user"`
+	Area      string   `json:"area"`
+	PostID    int64    `json:"post_id"`
+	CreatedAt string   `json:"created_at"`
+	Price     float64  `json:"price"`
+	Paid      bool     `json:"paid"`
+	Snippet   string   `json:"snippet"`
+	Text      string   `json:"text"`
+	Files     []string `json:"files"`
+	rank      float64
+}
+
+// Run searches the given models.
+//
+// Parameters:
+//   - ctx: Context for cancellation.
+//   - a: The application instance providing config.
+//   - args: Model usernames or .db paths (all models when empty).
+//
+// Returns:
+//   - Error if the query is invalid or no database could be searched.
+func (s *SearchCommand) Run(ctx context.Context, _ *app.App, args []string) error {
+	s.LogStart(s.Name(), args)
+	defer s.LogDone(s.Name())
+
+	targets, err := targetDBs(ctx, args)
+	if err != nil {
+		return err
+	}
+	if len(targets) == 0 {
+		return fmt.Errorf("no databases found to search")
+	}
+
+	var results []searchResult
+	for _, name := range sortedKeys(targets) {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		found, err := s.searchModel(ctx, name, targets[name])
+		if err != nil {
+			// A malformed query fails the same way for every model.
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		results = append(results, found...)
+	}
+
+	// Hits from several models are interleaved by rank.
+	sort.SliceStable(results, func(i, j int) bool { return results[i].rank < results[j].rank })
+	if s.query.Limit > 0 && len(results) > s.query.Limit {
+		results = results[:s.query.Limit]
+	}
+
+	switch s.format {
+	case SearchFormatJSON:
+		enc := json.NewEncoder(s.out)
+		enc.SetIndent("", "  ")
+		if results == nil {
+			results = []searchResult{}
+		}
+		return enc.Encode(results)
+	case SearchFormatPaths:
+		for _, r := range results {
+			for _, f := range r.Files {
+				fmt.Fprintln(s.out, f)
+			}
+		}
+		return nil
+	default:
+		s.printTable(results)
+		return nil
+	}
+}
+
+// searchModel runs the query against one model and attaches the paths of
+// each hit's downloaded media.
+func (s *SearchCommand) searchModel(ctx context.Context, name, dbPath string) ([]searchResult, error) {
+	conn, err := db.Open(name, dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", dbPath, err)
+	}
+	hits, err := db.SearchPosts(ctx, conn, s.query)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]searchResult, 0, len(hits))
+	for _, h := range hits {
+		r := searchResult{
+			User:      name,
+			Area:      h.Area,
+			PostID:    h.PostID,
+			CreatedAt: h.CreatedAt,
+			Price:     h.Price,
+			Paid:      h.Paid,
+			Snippet:   h.Snippet,
+			Text:      h.Text,
+			Files:     []string{},
+			rank:      h.Rank,
+		}
+		media, err := db.GetMediaByPostID(ctx, conn, h.PostID)
+		if err != nil {
+			return nil, fmt.Errorf("load media of post %d: %w", h.PostID, err)
+		}
+		for _, row := range media {
+			if !row.Downloaded || postTable(row.APIType.String) != h.Area {
+				continue
+			}
+			if path, ok := rowPath(row); ok {
+				r.Files = append(r.Files, path)
+			}
+		}
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+// postTable returns the table holding the post of a media row with the
+// given api_type (response type).
+func postTable(apiType string) string {
+	switch model.ResponseType(apiType) {
+	case model.ResponseMessages:
+		return "messages"
+	case model.ResponseStories, model.ResponseHighlights:
+		return "stories"
+	}
+	return "posts"
+}
+
+// ---------------------------------------------------------------------------
+// Report
+// ---------------------------------------------------------------------------
+
+// searchColumns are the columns of the table output.
+var searchColumns = []sections.Column{
+	{Key: "user", Title: "User", Width: 20},
+	{Key: "area", Title: "Area", Width: 8},
+	{Key: "post_id", Title: "Post ID", Width: 14},
+	{Key: "date", Title: "Date", Width: 10},
+	{Key: "price", Title: "Price", Width: 7},
+	{Key: "files", Title: "Files", Width: 5},
+	{Key: "snippet", Title: "Match", Width: 70},
+}
+
+// printTable prints the hits as a table.
+func (s *SearchCommand) printTable(results []searchResult) {
+	rows := make([]sections.Row, 0, len(results))
+	for _, r := range results {
+		date := r.CreatedAt
+		if len(date) > 10 {
+			date = date[:10]
+		}
+		rows = append(rows, sections.Row{
+			"user":    r.User,
+			"area":    r.Area,
+			"post_id": fmt.Sprintf("%d", r.PostID),
+			"date":    date,
+			"price":   fmt.Sprintf("%.2f", r.Price),
+			"files":   fmt.Sprintf("%d", len(r.Files)),
+			"snippet": r.Snippet,
+		})
+	}
+	console := sections.NewConsoleSection(searchColumns)
+	console.SetWriter(s.out)
+	console.SetRows(rows)
+	console.Print()
+}
@@ -0,0 +1,131 @@
+// =============================================================================
+// FILE: internal/commands/search_test.go
+// PURPOSE: Tests for the search command's output formats: the table, JSON
+//          with each hit's downloaded files, and the bare file paths.
+// =============================================================================
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gofscraper/internal/db"
+)
+
+// newSearchDB writes a database with a post and a message mentioning the
+// beach, each with downloaded media, and returns its path.
+func newSearchDB(t *testing.T) (dbPath, dir string) {
+	t.Helper()
+	ctx := context.Background()
+	dir = t.TempDir()
+	dbPath = filepath.Join(dir, "user_data.db")
+	conn, err := db.Open(dbPath, dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close(dbPath) })
+
+	if err := db.UpsertPost(ctx, conn, 1, "Beach day", 0, false, false, "2024-01-10T12:00:00", 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.UpsertMessage(ctx, conn, 2, "More beach pics", 5, true, false, "2024-02-10T12:00:00", 0); err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range []db.MediaRow{
+		{MediaID: 11, PostID: 1, APIType: db.NullString("timeline"), Filename: db.NullString("a.jpg"), Downloaded: true},
+		{MediaID: 12, PostID: 1, APIType: db.NullString("timeline"), Filename: db.NullString("b.jpg")}, // Not downloaded
+		{MediaID: 21, PostID: 2, APIType: db.NullString("messages"), Filename: db.NullString("c.mp4"), Downloaded: true},
+	} {
+		m.Directory = db.NullString(dir)
+		if err := db.UpsertMedia(ctx, conn, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dbPath, dir
+}
+
+func TestSearchOutputFormats(t *testing.T) {
+	dbPath, dir := newSearchDB(t)
+
+	run := func(t *testing.T, format string, q db.SearchQuery) (string, error) {
+		t.Helper()
+		var out bytes.Buffer
+		cmd := NewSearchCommand(slog.New(slog.DiscardHandler))
+		cmd.out = &out
+		cmd.SetFormat(format)
+		cmd.SetQuery(q)
+		err := cmd.Run(context.Background(), nil, []string{dbPath})
+		return out.String(), err
+	}
+
+	t.Run("json", func(t *testing.T) {
+		out, err := run(t, SearchFormatJSON, db.SearchQuery{Match: "beach", HighlightStart: "<", HighlightEnd: ">"})
+		if err != nil {
+			t.Fatalf("Run: %v", err)
+		}
+		var results []searchResult
+		if err := json.Unmarshal([]byte(out), &results); err != nil {
+			t.Fatalf("output is not JSON: %v\n%s", err, out)
+		}
+		files := make(map[string][]string, len(results))
+		for _, r := range results {
+			if r.User != dbPath || !strings.Contains(strings.ToLower(r.Snippet), "<beach>") {
+				t.Errorf("result %+v, want user %s and a highlighted snippet", r, dbPath)
+			}
+			files[r.Area] = r.Files
+		}
+		if len(results) != 2 ||
+			strings.Join(files["posts"], ",") != filepath.Join(dir, "a.jpg") ||
+			strings.Join(files["messages"], ",") != filepath.Join(dir, "c.mp4") {
+			t.Errorf("results = %+v, want the post with a.jpg and the message with c.mp4", results)
+		}
+	})
+
+	t.Run("json without hits", func(t *testing.T) {
+		out, err := run(t, SearchFormatJSON, db.SearchQuery{Match: "mountain"})
+		if err != nil {
+			t.Fatalf("Run: %v", err)
+		}
+		if strings.TrimSpace(out) != "[]" {
+			t.Errorf("output = %q, want an empty JSON array", out)
+		}
+	})
+
+	t.Run("paths", func(t *testing.T) {
+		out, err := run(t, SearchFormatPaths, db.SearchQuery{Match: "beach", Areas: []string{"messages"}})
+		if err != nil {
+			t.Fatalf("Run: %v", err)
+		}
+		if out != filepath.Join(dir, "c.mp4")+"\n" {
+			t.Errorf("output = %q, want only the message's file", out)
+		}
+	})
+
+	t.Run("table", func(t *testing.T) {
+		out, err := run(t, SearchFormatTable, db.SearchQuery{Match: "day"})
+		if err != nil {
+			t.Fatalf("Run: %v", err)
+		}
+		for _, want := range []string{"Match", "posts", "2024-01-10", "0.00", "Beach day"} {
+			if !strings.Contains(out, want) {
+				t.Errorf("table lacks %q:\n%s", want, out)
+			}
+		}
+		if strings.Contains(out, "More beach pics") {
+			t.Errorf("table lists the unmatched message:\n%s", out)
+		}
+	})
+
+	t.Run("invalid query", func(t *testing.T) {
+		if _, err := run(t, SearchFormatTable, db.SearchQuery{Match: "beach AND"}); err == nil ||
+			!strings.Contains(err.Error(), "invalid search query") {
+			t.Errorf("Run = %v, want an invalid search query error", err)
+		}
+	})
+}
@@ -0,0 +1,310 @@
+// =============================================================================
+// FILE: internal/commands/verify.go
+// PURPOSE: Verify command. Re-hashes downloaded files under the save location
+//          and compares them with the size and XXHash128 recorded in each
+//          model's database, reporting missing, truncated, modified, and
+//          unknown files per model.
+// =============================================================================
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gofscraper/internal/app"
+	cmdutils "gofscraper/internal/commands/utils"
+	"gofscraper/internal/db"
+	"gofscraper/internal/download"
+	"gofscraper/internal/hash"
+	"gofscraper/internal/tui/sections"
+)
+
+// ---------------------------------------------------------------------------
+// Verify status
+// ---------------------------------------------------------------------------
+
+// VerifyStatus is the outcome of checking one file.
+type VerifyStatus string
+
+const (
+	VerifyOK        VerifyStatus = "ok"
+	VerifyMissing   VerifyStatus = "missing"   // Recorded as downloaded but not on disk
+	VerifyTruncated VerifyStatus = "truncated" // Smaller than the recorded size
+	VerifyModified  VerifyStatus = "modified"  // Larger than recorded, or hash differs
+	VerifyUnhashed  VerifyStatus = "unhashed"  // No recorded hash to compare against
+	VerifyUnknown   VerifyStatus = "unknown"   // On disk but not in the database
+)
+
+// verifyProblems are the statuses that fail verification.
+var verifyProblems = []VerifyStatus{VerifyMissing, VerifyTruncated, VerifyModified, VerifyUnknown}
+
+// ---------------------------------------------------------------------------
+// VerifyCommand
+// ---------------------------------------------------------------------------
+
+// VerifyCommand checks downloaded files against their database records.
+type VerifyCommand struct {
+	cmdutils.CommandBase
+	recordHashes bool
+}
+
+// NewVerifyCommand creates a new VerifyCommand.
+//
+// Parameters:
+//   - logger: Structured logger for output.
+//
+// Returns:
+//   - A configured VerifyCommand.
+func NewVerifyCommand(logger *slog.Logger) *VerifyCommand {
+	return &VerifyCommand{
+		CommandBase: cmdutils.NewCommandBase(logger),
+	}
+}
+
+// SetRecordHashes makes verify store the hash of files that have none
+// recorded instead of reporting them as unhashed.
+func (v *VerifyCommand) SetRecordHashes(record bool) {
+	v.recordHashes = record
+}
+
+// Name returns the command name.
+func (v *VerifyCommand) Name() string {
+	return "verify"
+}
+
+// verifyResult collects the outcome for one model.
+type verifyResult struct {
+	user     string
+	counts   map[VerifyStatus]int
+	problems []verifyProblem
+}
+
+// verifyProblem is one file that failed verification.
+type verifyProblem struct {
+	status VerifyStatus
+	path   string
+}
+
+// Run verifies the files of the given models.
+//
+// Parameters:
+//   - ctx: Context for cancellation.
+//   - a: The application instance providing config.
+//   - args: Model usernames or .db paths (all models when empty).
+//
+// Returns:
+//   - Error if verification could not run or any file failed it.
+func (v *VerifyCommand) Run(ctx context.Context, _ *app.App, args []string) error {
+	v.LogStart(v.Name(), args)
+	defer v.LogDone(v.Name())
+
+	targets, err := targetDBs(ctx, args)
+	if err != nil {
+		return err
+	}
+	if len(targets) == 0 {
+		return fmt.Errorf("no databases found to verify")
+	}
+
+	var results []*verifyResult
+	for _, name := range sortedKeys(targets) {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		res, err := v.verifyModel(ctx, name, targets[name])
+		if err != nil {
+			v.Logger.Error("verify failed", "user", name, "error", err)
+			continue
+		}
+		results = append(results, res)
+	}
+
+	bad := v.printResults(results)
+	if bad > 0 {
+		return fmt.Errorf("verification found %d problem files", bad)
+	}
+	return nil
+}
+
+// verifyModel checks every downloaded file of one model, then scans the
+// model's directory for files the database does not know.
+func (v *VerifyCommand) verifyModel(ctx context.Context, name, dbPath string) (*verifyResult, error) {
+	conn, err := db.Open(name, dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", dbPath, err)
+	}
+	rows, err := db.GetAllMedia(ctx, conn)
+	if err != nil {
+		return nil, fmt.Errorf("load media: %w", err)
+	}
+
+	res := &verifyResult{user: name, counts: make(map[VerifyStatus]int)}
+	known := make(map[string]bool)
+	for _, row := range rows {
+		path, ok := rowPath(row)
+		if !ok {
+			continue
+		}
+		known[path] = true
+		if !row.Downloaded {
+			continue
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		status, sum, err := checkFile(path, row)
+		if err != nil {
+			return nil, err
+		}
+		if status == VerifyUnhashed && v.recordHashes {
+			row.Hash = db.NullString(sum)
+			if err := db.UpsertMedia(ctx, conn, row); err != nil {
+				return nil, fmt.Errorf("record hash for media %d: %w", row.MediaID, err)
+			}
+			status = VerifyOK
+		}
+		res.add(status, path)
+	}
+
+	// Any other media file under the model's directory is unknown.
+	root := modelDir(conn)
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || known[path] || !isMediaCandidate(d.Name()) {
+			return nil
+		}
+		// Sidecars written beside known files are not downloads.
+		if base, ok := strings.CutSuffix(path, download.SidecarExt); ok && known[base] {
+			return nil
+		}
+		res.add(VerifyUnknown, path)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan %s: %w", root, err)
+	}
+	return res, nil
+}
+
+// checkFile compares a file on disk with its database row. It returns the
+// computed hash when the file was read.
+func checkFile(path string, row db.MediaRow) (VerifyStatus, string, error) {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return VerifyMissing, "", nil
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("stat %s: %w", path, err)
+	}
+	if row.Size > 0 {
+		switch {
+		case info.Size() < row.Size:
+			return VerifyTruncated, "", nil
+		case info.Size() > row.Size:
+			return VerifyModified, "", nil
+		}
+	}
+
+	sum, err := hash.File(path)
+	if err != nil {
+		return "", "", err
+	}
+	switch {
+	case !row.Hash.Valid:
+		return VerifyUnhashed, sum, nil
+	case !strings.EqualFold(sum, row.Hash.String):
+		return VerifyModified, sum, nil
+	}
+	return VerifyOK, sum, nil
+}
+
+// isMediaCandidate reports whether a file in a model directory could be a
+// download, excluding databases, in-progress files, and hidden files.
+func isMediaCandidate(name string) bool {
+	switch {
+	case strings.HasPrefix(name, "."):
+		return false
+	case strings.HasPrefix(name, "user_data") && strings.Contains(name, ".db"):
+		return false
+	case strings.HasSuffix(name, ".part"), strings.HasSuffix(name, ".part.segments"):
+		return false
+	}
+	return true
+}
+
+// add counts a file and remembers it if it is a problem.
+func (r *verifyResult) add(status VerifyStatus, path string) {
+	r.counts[status]++
+	for _, p := range verifyProblems {
+		if status == p {
+			r.problems = append(r.problems, verifyProblem{status: status, path: path})
+			return
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Report
+// ---------------------------------------------------------------------------
+
+// verifyColumns are the columns of the per-model summary.
+var verifyColumns = []sections.Column{
+	{Key: "user", Title: "User", Width: 20},
+	{Key: "ok", Title: "OK", Width: 8},
+	{Key: "missing", Title: "Missing", Width: 8},
+	{Key: "truncated", Title: "Truncated", Width: 9},
+	{Key: "modified", Title: "Modified", Width: 8},
+	{Key: "unknown", Title: "Unknown", Width: 8},
+	{Key: "unhashed", Title: "Unhashed", Width: 8},
+}
+
+// verifyProblemColumns are the columns of the problem file list.
+var verifyProblemColumns = []sections.Column{
+	{Key: "user", Title: "User", Width: 20},
+	{Key: "status", Title: "Status", Width: 10},
+	{Key: "path", Title: "Path", Width: 80},
+}
+
+// printResults prints the summary and problem tables and returns the
+// number of problem files.
+func (v *VerifyCommand) printResults(results []*verifyResult) int {
+	var summary, problems []sections.Row
+	var bad int
+	for _, r := range results {
+		row := sections.Row{"user": r.user}
+		for _, s := range []VerifyStatus{VerifyOK, VerifyMissing, VerifyTruncated, VerifyModified, VerifyUnknown, VerifyUnhashed} {
+			row[string(s)] = fmt.Sprintf("%d", r.counts[s])
+		}
+		summary = append(summary, row)
+
+		for _, p := range r.problems {
+			problems = append(problems, sections.Row{
+				"user":   r.user,
+				"status": string(p.status),
+				"path":   p.path,
+			})
+		}
+		bad += len(r.problems)
+	}
+
+	console := sections.NewConsoleSection(verifyColumns)
+	console.SetRows(summary)
+	console.Print()
+
+	if len(problems) > 0 {
+		fmt.Println()
+		console = sections.NewConsoleSection(verifyProblemColumns)
+		console.SetRows(problems)
+		console.Print()
+	}
+	return bad
+}
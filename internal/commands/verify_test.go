@@ -0,0 +1,121 @@
+// =============================================================================
+// FILE: internal/commands/verify_test.go
+// PURPOSE: Tests for the verify command: each downloaded file is classified
+//          against its recorded size and hash, files the database does not
+//          know are reported, and unhashed files can have their hash
+//          recorded.
+// =============================================================================
+
+package commands
+
+import (
+	"context"
+	"maps"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gofscraper/internal/db"
+	"gofscraper/internal/download"
+	"gofscraper/internal/hash"
+)
+
+func TestVerifyModel(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "user_data.db")
+	conn, err := db.Open("verify_test", dbPath)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { db.Close("verify_test") })
+
+	write := func(name, content string) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	record := func(id int64, name, content, onDisk string, downloaded, hashed bool) {
+		t.Helper()
+		if onDisk != "" {
+			write(name, onDisk)
+		}
+		row := db.MediaRow{
+			MediaID:    id,
+			PostID:     1,
+			Directory:  db.NullString(dir),
+			Filename:   db.NullString(name),
+			Size:       int64(len(content)),
+			Downloaded: downloaded,
+		}
+		if hashed {
+			row.Hash = db.NullString(hash.Bytes([]byte(content)))
+		}
+		if err := db.UpsertMedia(ctx, conn, row); err != nil {
+			t.Fatalf("UpsertMedia: %v", err)
+		}
+	}
+
+	record(1, "ok.jpg", "original", "original", true, true)
+	record(2, "truncated.jpg", "original", "orig", true, true)
+	record(3, "modified.jpg", "original", "ORIGINAL", true, true)
+	record(4, "grown.jpg", "original", "original+", true, true)
+	record(5, "missing.jpg", "original", "", true, true)
+	record(6, "unhashed.jpg", "original", "original", true, false)
+	record(7, "pending.jpg", "original", "orig", false, false)
+	write("ok.jpg"+download.SidecarExt, "{}")
+	write("stray.jpg", "stray")
+	write("8.jpg.part", "partial")
+
+	res, err := NewVerifyCommand(nil).verifyModel(ctx, "verify_test", dbPath)
+	if err != nil {
+		t.Fatalf("verifyModel: %v", err)
+	}
+	wantCounts := map[VerifyStatus]int{
+		VerifyOK:        1,
+		VerifyTruncated: 1,
+		VerifyModified:  2,
+		VerifyMissing:   1,
+		VerifyUnhashed:  1,
+		VerifyUnknown:   1,
+	}
+	if !maps.Equal(res.counts, wantCounts) {
+		t.Errorf("counts = %v, want %v", res.counts, wantCounts)
+	}
+	gotProblems := make(map[string]VerifyStatus)
+	for _, p := range res.problems {
+		gotProblems[filepath.Base(p.path)] = p.status
+	}
+	wantProblems := map[string]VerifyStatus{
+		"truncated.jpg": VerifyTruncated,
+		"modified.jpg":  VerifyModified,
+		"grown.jpg":     VerifyModified,
+		"missing.jpg":   VerifyMissing,
+		"stray.jpg":     VerifyUnknown,
+	}
+	if !maps.Equal(gotProblems, wantProblems) {
+		t.Errorf("problems = %v, want %v", gotProblems, wantProblems)
+	}
+
+	// With hash recording, the unhashed file passes and keeps its hash.
+	v := NewVerifyCommand(nil)
+	v.SetRecordHashes(true)
+	if res, err = v.verifyModel(ctx, "verify_test", dbPath); err != nil {
+		t.Fatalf("verifyModel: %v", err)
+	}
+	if res.counts[VerifyUnhashed] != 0 || res.counts[VerifyOK] != 2 {
+		t.Errorf("counts with recorded hashes = %v", res.counts)
+	}
+	rows, err := db.GetMediaByPostID(ctx, conn, 1)
+	if err != nil {
+		t.Fatalf("GetMediaByPostID: %v", err)
+	}
+	for _, row := range rows {
+		if row.MediaID == 6 && row.Hash.String != hash.Bytes([]byte("original")) {
+			t.Errorf("recorded hash = %q", row.Hash.String)
+		}
+	}
+}
@@ -142,6 +142,12 @@ const (
 	// DefaultMaxCount is the default maximum post count (0 = unlimited).
 	DefaultMaxCount = 0
 
+	// DefaultQuality is the rendition downloaded when none is configured.
+	DefaultQuality = "source"
+
+	// DefaultProbeMedia probes downloaded files for duration and resolution.
+	DefaultProbeMedia = true
+
 	// DefaultFileSizeMax is the default max file size filter (0 = no limit).
 	DefaultFileSizeMax = 0
 
@@ -162,6 +168,14 @@ const (
 
 	// DefaultSystemFreeMin is the minimum free disk space required (0 = no check).
 	DefaultSystemFreeMin = 0
+
+	// DefaultDownloadSegments is the number of parallel byte-range segments
+	// a large file is split into (1 = single stream).
+	DefaultDownloadSegments = 4
+
+	// DefaultSegmentMinSize is the smallest file, in bytes, downloaded in
+	// segments (64 MiB).
+	DefaultSegmentMinSize = 64 * 1024 * 1024
 )
 
 // ---------------------------------------------------------------------------
@@ -199,6 +213,10 @@ const (
 	// DefaultEnableAutoAfter disables auto-after mode by default.
 	DefaultEnableAutoAfter = false
 
+	// DefaultTempMaxAge is how many hours a staged download may sit unused in
+	// temp_dir before it is cleaned up at startup.
+	DefaultTempMaxAge = 48
+
 	// DefaultIncludeLabelsAll disables include-all-labels by default.
 	DefaultIncludeLabelsAll = false
 
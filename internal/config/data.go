@@ -7,7 +7,13 @@
 
 package config
 
-import "gofscraper/internal/config/env"
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gofscraper/internal/config/env"
+)
 
 // ---------------------------------------------------------------------------
 // Profile & Metadata accessors
@@ -56,9 +62,28 @@ func GetDiscord() string {
 func GetSaveLocation() string {
 	cfg := Get()
 	if cfg.File.SaveLocation == "" {
-		return env.DefaultSavePath()
+		return expandHome(env.DefaultSavePath())
+	}
+	return expandHome(cfg.File.SaveLocation)
+}
+
+// expandHome replaces a leading "{home}" or "~" with the user's home
+// directory.
+func expandHome(p string) string {
+	var rest string
+	switch {
+	case strings.HasPrefix(p, "{home}"):
+		rest = p[len("{home}"):]
+	case strings.HasPrefix(p, "~"):
+		rest = p[1:]
+	default:
+		return p
 	}
-	return cfg.File.SaveLocation
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return p
+	}
+	return filepath.Join(home, rest)
 }
 
 // GetDirFormat returns the directory path format template.
@@ -105,6 +130,15 @@ func GetDateFormat() string {
 	return cfg.File.DateFormat
 }
 
+// GetRelativePaths returns whether media directories are recorded in the
+// database relative to the save location.
+//
+// Returns:
+//   - true to record relative directories.
+func GetRelativePaths() bool {
+	return Get().File.RelativePaths
+}
+
 // GetSpaceReplacer returns the character used to replace spaces in paths.
 //
 // Returns:
@@ -173,6 +207,55 @@ func GetMaxPostCount() int {
 	return Get().Download.MaxPostCount
 }
 
+// GetSetFileTimes returns whether downloaded files get their post date as
+// their modification time.
+//
+// Returns:
+//   - true if file times should be set.
+func GetSetFileTimes() bool {
+	return Get().Download.SetFileTimes
+}
+
+// GetWriteSidecars returns whether a JSON sidecar with post context is
+// written beside each downloaded file.
+//
+// Returns:
+//   - true if sidecars should be written.
+func GetWriteSidecars() bool {
+	return Get().Download.WriteSidecars
+}
+
+// GetEmbedMetadata returns whether the post date and caption are embedded
+// into downloaded JPEG and MP4 files.
+//
+// Returns:
+//   - true if metadata should be embedded.
+func GetEmbedMetadata() bool {
+	return Get().Download.EmbedMetadata
+}
+
+// GetProbeMedia returns whether downloaded files are probed for their
+// duration, dimensions, codec, and bitrate.
+//
+// Returns:
+//   - true if files should be probed.
+func GetProbeMedia() bool {
+	return Get().Download.ProbeMedia
+}
+
+// GetQuality returns the preferred media renditions in order, e.g.
+// ["720", "source"]. The best available rendition is used when none match.
+//
+// Returns:
+//   - The quality preference list (["source"] if unset).
+func GetQuality() []string {
+	q := Get().Download.Quality
+	if len(q) == 0 {
+		return []string{DefaultQuality}
+	}
+	return q
+}
+
 // ---------------------------------------------------------------------------
 // CDM options accessors
 // ---------------------------------------------------------------------------
@@ -229,6 +312,40 @@ func GetDownloadLimit() int64 {
 	return Get().Performance.DownloadLimit
 }
 
+// GetDownloadLimitSchedule returns the time-of-day bandwidth windows that
+// override download_limit while they are active.
+//
+// Returns:
+//   - The configured windows, in priority order.
+func GetDownloadLimitSchedule() []LimitWindow {
+	return Get().Performance.DownloadLimitSchedule
+}
+
+// GetDownloadSegments returns how many parallel byte-range segments a large
+// file is split into.
+//
+// Returns:
+//   - The segment count (1 = single stream).
+func GetDownloadSegments() int {
+	cfg := Get()
+	if cfg.Performance.DownloadSegments <= 0 {
+		return DefaultDownloadSegments
+	}
+	return cfg.Performance.DownloadSegments
+}
+
+// GetSegmentMinSize returns the smallest file size downloaded in segments.
+//
+// Returns:
+//   - The threshold in bytes.
+func GetSegmentMinSize() int64 {
+	cfg := Get()
+	if cfg.Performance.SegmentMinSize <= 0 {
+		return DefaultSegmentMinSize
+	}
+	return cfg.Performance.SegmentMinSize
+}
+
 // ---------------------------------------------------------------------------
 // Content filter accessors
 // ---------------------------------------------------------------------------
@@ -325,14 +442,27 @@ func GetSanitizeDB() bool {
 	return Get().Advanced.SanitizeText
 }
 
-// GetTempDir returns the temporary directory override.
+// GetTempDir returns the directory in-progress downloads are staged in.
 //
 // Returns:
-//   - The temp dir path, or empty for system default.
+//   - The temp dir path, or empty to write beside the final file.
 func GetTempDir() string {
 	return Get().Advanced.TempDir
 }
 
+// GetTempMaxAge returns how many hours a staged download may sit unused
+// before startup cleanup removes it.
+//
+// Returns:
+//   - The age in hours.
+func GetTempMaxAge() int {
+	cfg := Get()
+	if cfg.Advanced.TempMaxAge <= 0 {
+		return DefaultTempMaxAge
+	}
+	return cfg.Advanced.TempMaxAge
+}
+
 // GetHashEnabled returns whether file hash deduplication is enabled.
 //
 // Returns:
@@ -90,11 +90,11 @@ func MessagesNextEP() string {
 		"/api2/v2/chats/%d/messages?limit=100&id=%d&order=desc&skip_users=all&skip_users_dups=1")
 }
 
-// SubscriptionsEP returns the all-subscriptions endpoint template.
-// Format placeholder: offset.
+// SubscriptionsEP returns the subscriptions endpoint template.
+// Format placeholders: offset, type (all, active, expired).
 func SubscriptionsEP() string {
 	return GetString("OF_SUBSCRIPTIONS_EP",
-		"/api2/v2/subscriptions/subscribes?offset=%d&limit=10&type=all&format=infinite")
+		"/api2/v2/subscriptions/subscribes?offset=%d&limit=10&type=%s&format=infinite")
 }
 
 // SubscriptionsActiveEP returns the active subscriptions endpoint template.
@@ -146,6 +146,13 @@ func StoryEP() string {
 		"/api2/v2/stories/highlights/%d?unf=1")
 }
 
+// StoriesSpecificEP returns the single story endpoint template.
+// Format placeholder: story_id.
+func StoriesSpecificEP() string {
+	return GetString("OF_STORIES_SPECIFIC_EP",
+		"/api2/v2/stories/%d?unf=1")
+}
+
 // PurchasedContentEP returns the purchased content by user endpoint.
 // Format placeholders: offset, author_id.
 func PurchasedContentEP() string {
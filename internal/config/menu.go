@@ -51,6 +51,7 @@ func BuildMenuCategories() []MenuCategory {
 				{Key: "file_options.date", Label: "Date Format", Type: "string", CurrentValue: cfg.File.DateFormat},
 				{Key: "file_options.text_type_default", Label: "Text Type", Type: "choice", Choices: TextTypeOptions, CurrentValue: cfg.File.TextType},
 				{Key: "file_options.truncation_default", Label: "Truncation", Type: "bool", CurrentValue: cfg.File.Truncation},
+				{Key: "file_options.relative_paths", Label: "Relative DB Paths", Type: "bool", CurrentValue: cfg.File.RelativePaths},
 			},
 		},
 		{
@@ -60,6 +61,11 @@ func BuildMenuCategories() []MenuCategory {
 				{Key: "download_options.auto_resume", Label: "Auto Resume", Type: "bool", CurrentValue: cfg.Download.AutoResume},
 				{Key: "download_options.system_free_min", Label: "Min Free Space (bytes)", Type: "int", CurrentValue: cfg.Download.SystemFreeMin},
 				{Key: "download_options.max_post_count", Label: "Max Post Count", Type: "int", CurrentValue: cfg.Download.MaxPostCount},
+				{Key: "download_options.set_file_times", Label: "Set File Times", Type: "bool", CurrentValue: cfg.Download.SetFileTimes},
+				{Key: "download_options.write_sidecars", Label: "Write Sidecars", Type: "bool", CurrentValue: cfg.Download.WriteSidecars},
+				{Key: "download_options.embed_metadata", Label: "Embed Metadata", Type: "bool", CurrentValue: cfg.Download.EmbedMetadata},
+				{Key: "download_options.quality", Label: "Quality Preference", Type: "list", CurrentValue: cfg.Download.Quality},
+				{Key: "download_options.probe_media", Label: "Probe Media", Type: "bool", CurrentValue: cfg.Download.ProbeMedia},
 			},
 		},
 		{
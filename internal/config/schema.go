@@ -38,14 +38,20 @@ type FileOptions struct {
 	DateFormat   string `json:"date"`
 	TextType     string `json:"text_type_default"`
 	Truncation   bool   `json:"truncation_default"`
+	RelativePaths bool  `json:"relative_paths"` // Record media directories relative to save_location
 }
 
 // DownloadOptions controls download behavior and limits.
 type DownloadOptions struct {
-	Filter       []string `json:"filter"`
-	AutoResume   bool     `json:"auto_resume"`
-	SystemFreeMin int64   `json:"system_free_min"`
-	MaxPostCount int      `json:"max_post_count"`
+	Filter        []string `json:"filter"`
+	AutoResume    bool     `json:"auto_resume"`
+	SystemFreeMin int64    `json:"system_free_min"`
+	MaxPostCount  int      `json:"max_post_count"`
+	SetFileTimes  bool     `json:"set_file_times"` // Set file mtime to the post date
+	WriteSidecars bool     `json:"write_sidecars"` // Write <file>.json post metadata
+	EmbedMetadata bool     `json:"embed_metadata"` // Embed post date/caption in files
+	Quality       []string `json:"quality"`        // Preferred renditions, best first
+	ProbeMedia    bool     `json:"probe_media"`    // Probe files after download
 }
 
 // BinaryOptions specifies paths to external binaries.
@@ -62,8 +68,19 @@ type CDMOptions struct {
 
 // PerformanceOpts controls concurrency and bandwidth limits.
 type PerformanceOpts struct {
-	DownloadSems  int   `json:"download_sems"`
-	DownloadLimit int64 `json:"download_limit"`
+	DownloadSems          int           `json:"download_sems"`
+	DownloadLimit         int64         `json:"download_limit"`
+	DownloadLimitSchedule []LimitWindow `json:"download_limit_schedule"`
+	DownloadSegments      int           `json:"download_segments"`
+	SegmentMinSize        int64         `json:"segment_min_size"`
+}
+
+// LimitWindow sets the bandwidth limit for part of each day. A window whose
+// end is before its start wraps past midnight.
+type LimitWindow struct {
+	Start string `json:"start"` // "HH:MM"
+	End   string `json:"end"`   // "HH:MM"
+	Limit int64  `json:"limit"` // Bytes per second (0 = unlimited)
 }
 
 // ContentFilterOpts controls media content filtering by size and duration.
@@ -83,6 +100,7 @@ type AdvancedOptions struct {
 	RotateLogs        bool     `json:"rotate_logs"`
 	SanitizeText      bool     `json:"sanitize_text"`
 	TempDir           string   `json:"temp_dir"`
+	TempMaxAge        int      `json:"temp_max_age"`
 	RemoveHashMatch   bool     `json:"remove_hash_match"`
 	InfiniteLoopMode  string   `json:"infinite_loop_action_mode"`
 	EnableAutoAfter   bool     `json:"enable_auto_after"`
@@ -145,6 +163,8 @@ func DefaultConfig() AppConfig {
 			AutoResume:    DefaultResume,
 			SystemFreeMin: DefaultSystemFreeMin,
 			MaxPostCount:  DefaultMaxCount,
+			Quality:       []string{DefaultQuality},
+			ProbeMedia:    DefaultProbeMedia,
 		},
 		Binary: BinaryOptions{
 			FFmpeg: DefaultFFmpeg,
@@ -155,8 +175,10 @@ func DefaultConfig() AppConfig {
 			KeyMode:    DefaultKeyMode,
 		},
 		Performance: PerformanceOpts{
-			DownloadSems:  DefaultDownloadSem,
-			DownloadLimit: DefaultDownloadLimit,
+			DownloadSems:     DefaultDownloadSem,
+			DownloadLimit:    DefaultDownloadLimit,
+			DownloadSegments: DefaultDownloadSegments,
+			SegmentMinSize:   DefaultSegmentMinSize,
 		},
 		Content: ContentFilterOpts{
 			BlockAds:    DefaultBlockAds,
@@ -172,6 +194,7 @@ func DefaultConfig() AppConfig {
 			RotateLogs:       DefaultRotateLogs,
 			SanitizeText:     DefaultSanitizeDB,
 			TempDir:          "",
+			TempMaxAge:       DefaultTempMaxAge,
 			RemoveHashMatch:  false,
 			InfiniteLoopMode: "",
 			EnableAutoAfter:  DefaultEnableAutoAfter,
@@ -0,0 +1,119 @@
+// =============================================================================
+// FILE: internal/db/attempts.go
+// PURPOSE: Download attempt history. Records every download attempt with its
+//          HTTP status, error class, bytes written, and duration, and selects
+//          media whose most recent attempt failed for retry runs.
+// =============================================================================
+
+package db
+
+import (
+	"context"
+	"database/sql"
+)
+
+// ---------------------------------------------------------------------------
+// Row type
+// ---------------------------------------------------------------------------
+
+// DownloadAttemptRow represents a row in the download_attempts table.
+type DownloadAttemptRow struct {
+	MediaID      int64
+	PostID       int64
+	AttemptedAt  string // RFC 3339
+	HTTPStatus   int
+	ErrorClass   sql.NullString
+	Error        sql.NullString
+	BytesWritten int64
+	DurationMS   int64
+	Succeeded    bool
+	ModelID      int64
+}
+
+// ---------------------------------------------------------------------------
+// Attempt operations
+// ---------------------------------------------------------------------------
+
+// RecordDownloadAttempt appends one download attempt to the history.
+//
+// Parameters:
+//   - ctx: Context.
+//   - conn: Database connection.
+//   - a: The attempt to record.
+//
+// Returns:
+//   - Error if the insert fails.
+func RecordDownloadAttempt(ctx context.Context, conn *Conn, a DownloadAttemptRow) error {
+	_, err := conn.DB.ExecContext(ctx,
+		`INSERT INTO download_attempts (media_id, post_id, attempted_at, http_status, error_class, error, bytes_written, duration_ms, succeeded, model_id)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		a.MediaID, a.PostID, a.AttemptedAt, a.HTTPStatus, a.ErrorClass, a.Error,
+		a.BytesWritten, a.DurationMS, boolToInt(a.Succeeded), conn.rowModelID(a.ModelID),
+	)
+	return err
+}
+
+// GetLastAttempt retrieves the most recent download attempt for a media item.
+//
+// Parameters:
+//   - ctx: Context.
+//   - conn: Database connection.
+//   - mediaID: The media ID.
+//
+// Returns:
+//   - The attempt, and any error (sql.ErrNoRows if never attempted).
+func GetLastAttempt(ctx context.Context, conn *Conn, mediaID int64) (*DownloadAttemptRow, error) {
+	row := conn.DB.QueryRowContext(ctx,
+		`SELECT media_id, post_id, attempted_at, http_status, error_class, error, bytes_written, duration_ms, succeeded, model_id
+		 FROM download_attempts WHERE media_id = ? ORDER BY id DESC LIMIT 1`,
+		mediaID,
+	)
+
+	a := &DownloadAttemptRow{}
+	var postID, modelID sql.NullInt64
+	var succeeded int
+	err := row.Scan(&a.MediaID, &postID, &a.AttemptedAt, &a.HTTPStatus, &a.ErrorClass, &a.Error,
+		&a.BytesWritten, &a.DurationMS, &succeeded, &modelID)
+	if err != nil {
+		return nil, err
+	}
+	a.PostID = Int64FromNull(postID)
+	a.ModelID = Int64FromNull(modelID)
+	a.Succeeded = succeeded == 1
+	return a, nil
+}
+
+// GetFailedMediaIDs returns the media whose most recent download attempt
+// failed, mapped to that attempt's error class.
+//
+// Parameters:
+//   - ctx: Context.
+//   - conn: Database connection.
+//
+// Returns:
+//   - Media ID to error class, and any error.
+func GetFailedMediaIDs(ctx context.Context, conn *Conn) (map[int64]string, error) {
+	filter, args := conn.modelFilter("a.model_id")
+	rows, err := conn.DB.QueryContext(ctx,
+		`SELECT a.media_id, a.error_class FROM download_attempts a
+		 JOIN (SELECT media_id, MAX(id) AS last_id FROM download_attempts GROUP BY media_id) l
+		   ON a.id = l.last_id
+		 WHERE a.succeeded = 0 AND `+filter,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	failed := make(map[int64]string)
+	for rows.Next() {
+		var id int64
+		var class sql.NullString
+		if err := rows.Scan(&id, &class); err != nil {
+			return nil, err
+		}
+		failed[id] = StringFromNull(class)
+	}
+	return failed, rows.Err()
+}
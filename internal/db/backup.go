@@ -1,17 +1,20 @@
 // =============================================================================
 // FILE: internal/db/backup.go
 // PURPOSE: Database backup operations. Creates timestamped copies of model
-//          databases before destructive operations like merging or migration.
-//          Ports Python db/backup.py.
+//          databases before destructive operations like merging or migration,
+//          lists them, and restores from them. Ports Python db/backup.py.
 // =============================================================================
 
 package db
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
 	"time"
 )
 
@@ -19,7 +22,9 @@ import (
 // Backup
 // ---------------------------------------------------------------------------
 
-// Backup creates a timestamped backup of the given database file.
+// Backup creates a timestamped backup of the given database file. Stamps
+// have microsecond precision, and an existing backup is never overwritten:
+// a name already taken gets a numeric suffix.
 //
 // Parameters:
 //   - dbPath: Path to the database file to back up.
@@ -36,11 +41,22 @@ func Backup(dbPath string) (string, error) {
 	base := filepath.Base(dbPath)
 	ext := filepath.Ext(base)
 	name := base[:len(base)-len(ext)]
-	ts := time.Now().Format("20060102_150405")
-	backupPath := filepath.Join(dir, fmt.Sprintf("%s_backup_%s%s", name, ts, ext))
+	now := time.Now()
+	ts := fmt.Sprintf("%s_%06d", now.Format("20060102_150405"), now.Nanosecond()/1000)
 
-	if err := copyFile(dbPath, backupPath); err != nil {
-		return "", fmt.Errorf("failed to create backup: %w", err)
+	var backupPath string
+	for n := 0; ; n++ {
+		backupPath = filepath.Join(dir, fmt.Sprintf("%s_backup_%s%s", name, ts, ext))
+		if n > 0 {
+			backupPath = filepath.Join(dir, fmt.Sprintf("%s_backup_%s_%d%s", name, ts, n, ext))
+		}
+		err := copyFileExcl(dbPath, backupPath)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return "", fmt.Errorf("failed to create backup: %w", err)
+		}
 	}
 
 	// Also back up WAL and SHM files if they exist.
@@ -55,15 +71,108 @@ func Backup(dbPath string) (string, error) {
 	return backupPath, nil
 }
 
-// copyFile copies a file from src to dst.
+// ListBackups returns the timestamped backups Backup has made of dbPath,
+// newest first.
+//
+// Parameters:
+//   - dbPath: Path to the database file.
+//
+// Returns:
+//   - Backup file paths, and any error.
+func ListBackups(dbPath string) ([]string, error) {
+	dir := filepath.Dir(dbPath)
+	base := filepath.Base(dbPath)
+	ext := filepath.Ext(base)
+	name := base[:len(base)-len(ext)]
+
+	matches, err := filepath.Glob(filepath.Join(dir, name+"_backup_*"+ext))
+	if err != nil {
+		return nil, err
+	}
+
+	// Timestamps are zero-padded, so lexical order is chronological; a
+	// suffixed name sorts after the one it collided with.
+	sort.Sort(sort.Reverse(sort.StringSlice(matches)))
+	return matches, nil
+}
+
+// ---------------------------------------------------------------------------
+// Restore
+// ---------------------------------------------------------------------------
+
+// Restore replaces a database file with one of its backups. Any pooled
+// connection for username or to dbPath is closed first, and the current
+// file is backed up so the restore can itself be undone.
+//
+// Parameters:
+//   - username: The model username whose connection to close.
+//   - dbPath: Path to the database file to replace.
+//   - backupPath: Path to the backup to restore from.
+//
+// Returns:
+//   - The path of the pre-restore backup (empty if dbPath did not exist),
+//     and any error.
+func Restore(username, dbPath, backupPath string) (string, error) {
+	if _, err := os.Stat(backupPath); err != nil {
+		return "", fmt.Errorf("backup not found: %w", err)
+	}
+
+	if err := Close(username); err != nil {
+		return "", fmt.Errorf("failed to close database: %w", err)
+	}
+	// Other models of a consolidated database share the file.
+	if err := closePath(dbPath); err != nil {
+		return "", fmt.Errorf("failed to close database: %w", err)
+	}
+
+	var previous string
+	if _, err := os.Stat(dbPath); err == nil {
+		previous, err = Backup(dbPath)
+		if err != nil {
+			return "", fmt.Errorf("failed to back up current database: %w", err)
+		}
+	}
+
+	if err := copyFile(backupPath, dbPath); err != nil {
+		return previous, fmt.Errorf("failed to restore backup: %w", err)
+	}
+
+	// Replace or drop WAL/SHM so SQLite does not replay stale pages.
+	for _, suffix := range []string{"-wal", "-shm"} {
+		src := backupPath + suffix
+		dst := dbPath + suffix
+		if _, err := os.Stat(src); err == nil {
+			if err := copyFile(src, dst); err != nil {
+				return previous, fmt.Errorf("failed to restore %s: %w", suffix, err)
+			}
+		} else if err := os.Remove(dst); err != nil && !os.IsNotExist(err) {
+			return previous, fmt.Errorf("failed to remove stale %s: %w", suffix, err)
+		}
+	}
+
+	return previous, nil
+}
+
+// copyFile copies a file from src to dst, replacing dst.
 func copyFile(src, dst string) error {
+	return copyFileFlags(src, dst, os.O_TRUNC)
+}
+
+// copyFileExcl copies a file from src to dst, failing with fs.ErrExist if
+// dst already exists.
+func copyFileExcl(src, dst string) error {
+	return copyFileFlags(src, dst, os.O_EXCL)
+}
+
+// copyFileFlags copies src to a new dst opened with the extra flag.
+func copyFileFlags(src, dst string, flag int) error {
 	in, err := os.Open(src)
 	if err != nil {
 		return err
 	}
 	defer in.Close()
 
-	out, err := os.Create(dst)
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|flag, 0o666)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,55 @@
+// =============================================================================
+// FILE: internal/db/backup_test.go
+// PURPOSE: Tests for database backups: backups taken in quick succession,
+//          as a migration followed by a restore makes, each keep their own
+//          file.
+// =============================================================================
+
+package db_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gofscraper/internal/db"
+)
+
+func TestBackupNeverOverwrites(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "user_data.db")
+
+	const n = 20
+	made := make(map[string]string, n)
+	for i := range n {
+		content := fmt.Sprintf("version %d", i)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		backup, err := db.Backup(path)
+		if err != nil {
+			t.Fatalf("Backup: %v", err)
+		}
+		if _, ok := made[backup]; ok {
+			t.Fatalf("Backup returned %s twice", backup)
+		}
+		made[backup] = content
+	}
+
+	for backup, want := range made {
+		if got, err := os.ReadFile(backup); err != nil || string(got) != want {
+			t.Errorf("%s = %q, %v; want %q", backup, got, err, want)
+		}
+	}
+
+	backups, err := db.ListBackups(path)
+	if err != nil {
+		t.Fatalf("ListBackups: %v", err)
+	}
+	if len(backups) != n {
+		t.Fatalf("ListBackups = %d backups, want %d", len(backups), n)
+	}
+	if got, _ := os.ReadFile(backups[0]); string(got) != fmt.Sprintf("version %d", n-1) {
+		t.Errorf("newest backup holds %q, want the last version", got)
+	}
+}
@@ -0,0 +1,151 @@
+// =============================================================================
+// FILE: internal/db/consolidated.go
+// PURPOSE: Consolidated database support. One SQLite file can hold every
+//          model, with each table partitioned by model_id. Provides the
+//          model registry and the filters that scope a connection's queries
+//          to its model's partition.
+// =============================================================================
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// ---------------------------------------------------------------------------
+// Layout
+// ---------------------------------------------------------------------------
+
+// isConsolidated reports whether the database is marked as shared by every
+// model.
+func isConsolidated(db *sql.DB) bool {
+	var value string
+	err := db.QueryRow(
+		`SELECT flag_value FROM schema_flags WHERE flag_name = 'consolidated'`,
+	).Scan(&value)
+	return err == nil && value == "1"
+}
+
+// IsConsolidated reports whether conn's database is shared by every model.
+//
+// Parameters:
+//   - conn: Database connection.
+//
+// Returns:
+//   - true for a consolidated database.
+func IsConsolidated(conn *Conn) bool {
+	return conn.Partitioned || isConsolidated(conn.DB)
+}
+
+// ---------------------------------------------------------------------------
+// Model registry
+// ---------------------------------------------------------------------------
+
+// ModelRow is a row of the models table.
+type ModelRow struct {
+	ModelID  int64
+	Username string
+}
+
+// lookupModelID returns the ID registered for username, or 0.
+func lookupModelID(db *sql.DB, username string) (int64, error) {
+	var id int64
+	err := db.QueryRow(
+		`SELECT model_id FROM models WHERE username = ? COLLATE NOCASE ORDER BY id DESC LIMIT 1`,
+		username,
+	).Scan(&id)
+	if IsNotFound(err) {
+		return 0, nil
+	}
+	return id, err
+}
+
+// RegisterModel records the connection's username under modelID in the
+// models table. A partitioned connection is then scoped to modelID, so a
+// model scraped for the first time gets its partition.
+//
+// Parameters:
+//   - ctx: Context.
+//   - conn: The model's database connection.
+//   - modelID: The model's numeric ID.
+//
+// Returns:
+//   - Error if the upsert fails.
+func RegisterModel(ctx context.Context, conn *Conn, modelID int64) error {
+	if err := upsertModelRow(ctx, conn.DB, modelID, conn.Username); err != nil {
+		return err
+	}
+	if conn.Partitioned {
+		conn.ModelID = modelID
+	}
+	return nil
+}
+
+// ListModels returns the registered models, ordered by username.
+//
+// Parameters:
+//   - ctx: Context.
+//   - conn: Database connection.
+//
+// Returns:
+//   - The models, and any error.
+func ListModels(ctx context.Context, conn *Conn) ([]ModelRow, error) {
+	rows, err := conn.DB.QueryContext(ctx,
+		`SELECT model_id, COALESCE(username, '') FROM models ORDER BY username COLLATE NOCASE`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var models []ModelRow
+	for rows.Next() {
+		var m ModelRow
+		if err := rows.Scan(&m.ModelID, &m.Username); err != nil {
+			return nil, err
+		}
+		models = append(models, m)
+	}
+	return models, rows.Err()
+}
+
+// upsertModelRow upserts a models row, keyed by model_id.
+func upsertModelRow(ctx context.Context, ex execer, modelID int64, username string) error {
+	_, err := ex.ExecContext(ctx,
+		`INSERT INTO models (model_id, username)
+		 VALUES (?, ?)
+		 ON CONFLICT(model_id) DO UPDATE SET username = excluded.username`,
+		modelID, username,
+	)
+	if err != nil {
+		return fmt.Errorf("register model %d: %w", modelID, err)
+	}
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// Partition scoping
+// ---------------------------------------------------------------------------
+
+// modelFilter returns the condition limiting a query to the connection's
+// partition, with its arguments: "col = ?" on a partitioned connection,
+// "1" (every row) otherwise. col names the model_id column, qualified
+// when the query joins tables.
+func (c *Conn) modelFilter(col string) (string, []any) {
+	if !c.Partitioned {
+		return "1", nil
+	}
+	return col + " = ?", []any{c.ModelID}
+}
+
+// rowModelID returns the model_id to write on a new row: the partition's
+// ID on a partitioned connection, so rows always land in the model they
+// were written for, otherwise the caller's.
+func (c *Conn) rowModelID(modelID int64) int64 {
+	if c.Partitioned && c.ModelID != 0 {
+		return c.ModelID
+	}
+	return modelID
+}
@@ -0,0 +1,128 @@
+// =============================================================================
+// FILE: internal/db/consolidated_test.go
+// PURPOSE: Tests for consolidated databases: models whose posts and media
+//          share IDs keep separate rows, search entries, and queue entries
+//          in their partitions.
+// =============================================================================
+
+package db_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"gofscraper/internal/db"
+)
+
+// writeModelDB creates a per-model database holding post 100 with text,
+// its media 500, and a queue entry for that media.
+func writeModelDB(t *testing.T, username string, modelID int64, text string) *db.Conn {
+	t.Helper()
+	ctx := context.Background()
+	conn, err := db.Open(username, filepath.Join(t.TempDir(), username, "user_data.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close(username) })
+
+	if err := db.RegisterModel(ctx, conn, modelID); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.UpsertPost(ctx, conn, 100, text, 0, false, false, "2024-01-01T00:00:00", modelID); err != nil {
+		t.Fatal(err)
+	}
+	media := db.MediaRow{MediaID: 500, PostID: 100, ModelID: modelID, Filename: db.NullString(username + ".jpg"), Downloaded: true}
+	if err := db.UpsertMedia(ctx, conn, media); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.UpsertLabel(ctx, conn, 7, "Favourites", "custom", 100, modelID); err != nil {
+		t.Fatal(err)
+	}
+	queued := db.QueueRow{MediaID: 500, PostID: 100, ModelID: modelID, FilePath: "/" + username + ".jpg", Media: "{}"}
+	if err := db.EnqueueDownloads(ctx, conn, []db.QueueRow{queued}); err != nil {
+		t.Fatal(err)
+	}
+	return conn
+}
+
+func TestConsolidatedModelsSharingIDs(t *testing.T) {
+	ctx := context.Background()
+	models := []struct {
+		username string
+		modelID  int64
+		text     string
+	}{
+		{"alice_shared", 1, "alice at the beach"},
+		{"bob_shared", 2, "bob at the beach"},
+	}
+
+	dstPath := filepath.Join(t.TempDir(), "consolidated.db")
+	dst, err := db.OpenConsolidated(dstPath, dstPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close(dstPath) })
+
+	for _, m := range models {
+		src := writeModelDB(t, m.username, m.modelID, m.text)
+		result, err := db.ConsolidateModel(ctx, src, dst, m.modelID, m.username)
+		if err != nil {
+			t.Fatalf("ConsolidateModel(%s): %v", m.username, err)
+		}
+		if result.PostsMerged != 1 || result.MediaMerged != 1 || result.LabelsMerged != 1 {
+			t.Errorf("%s: merged %+v, want its post, media, and label", m.username, result)
+		}
+		if _, err := db.ConsolidateModel(ctx, src, dst, m.modelID, m.username); !errors.Is(err, db.ErrAlreadyConsolidated) {
+			t.Errorf("folding %s again = %v, want ErrAlreadyConsolidated", m.username, err)
+		}
+		// The per-model connections are reopened on the consolidated file.
+		db.Close(m.username)
+	}
+
+	for i, m := range models {
+		conn, err := db.Open(m.username, dstPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if conn.ModelID != m.modelID {
+			t.Fatalf("%s partition = %d, want %d", m.username, conn.ModelID, m.modelID)
+		}
+
+		post, err := db.GetPost(ctx, conn, 100)
+		if err != nil || post.Text.String != m.text {
+			t.Errorf("%s post 100 = %+v (err %v), want %q", m.username, post, err, m.text)
+		}
+		media, err := db.GetMediaByPostID(ctx, conn, 100)
+		if err != nil || len(media) != 1 || media[0].Filename.String != m.username+".jpg" {
+			t.Errorf("%s media of post 100 = %+v (err %v), want only its own", m.username, media, err)
+		}
+		queued, err := db.GetQueuedDownloads(ctx, conn)
+		if err != nil || len(queued) != 1 || queued[0].FilePath != "/"+m.username+".jpg" {
+			t.Errorf("%s queue = %+v (err %v), want only its own entry", m.username, queued, err)
+		}
+		hits, err := db.SearchPosts(ctx, conn, db.SearchQuery{Match: "beach"})
+		if err != nil || len(hits) != 1 || hits[0].Text != m.text {
+			t.Errorf("%s search = %+v (err %v), want only its own post", m.username, hits, err)
+		}
+
+		// Finishing one model's download leaves the other's queued.
+		if err := db.SetQueueState(ctx, conn, 500, db.QueueDone); err != nil {
+			t.Fatal(err)
+		}
+		left, err := db.GetQueuedDownloads(ctx, dst)
+		if err != nil || len(left) != len(models)-i-1 {
+			t.Errorf("after %s finished: queue = %+v (err %v), want %d entries", m.username, left, err, len(models)-i-1)
+		}
+		db.Close(m.username)
+	}
+
+	all, err := db.GetStats(ctx, dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if all.PostCount != 2 || all.MediaCount != 2 {
+		t.Errorf("consolidated stats = %+v, want both models' post and media", all)
+	}
+}
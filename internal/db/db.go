@@ -2,7 +2,8 @@
 // FILE: internal/db/db.go
 // PURPOSE: Database connection pool and initialisation. Manages per-model
 //          SQLite database connections with WAL mode, busy timeouts, and
-//          schema migration. Ports Python db/__init__.py.
+//          schema migration, and the model partitions of a consolidated
+//          database. Ports Python db/__init__.py.
 // =============================================================================
 
 package db
@@ -31,12 +32,23 @@ type Conn struct {
 	DB       *sql.DB
 	Username string
 	Path     string
+
+	// Partitioned is set on a connection for one model of a consolidated
+	// database (one file shared by every model); its queries only see
+	// rows whose model_id is ModelID. A connection opened under the file's
+	// own path instead sees every model.
+	Partitioned bool
+	ModelID     int64
 }
 
 // Open returns a database connection for the given model. Creates the DB file
 // and runs migrations if it doesn't exist. Connections are cached so repeated
 // calls for the same model return the same connection.
 //
+// When dbPath is a consolidated database, the connection is limited to the
+// model registered under username (see RegisterModel), unless username is
+// dbPath itself.
+//
 // Parameters:
 //   - username: The model username.
 //   - dbPath: Absolute path to the SQLite database file.
@@ -44,11 +56,66 @@ type Conn struct {
 // Returns:
 //   - A *Conn wrapping the database, and any error.
 func Open(username, dbPath string) (*Conn, error) {
+	return open(username, dbPath, false)
+}
+
+// OpenConsolidated is like Open, but marks dbPath as a consolidated
+// database shared by every model, creating it if needed.
+//
+// Parameters:
+//   - username: The model username.
+//   - dbPath: Absolute path to the consolidated database file.
+//
+// Returns:
+//   - A *Conn for the model's partition, and any error.
+func OpenConsolidated(username, dbPath string) (*Conn, error) {
+	return open(username, dbPath, true)
+}
+
+// open implements Open and OpenConsolidated.
+func open(username, dbPath string, consolidate bool) (*Conn, error) {
 	// Return cached connection if available.
 	if cached, ok := connPool.Load(username); ok {
 		return cached.(*Conn), nil
 	}
 
+	// Models of a consolidated database share one handle.
+	sqlDB := sharedHandle(dbPath)
+	if sqlDB == nil {
+		var err error
+		if sqlDB, err = openHandle(dbPath); err != nil {
+			return nil, err
+		}
+	}
+
+	if consolidate {
+		if _, err := sqlDB.Exec(
+			`INSERT OR REPLACE INTO schema_flags (flag_name, flag_value) VALUES ('consolidated', '1')`,
+		); err != nil {
+			return nil, fmt.Errorf("failed to mark %s consolidated: %w", dbPath, err)
+		}
+	}
+
+	conn := &Conn{
+		DB:       sqlDB,
+		Username: username,
+		Path:     dbPath,
+	}
+	if username != dbPath && isConsolidated(sqlDB) {
+		conn.Partitioned = true
+		id, err := lookupModelID(sqlDB, username)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up model %s: %w", username, err)
+		}
+		conn.ModelID = id
+	}
+
+	connPool.Store(username, conn)
+	return conn, nil
+}
+
+// openHandle opens, verifies, and migrates the database file at dbPath.
+func openHandle(dbPath string) (*sql.DB, error) {
 	// Ensure directory exists.
 	if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
 		return nil, fmt.Errorf("failed to create DB directory: %w", err)
@@ -72,23 +139,56 @@ func Open(username, dbPath string) (*Conn, error) {
 	}
 
 	// Run schema migrations.
-	if err := migrate(sqlDB); err != nil {
+	if err := migrate(sqlDB, dbPath); err != nil {
 		sqlDB.Close()
 		return nil, fmt.Errorf("failed to migrate database %s: %w", dbPath, err)
 	}
+	return sqlDB, nil
+}
 
-	conn := &Conn{
-		DB:       sqlDB,
-		Username: username,
-		Path:     dbPath,
+// Migrate applies the pending migrations of the database file at dbPath
+// through a handle of its own. Unlike Open it never returns a pooled
+// connection, which may belong to another file or predate the pending steps.
+//
+// Parameters:
+//   - dbPath: Path to the database file.
+//
+// Returns:
+//   - Any open or migration error.
+func Migrate(dbPath string) error {
+	sqlDB, err := openHandle(dbPath)
+	if err != nil {
+		return err
 	}
+	return sqlDB.Close()
+}
 
-	connPool.Store(username, conn)
-	return conn, nil
+// sharedHandle returns the handle of a pooled connection to dbPath, or nil.
+func sharedHandle(dbPath string) *sql.DB {
+	var found *sql.DB
+	connPool.Range(func(_, value any) bool {
+		if conn := value.(*Conn); conn.Path == dbPath {
+			found = conn.DB
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// handleInUse reports whether any pooled connection uses sqlDB.
+func handleInUse(sqlDB *sql.DB) bool {
+	inUse := false
+	connPool.Range(func(_, value any) bool {
+		inUse = value.(*Conn).DB == sqlDB
+		return !inUse
+	})
+	return inUse
 }
 
 // Close closes a specific model's database connection and removes it from
-// the pool.
+// the pool. The file stays open while other models of a consolidated
+// database use it.
 //
 // Parameters:
 //   - username: The model username whose connection to close.
@@ -101,19 +201,40 @@ func Close(username string) error {
 		return nil
 	}
 	conn := raw.(*Conn)
+	if handleInUse(conn.DB) {
+		return nil
+	}
 	return conn.DB.Close()
 }
 
+// closePath closes every pooled connection to dbPath.
+func closePath(dbPath string) error {
+	var firstErr error
+	connPool.Range(func(key, value any) bool {
+		if value.(*Conn).Path == dbPath {
+			if err := Close(key.(string)); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+		return true
+	})
+	return firstErr
+}
+
 // CloseAll closes all open database connections. Called during shutdown.
 //
 // Returns:
 //   - The first error encountered, or nil.
 func CloseAll() error {
 	var firstErr error
+	closed := make(map[*sql.DB]bool)
 	connPool.Range(func(key, value any) bool {
 		conn := value.(*Conn)
-		if err := conn.DB.Close(); err != nil && firstErr == nil {
-			firstErr = err
+		if !closed[conn.DB] {
+			closed[conn.DB] = true
+			if err := conn.DB.Close(); err != nil && firstErr == nil {
+				firstErr = err
+			}
 		}
 		connPool.Delete(key)
 		return true
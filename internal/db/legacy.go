@@ -0,0 +1,411 @@
+// =============================================================================
+// FILE: internal/db/legacy.go
+// PURPOSE: Legacy database import. Reads a Python OF-Scraper user_data.db and
+//          upserts its posts, messages, stories, media, labels, and profiles
+//          into a model database, reporting columns with no Go equivalent.
+//          Ports Python db/operations_/* table layouts.
+// =============================================================================
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ---------------------------------------------------------------------------
+// Legacy layout
+// ---------------------------------------------------------------------------
+
+// legacyPostColumns are the post-shaped columns the Go schema keeps.
+var legacyPostColumns = []string{"id", "post_id", "text", "price", "paid", "archived", "created_at", "model_id"}
+
+// legacyColumns lists, per legacy table in import order, the columns that
+// map onto the Go schema. Other columns are reported as unmapped.
+var legacyColumns = []struct {
+	table   string
+	columns []string
+}{
+	{"models", []string{"id", "model_id", "username"}},
+	{"profiles", []string{"id", "user_id", "username"}},
+	{"posts", legacyPostColumns},
+	{"messages", legacyPostColumns},
+	{"stories", legacyPostColumns},
+	{"others", legacyPostColumns},
+	{"products", legacyPostColumns},
+	{"labels", []string{"id", "label_id", "name", "type", "post_id", "model_id"}},
+	{"medias", []string{
+		"id", "media_id", "post_id", "link", "directory", "filename", "size",
+		"api_type", "media_type", "preview", "linked", "downloaded",
+		"created_at", "posted_at", "hash", "model_id",
+	}},
+}
+
+// legacyIgnoredTables are bookkeeping tables that carry no content.
+var legacyIgnoredTables = map[string]bool{
+	"schema_flags":    true,
+	"sqlite_sequence": true,
+}
+
+// LegacyImportResult holds the outcome of a legacy database import.
+type LegacyImportResult struct {
+	Tables        []string            // Legacy tables imported, in import order
+	Imported      map[string]int      // Rows upserted, by table
+	Skipped       map[string]int      // Rows without a usable ID, or already downloaded here
+	Unmapped      map[string][]string // Legacy columns with no Go equivalent, by table
+	UnknownTables []string            // Legacy tables that were not imported
+	BackupPath    string              // Backup of the destination taken first
+}
+
+// ---------------------------------------------------------------------------
+// Import
+// ---------------------------------------------------------------------------
+
+// ImportLegacy copies a Python OF-Scraper database into dst. Rows go through
+// the same upserts the scraper uses, so re-importing or importing over
+// existing data de-duplicates by post, media, label, and profile ID. A media
+// row already marked downloaded in dst is never reset by the import. The
+// destination is backed up first and the import runs in one transaction.
+//
+// Parameters:
+//   - ctx: Context for cancellation.
+//   - srcPath: Path to the legacy user_data.db (opened read-only).
+//   - dst: Destination model database connection.
+//
+// Returns:
+//   - The per-table import report, and any error.
+func ImportLegacy(ctx context.Context, srcPath string, dst *Conn) (LegacyImportResult, error) {
+	result := LegacyImportResult{
+		Imported: make(map[string]int),
+		Skipped:  make(map[string]int),
+		Unmapped: make(map[string][]string),
+	}
+
+	src, err := sql.Open("sqlite", fmt.Sprintf("file:%s?mode=ro", srcPath))
+	if err != nil {
+		return result, fmt.Errorf("failed to open legacy database %s: %w", srcPath, err)
+	}
+	defer src.Close()
+
+	present, err := legacyTables(ctx, src)
+	if err != nil {
+		return result, fmt.Errorf("failed to read legacy schema: %w", err)
+	}
+
+	known := make(map[string]bool, len(legacyColumns))
+	for _, lc := range legacyColumns {
+		known[lc.table] = true
+	}
+	for table := range present {
+		if !known[table] && !legacyIgnoredTables[table] {
+			result.UnknownTables = append(result.UnknownTables, table)
+		}
+	}
+	sort.Strings(result.UnknownTables)
+
+	backupPath, err := Backup(dst.Path)
+	if err != nil {
+		return result, fmt.Errorf("failed to back up destination: %w", err)
+	}
+	result.BackupPath = backupPath
+
+	// Older legacy databases leave model_id empty on some rows; fall back to
+	// the database's only model when there is exactly one.
+	defaultModelID := legacyDefaultModelID(ctx, src, present)
+
+	err = WithTx(ctx, dst, func(tx *sql.Tx) error {
+		for _, lc := range legacyColumns {
+			cols, ok := present[lc.table]
+			if !ok {
+				continue
+			}
+			if unmapped := unmappedColumns(cols, lc.columns); len(unmapped) > 0 {
+				result.Unmapped[lc.table] = unmapped
+			}
+
+			imported, skipped, err := importLegacyTable(ctx, src, tx, lc.table, defaultModelID)
+			if err != nil {
+				return fmt.Errorf("failed to import %s: %w", lc.table, err)
+			}
+			result.Tables = append(result.Tables, lc.table)
+			result.Imported[lc.table] = imported
+			result.Skipped[lc.table] = skipped
+			slog.Debug("imported legacy table", "table", lc.table, "imported", imported, "skipped", skipped)
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+// importLegacyTable upserts every row of one legacy table into tx.
+func importLegacyTable(ctx context.Context, src *sql.DB, tx *sql.Tx, table string, defaultModelID int64) (imported, skipped int, err error) {
+	rows, err := src.QueryContext(ctx, fmt.Sprintf("SELECT * FROM %s", table))
+	if err != nil {
+		return 0, 0, err
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for rows.Next() {
+		vals := make([]any, len(cols))
+		ptrs := make([]any, len(cols))
+		for i := range vals {
+			ptrs[i] = &vals[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return imported, skipped, err
+		}
+		row := make(map[string]any, len(cols))
+		for i, c := range cols {
+			row[strings.ToLower(c)] = vals[i]
+		}
+
+		modelID := legacyInt(row["model_id"])
+		if modelID == 0 {
+			modelID = defaultModelID
+		}
+
+		ok, err := importLegacyRow(ctx, tx, table, row, modelID)
+		if err != nil {
+			return imported, skipped, err
+		}
+		if ok {
+			imported++
+		} else {
+			skipped++
+		}
+	}
+	return imported, skipped, rows.Err()
+}
+
+// importLegacyRow maps one legacy row onto the Go schema and upserts it.
+// Returns false when the row was skipped.
+func importLegacyRow(ctx context.Context, tx *sql.Tx, table string, row map[string]any, modelID int64) (bool, error) {
+	switch table {
+	case "models":
+		id := legacyInt(row["model_id"])
+		if id == 0 {
+			return false, nil
+		}
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO models (model_id, username) VALUES (?, ?)
+			 ON CONFLICT(model_id) DO UPDATE SET username = COALESCE(excluded.username, models.username)`,
+			id, legacyString(row["username"]),
+		)
+		return err == nil, err
+
+	case "profiles":
+		id := legacyInt(row["user_id"])
+		if id == 0 {
+			return false, nil
+		}
+		return true, upsertProfileRow(ctx, tx, id, legacyString(row["username"]).String)
+
+	case "labels":
+		id := legacyInt(row["label_id"])
+		if id == 0 {
+			return false, nil
+		}
+		return true, upsertLabelRow(ctx, tx, id,
+			legacyString(row["name"]).String, legacyString(row["type"]).String,
+			legacyInt(row["post_id"]), modelID,
+		)
+
+	case "medias":
+		m := MediaRow{
+			MediaID:    legacyInt(row["media_id"]),
+			PostID:     legacyInt(row["post_id"]),
+			Link:       legacyString(row["link"]),
+			Directory:  legacyString(row["directory"]),
+			Filename:   legacyString(row["filename"]),
+			Size:       legacyInt(row["size"]),
+			APIType:    legacyString(row["api_type"]),
+			MediaType:  legacyString(row["media_type"]),
+			Preview:    legacyInt(row["preview"]) != 0,
+			Linked:     legacyString(row["linked"]),
+			Downloaded: legacyInt(row["downloaded"]) != 0,
+			CreatedAt:  legacyString(row["created_at"]),
+			PostedAt:   legacyString(row["posted_at"]),
+			Hash:       legacyString(row["hash"]),
+			ModelID:    modelID,
+		}
+		if m.MediaID == 0 {
+			return false, nil
+		}
+		if !m.Downloaded {
+			var downloaded int
+			err := tx.QueryRowContext(ctx,
+				`SELECT downloaded FROM medias WHERE media_id = ?`, m.MediaID,
+			).Scan(&downloaded)
+			if err == nil && downloaded != 0 {
+				return false, nil
+			}
+		}
+		return true, upsertMediaRow(ctx, tx, m)
+
+	default: // Post-shaped tables.
+		id := legacyInt(row["post_id"])
+		if id == 0 {
+			return false, nil
+		}
+		return true, upsertPostRow(ctx, tx, table, id,
+			legacyString(row["text"]).String, legacyFloat(row["price"]),
+			legacyInt(row["paid"]) != 0, legacyInt(row["archived"]) != 0,
+			legacyString(row["created_at"]).String, modelID,
+		)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Schema inspection
+// ---------------------------------------------------------------------------
+
+// legacyTables returns the legacy database's tables and their columns.
+func legacyTables(ctx context.Context, src *sql.DB) (map[string][]string, error) {
+	rows, err := src.QueryContext(ctx, `SELECT name FROM sqlite_master WHERE type = 'table'`)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	tables := make(map[string][]string, len(names))
+	for _, name := range names {
+		colRows, err := src.QueryContext(ctx, fmt.Sprintf("SELECT * FROM %s LIMIT 0", name))
+		if err != nil {
+			return nil, err
+		}
+		cols, err := colRows.Columns()
+		colRows.Close()
+		if err != nil {
+			return nil, err
+		}
+		for i := range cols {
+			cols[i] = strings.ToLower(cols[i])
+		}
+		tables[strings.ToLower(name)] = cols
+	}
+	return tables, nil
+}
+
+// unmappedColumns returns the columns of cols not in mapped.
+func unmappedColumns(cols, mapped []string) []string {
+	keep := make(map[string]bool, len(mapped))
+	for _, c := range mapped {
+		keep[c] = true
+	}
+	var out []string
+	for _, c := range cols {
+		if !keep[c] {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// legacyDefaultModelID returns the model ID of a single-model legacy
+// database, or 0 when it has none or several.
+func legacyDefaultModelID(ctx context.Context, src *sql.DB, present map[string][]string) int64 {
+	if _, ok := present["models"]; !ok {
+		return 0
+	}
+	var count, id int64
+	err := src.QueryRowContext(ctx,
+		`SELECT COUNT(DISTINCT model_id), COALESCE(MAX(model_id), 0) FROM models`,
+	).Scan(&count, &id)
+	if err != nil || count != 1 {
+		return 0
+	}
+	return id
+}
+
+// ---------------------------------------------------------------------------
+// Value conversion
+// ---------------------------------------------------------------------------
+
+// legacyInt converts a loosely-typed legacy value to an int64.
+func legacyInt(v any) int64 {
+	switch x := v.(type) {
+	case int64:
+		return x
+	case float64:
+		return int64(x)
+	case bool:
+		if x {
+			return 1
+		}
+		return 0
+	case []byte:
+		return legacyInt(string(x))
+	case string:
+		s := strings.TrimSpace(strings.ToLower(x))
+		switch s {
+		case "true":
+			return 1
+		case "false", "":
+			return 0
+		}
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return n
+		}
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return int64(f)
+		}
+	}
+	return 0
+}
+
+// legacyFloat converts a loosely-typed legacy value to a float64.
+func legacyFloat(v any) float64 {
+	switch x := v.(type) {
+	case float64:
+		return x
+	case []byte:
+		return legacyFloat(string(x))
+	case string:
+		if f, err := strconv.ParseFloat(strings.TrimSpace(x), 64); err == nil {
+			return f
+		}
+		return 0
+	}
+	return float64(legacyInt(v))
+}
+
+// legacyString converts a loosely-typed legacy value to a NullString.
+// TIMESTAMP columns may come back as time.Time and are formatted as RFC 3339.
+func legacyString(v any) sql.NullString {
+	switch x := v.(type) {
+	case nil:
+		return sql.NullString{}
+	case string:
+		return NullString(x)
+	case []byte:
+		return NullString(string(x))
+	case time.Time:
+		return NullString(x.Format(time.RFC3339))
+	default:
+		return NullString(fmt.Sprint(x))
+	}
+}
@@ -1,8 +1,9 @@
 // =============================================================================
 // FILE: internal/db/merge.go
 // PURPOSE: Database merge operations. Merges data from one model's database
-//          into another, handling duplicate detection and conflict resolution.
-//          Ports Python db/merge.py.
+//          into another, handling duplicate detection and conflict resolution,
+//          and folds per-model databases into a consolidated one. Ports
+//          Python db/merge.py.
 // =============================================================================
 
 package db
@@ -10,8 +11,10 @@ package db
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
 )
 
 // ---------------------------------------------------------------------------
@@ -25,11 +28,12 @@ type MergeResult struct {
 	MediaMerged    int
 	StoriesMerged  int
 	LabelsMerged   int
+	OthersMerged   int // others, products, attempts, queue, and profiles rows
 	Conflicts      int
 }
 
 // MergeDatabases merges all data from srcDB into dstDB. Creates a backup
-// of dstDB before merging.
+// of dstDB before merging, and merges nothing if the backup fails.
 //
 // Parameters:
 //   - ctx: Context for cancellation.
@@ -42,34 +46,43 @@ func MergeDatabases(ctx context.Context, srcConn, dstConn *Conn) (MergeResult, e
 	var result MergeResult
 
 	// Backup destination before merge.
-	if _, err := Backup(dstConn.Path); err != nil {
-		slog.Warn("failed to backup before merge", "path", dstConn.Path, "error", err)
+	backupPath, err := Backup(dstConn.Path)
+	if err != nil {
+		return result, fmt.Errorf("backup before merge: %w", err)
 	}
+	slog.Debug("backed up database before merge", "path", dstConn.Path, "backup", backupPath)
 
 	// Merge within a transaction.
-	err := WithTx(ctx, dstConn, func(tx *sql.Tx) error {
+	err = WithTx(ctx, dstConn, func(tx *sql.Tx) error {
 		var err error
 
 		// Merge posts.
-		result.PostsMerged, err = mergeTable(ctx, srcConn.DB, tx, "posts", "post_id")
+		result.PostsMerged, err = mergeTable(ctx, srcConn, tx, "posts", 0)
 		if err != nil {
 			return fmt.Errorf("failed to merge posts: %w", err)
 		}
 
 		// Merge messages.
-		result.MessagesMerged, err = mergeTable(ctx, srcConn.DB, tx, "messages", "post_id")
+		result.MessagesMerged, err = mergeTable(ctx, srcConn, tx, "messages", 0)
 		if err != nil {
 			return fmt.Errorf("failed to merge messages: %w", err)
 		}
 
 		// Merge stories.
-		result.StoriesMerged, err = mergeTable(ctx, srcConn.DB, tx, "stories", "post_id")
+		result.StoriesMerged, err = mergeTable(ctx, srcConn, tx, "stories", 0)
 		if err != nil {
 			return fmt.Errorf("failed to merge stories: %w", err)
 		}
 
+		// Index the merged post text.
+		for _, table := range []string{"posts", "messages", "stories"} {
+			if err := indexTable(ctx, tx, table); err != nil {
+				return err
+			}
+		}
+
 		// Merge media.
-		result.MediaMerged, err = mergeMediaTable(ctx, srcConn.DB, tx)
+		result.MediaMerged, err = mergeMediaTable(ctx, srcConn, tx)
 		if err != nil {
 			return fmt.Errorf("failed to merge media: %w", err)
 		}
@@ -80,11 +93,21 @@ func MergeDatabases(ctx context.Context, srcConn, dstConn *Conn) (MergeResult, e
 	return result, err
 }
 
-// mergeTable copies rows from src table into dst tx, skipping duplicates.
-func mergeTable(ctx context.Context, srcDB *sql.DB, dstTx *sql.Tx, table, uniqueCol string) (int, error) {
-	rows, err := srcDB.QueryContext(ctx,
-		fmt.Sprintf("SELECT * FROM %s", table),
-	)
+// mergeTable copies rows from src table into dst tx, skipping rows whose
+// unique key ((model_id, post_id), (model_id, media_id), ...) is already
+// present. The id column is
+// left for the destination to assign, so rows of databases numbered
+// independently do not collide on it. A non-zero modelID replaces the
+// model_id of every copied row.
+func mergeTable(ctx context.Context, srcConn *Conn, dstTx *sql.Tx, table string, modelID int64) (int, error) {
+	query := fmt.Sprintf("SELECT * FROM %s", table)
+	var args []any
+	if table != "profiles" {
+		filter, filterArgs := srcConn.modelFilter("model_id")
+		query += " WHERE " + filter
+		args = filterArgs
+	}
+	rows, err := srcConn.DB.QueryContext(ctx, query, args...)
 	if err != nil {
 		return 0, err
 	}
@@ -95,6 +118,17 @@ func mergeTable(ctx context.Context, srcDB *sql.DB, dstTx *sql.Tx, table, unique
 		return 0, err
 	}
 
+	// Build INSERT OR IGNORE statement over every column but id.
+	var names, placeholders []string
+	for _, c := range cols {
+		if c != "id" {
+			names = append(names, c)
+			placeholders = append(placeholders, "?")
+		}
+	}
+	insert := fmt.Sprintf("INSERT OR IGNORE INTO %s (%s) VALUES (%s)",
+		table, strings.Join(names, ", "), strings.Join(placeholders, ", "))
+
 	merged := 0
 	for rows.Next() {
 		vals := make([]any, len(cols))
@@ -106,16 +140,18 @@ func mergeTable(ctx context.Context, srcDB *sql.DB, dstTx *sql.Tx, table, unique
 			return merged, err
 		}
 
-		// Build INSERT OR IGNORE statement.
-		placeholders := ""
-		for i := range cols {
-			if i > 0 {
-				placeholders += ", "
+		insertVals := make([]any, 0, len(names))
+		for i, c := range cols {
+			switch {
+			case c == "id":
+				continue
+			case c == "model_id" && modelID != 0:
+				insertVals = append(insertVals, modelID)
+			default:
+				insertVals = append(insertVals, vals[i])
 			}
-			placeholders += "?"
 		}
-		query := fmt.Sprintf("INSERT OR IGNORE INTO %s VALUES (%s)", table, placeholders)
-		result, err := dstTx.ExecContext(ctx, query, vals...)
+		result, err := dstTx.ExecContext(ctx, insert, insertVals...)
 		if err != nil {
 			return merged, err
 		}
@@ -127,6 +163,122 @@ func mergeTable(ctx context.Context, srcDB *sql.DB, dstTx *sql.Tx, table, unique
 }
 
 // mergeMediaTable handles media merge with special conflict resolution.
-func mergeMediaTable(ctx context.Context, srcDB *sql.DB, dstTx *sql.Tx) (int, error) {
-	return mergeTable(ctx, srcDB, dstTx, "medias", "media_id")
+func mergeMediaTable(ctx context.Context, srcConn *Conn, dstTx *sql.Tx) (int, error) {
+	return mergeTable(ctx, srcConn, dstTx, "medias", 0)
+}
+
+// ---------------------------------------------------------------------------
+// Consolidation
+// ---------------------------------------------------------------------------
+
+// ErrAlreadyConsolidated is returned when a model was already folded into
+// the consolidated database.
+var ErrAlreadyConsolidated = errors.New("model already consolidated")
+
+// consolidateTables are the tables a fold copies, with the MergeResult
+// field counting their rows (nil: counted in OthersMerged).
+var consolidateTables = []struct {
+	table string
+	count func(*MergeResult) *int
+}{
+	{"posts", func(r *MergeResult) *int { return &r.PostsMerged }},
+	{"messages", func(r *MergeResult) *int { return &r.MessagesMerged }},
+	{"stories", func(r *MergeResult) *int { return &r.StoriesMerged }},
+	{"labels", func(r *MergeResult) *int { return &r.LabelsMerged }},
+	{"medias", func(r *MergeResult) *int { return &r.MediaMerged }},
+	{"others", nil},
+	{"products", nil},
+	{"download_attempts", nil},
+	{"download_queue", nil},
+	{"profiles", nil},
+}
+
+// ModelIDOf returns the ID of the model a per-model database belongs to:
+// the one registered for its username, or else the model_id most of its
+// posts and media carry.
+//
+// Parameters:
+//   - ctx: Context.
+//   - conn: The per-model database connection.
+//   - username: The model's username.
+//
+// Returns:
+//   - The model ID (0 if the database records none), and any error.
+func ModelIDOf(ctx context.Context, conn *Conn, username string) (int64, error) {
+	if id, err := lookupModelID(conn.DB, username); err != nil || id != 0 {
+		return id, err
+	}
+	var id int64
+	err := conn.DB.QueryRowContext(ctx,
+		`SELECT model_id FROM (
+		   SELECT model_id FROM posts UNION ALL SELECT model_id FROM messages UNION ALL SELECT model_id FROM medias
+		 ) WHERE model_id IS NOT NULL AND model_id != 0
+		 GROUP BY model_id ORDER BY COUNT(*) DESC LIMIT 1`,
+	).Scan(&id)
+	if IsNotFound(err) {
+		return 0, nil
+	}
+	return id, err
+}
+
+// ConsolidateModel folds a per-model database into the consolidated
+// database dst as the partition of modelID. Every model table is copied
+// with mergeTable, its rows assigned to modelID; the model is registered
+// under username and its post text is indexed. The fold runs in one
+// transaction and records a flag, so folding the same model again returns
+// ErrAlreadyConsolidated.
+//
+// Parameters:
+//   - ctx: Context for cancellation.
+//   - srcConn: The model's per-model database.
+//   - dstConn: The consolidated database, opened under its own path.
+//   - modelID: The model's numeric ID.
+//   - username: The model's username.
+//
+// Returns:
+//   - MergeResult with counts of copied rows, and any error.
+func ConsolidateModel(ctx context.Context, srcConn, dstConn *Conn, modelID int64, username string) (MergeResult, error) {
+	var result MergeResult
+	if modelID == 0 {
+		return result, fmt.Errorf("model ID of %s is unknown", username)
+	}
+	flag := "consolidated:" + username
+
+	err := WithTx(ctx, dstConn, func(tx *sql.Tx) error {
+		var done int
+		if err := tx.QueryRowContext(ctx,
+			`SELECT COUNT(*) FROM schema_flags WHERE flag_name = ?`, flag,
+		).Scan(&done); err != nil {
+			return err
+		}
+		if done > 0 {
+			return ErrAlreadyConsolidated
+		}
+
+		for _, t := range consolidateTables {
+			n, err := mergeTable(ctx, srcConn, tx, t.table, modelID)
+			if err != nil {
+				return fmt.Errorf("failed to merge %s: %w", t.table, err)
+			}
+			if t.count != nil {
+				*t.count(&result) = n
+			} else {
+				result.OthersMerged += n
+			}
+		}
+		for _, a := range searchAreas {
+			if err := indexTable(ctx, tx, a.table); err != nil {
+				return err
+			}
+		}
+		if err := upsertModelRow(ctx, tx, modelID, username); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO schema_flags (flag_name, flag_value) VALUES (?, ?)`,
+			flag, fmt.Sprintf("%d", modelID),
+		)
+		return err
+	})
+	return result, err
 }
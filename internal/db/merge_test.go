@@ -0,0 +1,63 @@
+// =============================================================================
+// FILE: internal/db/merge_test.go
+// PURPOSE: Tests for database merges: the destination is backed up first,
+//          and a merge whose backup fails leaves the destination untouched.
+// =============================================================================
+
+package db_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"gofscraper/internal/db"
+)
+
+func TestMergeDatabases(t *testing.T) {
+	ctx := context.Background()
+	src := writeModelDB(t, "merge_src", 1, "from the source")
+
+	dstPath := filepath.Join(t.TempDir(), "user_data.db")
+	dst, err := db.Open("merge_dst", dstPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close("merge_dst") })
+
+	result, err := db.MergeDatabases(ctx, src, dst)
+	if err != nil {
+		t.Fatalf("MergeDatabases: %v", err)
+	}
+	if result.PostsMerged != 1 || result.MediaMerged != 1 {
+		t.Errorf("merged %+v, want the post and its media", result)
+	}
+	if backups, err := db.ListBackups(dstPath); err != nil || len(backups) != 1 {
+		t.Errorf("backups = %v (err %v), want one taken before the merge", backups, err)
+	}
+	hits, err := db.SearchPosts(ctx, dst, db.SearchQuery{Match: "source"})
+	if err != nil || len(hits) != 1 {
+		t.Errorf("search after merge = %+v (err %v), want the merged post", hits, err)
+	}
+}
+
+func TestMergeDatabasesBackupFails(t *testing.T) {
+	ctx := context.Background()
+	src := writeModelDB(t, "merge_src_nobackup", 1, "from the source")
+
+	dst, err := db.Open("merge_dst_nobackup", filepath.Join(t.TempDir(), "user_data.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close("merge_dst_nobackup") })
+	// Backup fails for a file that is not on disk where the conn says.
+	dst.Path = filepath.Join(t.TempDir(), "moved.db")
+
+	if _, err := db.MergeDatabases(ctx, src, dst); err == nil {
+		t.Fatal("MergeDatabases succeeded without a backup")
+	}
+	stats, err := db.GetStats(ctx, dst)
+	if err != nil || stats.PostCount != 0 || stats.MediaCount != 0 {
+		t.Errorf("destination stats = %+v (err %v), want nothing merged", stats, err)
+	}
+}
@@ -35,18 +35,7 @@ import (
 // Returns:
 //   - Error if the upsert fails.
 func UpsertPost(ctx context.Context, conn *Conn, postID int64, text string, price float64, paid, archived bool, createdAt string, modelID int64) error {
-	_, err := conn.DB.ExecContext(ctx,
-		`INSERT INTO posts (post_id, text, price, paid, archived, created_at, model_id)
-		 VALUES (?, ?, ?, ?, ?, ?, ?)
-		 ON CONFLICT(post_id) DO UPDATE SET
-		   text = excluded.text,
-		   price = excluded.price,
-		   paid = excluded.paid,
-		   archived = excluded.archived,
-		   created_at = excluded.created_at`,
-		postID, text, price, boolToInt(paid), boolToInt(archived), createdAt, modelID,
-	)
-	return err
+	return upsertPostRow(ctx, conn.DB, "posts", postID, text, price, paid, archived, createdAt, conn.rowModelID(modelID))
 }
 
 // GetPost retrieves a single post by ID.
@@ -59,9 +48,10 @@ func UpsertPost(ctx context.Context, conn *Conn, postID int64, text string, pric
 // Returns:
 //   - A PostRow, and any error (sql.ErrNoRows if not found).
 func GetPost(ctx context.Context, conn *Conn, postID int64) (*PostRow, error) {
+	filter, args := conn.modelFilter("model_id")
 	row := conn.DB.QueryRowContext(ctx,
-		`SELECT post_id, text, price, paid, archived, created_at, model_id FROM posts WHERE post_id = ?`,
-		postID,
+		`SELECT post_id, text, price, paid, archived, created_at, model_id FROM posts WHERE post_id = ? AND `+filter,
+		append([]any{postID}, args...)...,
 	)
 
 	p := &PostRow{}
@@ -81,8 +71,15 @@ func GetPost(ctx context.Context, conn *Conn, postID int64) (*PostRow, error) {
 // Returns:
 //   - Slice of PostRow, and any error.
 func GetAllPosts(ctx context.Context, conn *Conn) ([]PostRow, error) {
+	return queryPostRows(ctx, conn, "posts")
+}
+
+// queryPostRows reads every row of a post-shaped table, newest first.
+func queryPostRows(ctx context.Context, conn *Conn, table string) ([]PostRow, error) {
+	filter, args := conn.modelFilter("model_id")
 	rows, err := conn.DB.QueryContext(ctx,
-		`SELECT post_id, text, price, paid, archived, created_at, model_id FROM posts ORDER BY created_at DESC`,
+		`SELECT post_id, text, price, paid, archived, created_at, model_id FROM `+table+` WHERE `+filter+` ORDER BY created_at DESC`,
+		args...,
 	)
 	if err != nil {
 		return nil, err
@@ -114,26 +111,8 @@ func GetAllPosts(ctx context.Context, conn *Conn) ([]PostRow, error) {
 // Returns:
 //   - Error if the upsert fails.
 func UpsertMedia(ctx context.Context, conn *Conn, m MediaRow) error {
-	_, err := conn.DB.ExecContext(ctx,
-		`INSERT INTO medias (media_id, post_id, link, directory, filename, size, api_type, media_type, preview, linked, downloaded, created_at, posted_at, hash, model_id)
-		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-		 ON CONFLICT(media_id) DO UPDATE SET
-		   link = excluded.link,
-		   directory = excluded.directory,
-		   filename = excluded.filename,
-		   size = excluded.size,
-		   api_type = excluded.api_type,
-		   media_type = excluded.media_type,
-		   preview = excluded.preview,
-		   linked = excluded.linked,
-		   downloaded = excluded.downloaded,
-		   posted_at = excluded.posted_at,
-		   hash = excluded.hash`,
-		m.MediaID, m.PostID, m.Link, m.Directory, m.Filename, m.Size,
-		m.APIType, m.MediaType, boolToInt(m.Preview), m.Linked,
-		boolToInt(m.Downloaded), m.CreatedAt, m.PostedAt, m.Hash, m.ModelID,
-	)
-	return err
+	m.ModelID = conn.rowModelID(m.ModelID)
+	return upsertMediaRow(ctx, conn.DB, m)
 }
 
 // GetMediaByPostID retrieves all media for a given post.
@@ -146,10 +125,12 @@ func UpsertMedia(ctx context.Context, conn *Conn, m MediaRow) error {
 // Returns:
 //   - Slice of MediaRow, and any error.
 func GetMediaByPostID(ctx context.Context, conn *Conn, postID int64) ([]MediaRow, error) {
+	filter, args := conn.modelFilter("model_id")
 	rows, err := conn.DB.QueryContext(ctx,
-		`SELECT media_id, post_id, link, directory, filename, size, api_type, media_type, preview, linked, downloaded, created_at, posted_at, hash, model_id
-		 FROM medias WHERE post_id = ?`,
-		postID,
+		`SELECT media_id, post_id, link, directory, filename, size, api_type, media_type, preview, linked, downloaded, created_at, posted_at, hash, model_id, quality,
+		        duration, width, height, codec, bitrate
+		 FROM medias WHERE post_id = ? AND `+filter,
+		append([]any{postID}, args...)...,
 	)
 	if err != nil {
 		return nil, err
@@ -168,9 +149,12 @@ func GetMediaByPostID(ctx context.Context, conn *Conn, postID int64) ([]MediaRow
 // Returns:
 //   - Slice of MediaRow, and any error.
 func GetAllMedia(ctx context.Context, conn *Conn) ([]MediaRow, error) {
+	filter, args := conn.modelFilter("model_id")
 	rows, err := conn.DB.QueryContext(ctx,
-		`SELECT media_id, post_id, link, directory, filename, size, api_type, media_type, preview, linked, downloaded, created_at, posted_at, hash, model_id
-		 FROM medias ORDER BY created_at DESC`,
+		`SELECT media_id, post_id, link, directory, filename, size, api_type, media_type, preview, linked, downloaded, created_at, posted_at, hash, model_id, quality,
+		        duration, width, height, codec, bitrate
+		 FROM medias WHERE `+filter+` ORDER BY created_at DESC`,
+		args...,
 	)
 	if err != nil {
 		return nil, err
@@ -189,9 +173,12 @@ func GetAllMedia(ctx context.Context, conn *Conn) ([]MediaRow, error) {
 // Returns:
 //   - Slice of MediaRow.
 func GetDownloadedMedia(ctx context.Context, conn *Conn) ([]MediaRow, error) {
+	filter, args := conn.modelFilter("model_id")
 	rows, err := conn.DB.QueryContext(ctx,
-		`SELECT media_id, post_id, link, directory, filename, size, api_type, media_type, preview, linked, downloaded, created_at, posted_at, hash, model_id
-		 FROM medias WHERE downloaded = 1`,
+		`SELECT media_id, post_id, link, directory, filename, size, api_type, media_type, preview, linked, downloaded, created_at, posted_at, hash, model_id, quality,
+		        duration, width, height, codec, bitrate
+		 FROM medias WHERE downloaded = 1 AND `+filter,
+		args...,
 	)
 	if err != nil {
 		return nil, err
@@ -201,24 +188,50 @@ func GetDownloadedMedia(ctx context.Context, conn *Conn) ([]MediaRow, error) {
 	return scanMediaRows(rows)
 }
 
+// MediaPath is the recorded location of one media file.
+type MediaPath struct {
+	MediaID   int64
+	Directory string
+	Filename  string
+}
+
+// UpdateMediaPaths sets the directory and filename of many media rows in a
+// single transaction, so either every new location is recorded or none is.
+//
+// Parameters:
+//   - ctx: Context.
+//   - conn: Database connection.
+//   - paths: The new locations, keyed by media ID.
+//
+// Returns:
+//   - Error if any update fails; the transaction is then rolled back.
+func UpdateMediaPaths(ctx context.Context, conn *Conn, paths []MediaPath) error {
+	filter, filterArgs := conn.modelFilter("model_id")
+	return WithTx(ctx, conn, func(tx *sql.Tx) error {
+		for _, p := range paths {
+			if _, err := tx.ExecContext(ctx,
+				`UPDATE medias SET directory = ?, filename = ? WHERE media_id = ? AND `+filter,
+				append([]any{p.Directory, p.Filename, p.MediaID}, filterArgs...)...,
+			); err != nil {
+				return fmt.Errorf("update path of media %d: %w", p.MediaID, err)
+			}
+		}
+		return nil
+	})
+}
+
 // ---------------------------------------------------------------------------
 // Message operations
 // ---------------------------------------------------------------------------
 
 // UpsertMessage inserts or updates a message record.
 func UpsertMessage(ctx context.Context, conn *Conn, postID int64, text string, price float64, paid, archived bool, createdAt string, modelID int64) error {
-	_, err := conn.DB.ExecContext(ctx,
-		`INSERT INTO messages (post_id, text, price, paid, archived, created_at, model_id)
-		 VALUES (?, ?, ?, ?, ?, ?, ?)
-		 ON CONFLICT(post_id) DO UPDATE SET
-		   text = excluded.text,
-		   price = excluded.price,
-		   paid = excluded.paid,
-		   archived = excluded.archived,
-		   created_at = excluded.created_at`,
-		postID, text, price, boolToInt(paid), boolToInt(archived), createdAt, modelID,
-	)
-	return err
+	return upsertPostRow(ctx, conn.DB, "messages", postID, text, price, paid, archived, createdAt, conn.rowModelID(modelID))
+}
+
+// GetAllMessages retrieves all message records.
+func GetAllMessages(ctx context.Context, conn *Conn) ([]PostRow, error) {
+	return queryPostRows(ctx, conn, "messages")
 }
 
 // ---------------------------------------------------------------------------
@@ -227,18 +240,12 @@ func UpsertMessage(ctx context.Context, conn *Conn, postID int64, text string, p
 
 // UpsertStory inserts or updates a story record.
 func UpsertStory(ctx context.Context, conn *Conn, postID int64, text string, price float64, paid, archived bool, createdAt string, modelID int64) error {
-	_, err := conn.DB.ExecContext(ctx,
-		`INSERT INTO stories (post_id, text, price, paid, archived, created_at, model_id)
-		 VALUES (?, ?, ?, ?, ?, ?, ?)
-		 ON CONFLICT(post_id) DO UPDATE SET
-		   text = excluded.text,
-		   price = excluded.price,
-		   paid = excluded.paid,
-		   archived = excluded.archived,
-		   created_at = excluded.created_at`,
-		postID, text, price, boolToInt(paid), boolToInt(archived), createdAt, modelID,
-	)
-	return err
+	return upsertPostRow(ctx, conn.DB, "stories", postID, text, price, paid, archived, createdAt, conn.rowModelID(modelID))
+}
+
+// GetAllStories retrieves all story and highlight records.
+func GetAllStories(ctx context.Context, conn *Conn) ([]PostRow, error) {
+	return queryPostRows(ctx, conn, "stories")
 }
 
 // ---------------------------------------------------------------------------
@@ -247,15 +254,7 @@ func UpsertStory(ctx context.Context, conn *Conn, postID int64, text string, pri
 
 // UpsertLabel inserts or updates a label record.
 func UpsertLabel(ctx context.Context, conn *Conn, labelID int64, name, labelType string, postID, modelID int64) error {
-	_, err := conn.DB.ExecContext(ctx,
-		`INSERT INTO labels (label_id, name, type, post_id, model_id)
-		 VALUES (?, ?, ?, ?, ?)
-		 ON CONFLICT(label_id, post_id) DO UPDATE SET
-		   name = excluded.name,
-		   type = excluded.type`,
-		labelID, name, labelType, postID, modelID,
-	)
-	return err
+	return upsertLabelRow(ctx, conn.DB, labelID, name, labelType, postID, conn.rowModelID(modelID))
 }
 
 // ---------------------------------------------------------------------------
@@ -264,13 +263,7 @@ func UpsertLabel(ctx context.Context, conn *Conn, labelID int64, name, labelType
 
 // UpsertProfile inserts or updates a profile record.
 func UpsertProfile(ctx context.Context, conn *Conn, userID int64, username string) error {
-	_, err := conn.DB.ExecContext(ctx,
-		`INSERT INTO profiles (user_id, username)
-		 VALUES (?, ?)
-		 ON CONFLICT(user_id) DO UPDATE SET username = excluded.username`,
-		userID, username,
-	)
-	return err
+	return upsertProfileRow(ctx, conn.DB, userID, username)
 }
 
 // ---------------------------------------------------------------------------
@@ -298,28 +291,30 @@ type Stats struct {
 //   - Stats struct, and any error.
 func GetStats(ctx context.Context, conn *Conn) (Stats, error) {
 	var s Stats
+	filter, args := conn.modelFilter("model_id")
 
 	queries := []struct {
 		query string
 		dest  *int
 	}{
-		{"SELECT COUNT(*) FROM posts", &s.PostCount},
-		{"SELECT COUNT(*) FROM messages", &s.MessageCount},
-		{"SELECT COUNT(*) FROM medias", &s.MediaCount},
-		{"SELECT COUNT(*) FROM stories", &s.StoryCount},
-		{"SELECT COUNT(*) FROM labels", &s.LabelCount},
-		{"SELECT COUNT(*) FROM medias WHERE downloaded = 1", &s.Downloaded},
+		{"SELECT COUNT(*) FROM posts WHERE " + filter, &s.PostCount},
+		{"SELECT COUNT(*) FROM messages WHERE " + filter, &s.MessageCount},
+		{"SELECT COUNT(*) FROM medias WHERE " + filter, &s.MediaCount},
+		{"SELECT COUNT(*) FROM stories WHERE " + filter, &s.StoryCount},
+		{"SELECT COUNT(*) FROM labels WHERE " + filter, &s.LabelCount},
+		{"SELECT COUNT(*) FROM medias WHERE downloaded = 1 AND " + filter, &s.Downloaded},
 	}
 
 	for _, q := range queries {
-		if err := conn.DB.QueryRowContext(ctx, q.query).Scan(q.dest); err != nil {
+		if err := conn.DB.QueryRowContext(ctx, q.query, args...).Scan(q.dest); err != nil {
 			return s, fmt.Errorf("stats query failed: %w", err)
 		}
 	}
 
 	// Total size of downloaded media.
 	conn.DB.QueryRowContext(ctx,
-		"SELECT COALESCE(SUM(size), 0) FROM medias WHERE downloaded = 1",
+		"SELECT COALESCE(SUM(size), 0) FROM medias WHERE downloaded = 1 AND "+filter,
+		args...,
 	).Scan(&s.TotalSize)
 
 	return s, nil
@@ -353,6 +348,94 @@ func WithTx(ctx context.Context, conn *Conn, fn func(tx *sql.Tx) error) error {
 	return tx.Commit()
 }
 
+// ---------------------------------------------------------------------------
+// Upsert statements
+// ---------------------------------------------------------------------------
+
+// execer is satisfied by *sql.DB and *sql.Tx, so the upserts can run inside
+// a caller's transaction.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// upsertPostRow upserts into a post-shaped table (posts, messages, stories,
+// others, products), keyed by (model_id, post_id), and into its
+// post_search entry.
+func upsertPostRow(ctx context.Context, ex execer, table string, postID int64, text string, price float64, paid, archived bool, createdAt string, modelID int64) error {
+	_, err := ex.ExecContext(ctx,
+		`INSERT INTO `+table+` (post_id, text, price, paid, archived, created_at, model_id)
+		 VALUES (?, ?, ?, ?, ?, ?, ?)
+		 ON CONFLICT(model_id, post_id) DO UPDATE SET
+		   text = excluded.text,
+		   price = excluded.price,
+		   paid = excluded.paid,
+		   archived = excluded.archived,
+		   created_at = excluded.created_at`,
+		postID, text, price, boolToInt(paid), boolToInt(archived), createdAt, modelID,
+	)
+	if err != nil {
+		return err
+	}
+	return indexPost(ctx, ex, table, postID, modelID)
+}
+
+// upsertMediaRow upserts a medias row, keyed by (model_id, media_id).
+func upsertMediaRow(ctx context.Context, ex execer, m MediaRow) error {
+	_, err := ex.ExecContext(ctx,
+		`INSERT INTO medias (media_id, post_id, link, directory, filename, size, api_type, media_type, preview, linked, downloaded, created_at, posted_at, hash, model_id, quality,
+		                     duration, width, height, codec, bitrate)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		 ON CONFLICT(model_id, media_id) DO UPDATE SET
+		   link = excluded.link,
+		   directory = excluded.directory,
+		   filename = excluded.filename,
+		   size = excluded.size,
+		   api_type = excluded.api_type,
+		   media_type = excluded.media_type,
+		   preview = excluded.preview,
+		   linked = excluded.linked,
+		   downloaded = excluded.downloaded,
+		   posted_at = excluded.posted_at,
+		   hash = COALESCE(excluded.hash, medias.hash),
+		   quality = COALESCE(excluded.quality, medias.quality),
+		   duration = COALESCE(excluded.duration, medias.duration),
+		   width = COALESCE(excluded.width, medias.width),
+		   height = COALESCE(excluded.height, medias.height),
+		   codec = COALESCE(excluded.codec, medias.codec),
+		   bitrate = COALESCE(excluded.bitrate, medias.bitrate)`,
+		m.MediaID, m.PostID, m.Link, m.Directory, m.Filename, m.Size,
+		m.APIType, m.MediaType, boolToInt(m.Preview), m.Linked,
+		boolToInt(m.Downloaded), m.CreatedAt, m.PostedAt, m.Hash, m.ModelID,
+		m.Quality, m.Duration, m.Width, m.Height, m.Codec, m.Bitrate,
+	)
+	return err
+}
+
+// upsertLabelRow upserts a labels row, keyed by (model_id, label_id,
+// post_id).
+func upsertLabelRow(ctx context.Context, ex execer, labelID int64, name, labelType string, postID, modelID int64) error {
+	_, err := ex.ExecContext(ctx,
+		`INSERT INTO labels (label_id, name, type, post_id, model_id)
+		 VALUES (?, ?, ?, ?, ?)
+		 ON CONFLICT(model_id, label_id, post_id) DO UPDATE SET
+		   name = excluded.name,
+		   type = excluded.type`,
+		labelID, name, labelType, postID, modelID,
+	)
+	return err
+}
+
+// upsertProfileRow upserts a profiles row, keyed by user_id.
+func upsertProfileRow(ctx context.Context, ex execer, userID int64, username string) error {
+	_, err := ex.ExecContext(ctx,
+		`INSERT INTO profiles (user_id, username)
+		 VALUES (?, ?)
+		 ON CONFLICT(user_id) DO UPDATE SET username = excluded.username`,
+		userID, username,
+	)
+	return err
+}
+
 // ---------------------------------------------------------------------------
 // Row types
 // ---------------------------------------------------------------------------
@@ -385,6 +468,14 @@ type MediaRow struct {
 	PostedAt   sql.NullString
 	Hash       sql.NullString
 	ModelID    int64
+	Quality    sql.NullString // Rendition downloaded ("source", "720", ...)
+
+	// Probed from the downloaded file; NULL until probed.
+	Duration sql.NullFloat64 // Seconds
+	Width    sql.NullInt64
+	Height   sql.NullInt64
+	Codec    sql.NullString
+	Bitrate  sql.NullInt64 // Bits per second
 }
 
 // ---------------------------------------------------------------------------
@@ -407,6 +498,7 @@ func scanMediaRows(rows *sql.Rows) ([]MediaRow, error) {
 			&m.MediaID, &m.PostID, &m.Link, &m.Directory, &m.Filename,
 			&m.Size, &m.APIType, &m.MediaType, &preview, &m.Linked,
 			&downloaded, &m.CreatedAt, &m.PostedAt, &m.Hash, &m.ModelID,
+			&m.Quality, &m.Duration, &m.Width, &m.Height, &m.Codec, &m.Bitrate,
 		); err != nil {
 			return nil, err
 		}
@@ -0,0 +1,169 @@
+// =============================================================================
+// FILE: internal/db/queue.go
+// PURPOSE: Persistent download queue. Stores the media a download run has
+//          queued, with their resolved file paths and state, so a run killed
+//          partway through can be resumed without re-fetching content.
+// =============================================================================
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// ---------------------------------------------------------------------------
+// Row type
+// ---------------------------------------------------------------------------
+
+// QueueState is the state of a queued download.
+type QueueState string
+
+const (
+	QueuePending QueueState = "pending" // Waiting for a worker
+	QueueActive  QueueState = "active"  // Picked up by a worker
+	QueueDone    QueueState = "done"    // Finished, successfully or not
+)
+
+// QueueRow represents a row in the download_queue table.
+type QueueRow struct {
+	MediaID   int64
+	PostID    int64
+	ModelID   int64
+	State     QueueState
+	FilePath  string
+	Media     string // JSON-encoded model.Media and its post
+	QueuedAt  string // RFC 3339
+	UpdatedAt string // RFC 3339
+}
+
+// ---------------------------------------------------------------------------
+// Queue operations
+// ---------------------------------------------------------------------------
+
+// EnqueueDownloads adds media to the queue as pending in one transaction.
+// Media already queued are reset to pending with the new data.
+//
+// Parameters:
+//   - ctx: Context.
+//   - conn: Database connection.
+//   - rows: The queue entries; State and timestamps are set here.
+//
+// Returns:
+//   - Error if any insert fails.
+func EnqueueDownloads(ctx context.Context, conn *Conn, rows []QueueRow) error {
+	now := time.Now().UTC().Format(time.RFC3339)
+	return WithTx(ctx, conn, func(tx *sql.Tx) error {
+		for _, r := range rows {
+			_, err := tx.ExecContext(ctx,
+				`INSERT INTO download_queue (media_id, post_id, model_id, state, filepath, media, queued_at, updated_at)
+				 VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+				 ON CONFLICT(model_id, media_id) DO UPDATE SET
+				   post_id = excluded.post_id,
+				   state = excluded.state,
+				   filepath = excluded.filepath,
+				   media = excluded.media,
+				   updated_at = excluded.updated_at`,
+				r.MediaID, r.PostID, conn.rowModelID(r.ModelID), QueuePending, NullString(r.FilePath), r.Media, now, now,
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SetQueueState moves one queued download to a new state.
+//
+// Parameters:
+//   - ctx: Context.
+//   - conn: Database connection.
+//   - mediaID: The media ID.
+//   - state: The new state.
+//
+// Returns:
+//   - Error if the update fails.
+func SetQueueState(ctx context.Context, conn *Conn, mediaID int64, state QueueState) error {
+	filter, args := conn.modelFilter("model_id")
+	_, err := conn.DB.ExecContext(ctx,
+		`UPDATE download_queue SET state = ?, updated_at = ? WHERE media_id = ? AND `+filter,
+		append([]any{state, time.Now().UTC().Format(time.RFC3339), mediaID}, args...)...,
+	)
+	return err
+}
+
+// RequeueActive returns downloads left active by an interrupted run to
+// pending.
+//
+// Parameters:
+//   - ctx: Context.
+//   - conn: Database connection.
+//
+// Returns:
+//   - The number of downloads re-queued, and any error.
+func RequeueActive(ctx context.Context, conn *Conn) (int64, error) {
+	filter, args := conn.modelFilter("model_id")
+	res, err := conn.DB.ExecContext(ctx,
+		`UPDATE download_queue SET state = ?, updated_at = ? WHERE state = ? AND `+filter,
+		append([]any{QueuePending, time.Now().UTC().Format(time.RFC3339), QueueActive}, args...)...,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// GetQueuedDownloads returns the downloads not yet done, oldest first.
+//
+// Parameters:
+//   - ctx: Context.
+//   - conn: Database connection.
+//
+// Returns:
+//   - The pending and active entries, and any error.
+func GetQueuedDownloads(ctx context.Context, conn *Conn) ([]QueueRow, error) {
+	filter, args := conn.modelFilter("model_id")
+	rows, err := conn.DB.QueryContext(ctx,
+		`SELECT media_id, post_id, model_id, state, filepath, media, queued_at, updated_at
+		 FROM download_queue WHERE state != ? AND `+filter+` ORDER BY queued_at, media_id`,
+		append([]any{QueueDone}, args...)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []QueueRow
+	for rows.Next() {
+		var r QueueRow
+		var postID, modelID sql.NullInt64
+		var filePath sql.NullString
+		if err := rows.Scan(&r.MediaID, &postID, &modelID, &r.State, &filePath, &r.Media, &r.QueuedAt, &r.UpdatedAt); err != nil {
+			return nil, err
+		}
+		r.PostID = Int64FromNull(postID)
+		r.ModelID = Int64FromNull(modelID)
+		r.FilePath = StringFromNull(filePath)
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+// ClearDoneDownloads removes finished downloads from the queue.
+//
+// Parameters:
+//   - ctx: Context.
+//   - conn: Database connection.
+//
+// Returns:
+//   - Error if the delete fails.
+func ClearDoneDownloads(ctx context.Context, conn *Conn) error {
+	filter, args := conn.modelFilter("model_id")
+	_, err := conn.DB.ExecContext(ctx,
+		`DELETE FROM download_queue WHERE state = ? AND `+filter,
+		append([]any{QueueDone}, args...)...,
+	)
+	return err
+}
@@ -0,0 +1,106 @@
+// =============================================================================
+// FILE: internal/db/rebase.go
+// PURPOSE: Path rebasing. Rewrites the leading directory of recorded media
+//          directories and queued download paths, for when the save location
+//          has moved to another mount point or machine.
+// =============================================================================
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// ---------------------------------------------------------------------------
+// Rebase
+// ---------------------------------------------------------------------------
+
+// RebaseResult counts the rows a rebase rewrites.
+type RebaseResult struct {
+	Media  int64 // medias rows whose directory is under the old prefix
+	Queued int64 // download_queue rows whose file path is under it
+}
+
+// rebaseColumns are the path columns a rebase rewrites.
+var rebaseColumns = []struct {
+	table  string
+	column string
+}{
+	{"medias", "directory"},
+	{"download_queue", "filepath"},
+}
+
+// RebasePaths replaces the directory prefix from with to in every recorded
+// media directory and queued download path. Only whole path components
+// match: "/mnt/old" rebases "/mnt/old/a" but not "/mnt/older". Relative
+// directories never match. All rows are rewritten in one transaction; a
+// model's connection to a consolidated database rewrites only its rows.
+//
+// Parameters:
+//   - ctx: Context for cancellation.
+//   - conn: Database connection.
+//   - from: The old prefix, e.g. "/mnt/old".
+//   - to: The new prefix, e.g. "/srv/archive".
+//   - dryRun: Count the matching rows without rewriting them.
+//
+// Returns:
+//   - The number of rows matched (and rewritten unless dryRun), and any
+//     error.
+func RebasePaths(ctx context.Context, conn *Conn, from, to string, dryRun bool) (RebaseResult, error) {
+	var result RebaseResult
+	if from == "" || to == "" {
+		return result, fmt.Errorf("rebase requires both an old and a new prefix")
+	}
+	from, to = filepath.Clean(from), filepath.Clean(to)
+	// Paths under a prefix keep what follows its separator; a root prefix
+	// such as "/" already ends in one.
+	under, toUnder := withSeparator(from), withSeparator(to)
+
+	// SQLite's substr counts characters, not bytes.
+	underLen := utf8.RuneCountInString(under)
+
+	filter, filterArgs := conn.modelFilter("model_id")
+	err := WithTx(ctx, conn, func(tx *sql.Tx) error {
+		for i, c := range rebaseColumns {
+			where := ` WHERE (` + c.column + ` = ? OR substr(` + c.column + `, 1, ?) = ?) AND ` + filter
+			whereArgs := append([]any{from, underLen, under}, filterArgs...)
+			var n int64
+			if dryRun {
+				err := tx.QueryRowContext(ctx,
+					`SELECT COUNT(*) FROM `+c.table+where, whereArgs...,
+				).Scan(&n)
+				if err != nil {
+					return fmt.Errorf("count %s: %w", c.table, err)
+				}
+			} else {
+				res, err := tx.ExecContext(ctx,
+					`UPDATE `+c.table+` SET `+c.column+` = CASE WHEN `+c.column+` = ? THEN ? ELSE ? || substr(`+c.column+`, ?) END`+where,
+					append([]any{from, to, toUnder, underLen + 1}, whereArgs...)...,
+				)
+				if err != nil {
+					return fmt.Errorf("rebase %s: %w", c.table, err)
+				}
+				if n, err = res.RowsAffected(); err != nil {
+					return err
+				}
+			}
+			if i == 0 {
+				result.Media = n
+			} else {
+				result.Queued = n
+			}
+		}
+		return nil
+	})
+	return result, err
+}
+
+// withSeparator returns the cleaned path p ending in exactly one separator.
+func withSeparator(p string) string {
+	return strings.TrimSuffix(p, string(filepath.Separator)) + string(filepath.Separator)
+}
@@ -0,0 +1,121 @@
+// =============================================================================
+// FILE: internal/db/rebase_test.go
+// PURPOSE: Tests for path rebasing: only whole leading components match,
+//          and root or trailing-slash prefixes join to the new prefix
+//          with exactly one separator.
+// =============================================================================
+
+package db_test
+
+import (
+	"context"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"gofscraper/internal/db"
+)
+
+func TestRebasePaths(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("prefixes are POSIX paths")
+	}
+	dirs := []string{"/mnt/x", "/mnt/x/sub", "/mnt/xy", "/other", "relative/mnt/x", "/"}
+
+	tests := []struct {
+		name     string
+		from, to string
+		want     []string // Directories after the rebase, in dirs order
+	}{
+		{
+			name: "directory prefix",
+			from: "/mnt/x", to: "/srv",
+			want: []string{"/srv", "/srv/sub", "/mnt/xy", "/other", "relative/mnt/x", "/"},
+		},
+		{
+			name: "trailing separators",
+			from: "/mnt/x/", to: "/srv/",
+			want: []string{"/srv", "/srv/sub", "/mnt/xy", "/other", "relative/mnt/x", "/"},
+		},
+		{
+			name: "root prefix",
+			from: "/", to: "/srv",
+			want: []string{"/srv/mnt/x", "/srv/mnt/x/sub", "/srv/mnt/xy", "/srv/other", "relative/mnt/x", "/srv"},
+		},
+		{
+			name: "to root",
+			from: "/mnt", to: "/",
+			want: []string{"/x", "/x/sub", "/xy", "/other", "relative/mnt/x", "/"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			conn, err := db.Open("rebase_test", filepath.Join(t.TempDir(), "user_data.db"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer db.Close("rebase_test")
+
+			queue := make([]db.QueueRow, 0, len(dirs))
+			for i, dir := range dirs {
+				id := int64(i + 1)
+				row := db.MediaRow{MediaID: id, PostID: id, Directory: db.NullString(dir), Filename: db.NullString("f.jpg")}
+				if err := db.UpsertMedia(ctx, conn, row); err != nil {
+					t.Fatal(err)
+				}
+				queue = append(queue, db.QueueRow{MediaID: id, PostID: id, FilePath: filepath.Join(dir, "f.jpg"), Media: "{}"})
+			}
+			if err := db.EnqueueDownloads(ctx, conn, queue); err != nil {
+				t.Fatal(err)
+			}
+
+			dry, err := db.RebasePaths(ctx, conn, tt.from, tt.to, true)
+			if err != nil {
+				t.Fatalf("dry run: %v", err)
+			}
+			result, err := db.RebasePaths(ctx, conn, tt.from, tt.to, false)
+			if err != nil {
+				t.Fatalf("RebasePaths: %v", err)
+			}
+			if dry != result {
+				t.Errorf("dry run counted %+v, rebase rewrote %+v", dry, result)
+			}
+
+			media, err := db.GetAllMedia(ctx, conn)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make(map[int64]string, len(media))
+			for _, m := range media {
+				got[m.MediaID] = m.Directory.String
+			}
+			queued, err := db.GetQueuedDownloads(ctx, conn)
+			if err != nil {
+				t.Fatal(err)
+			}
+			gotQueued := make(map[int64]string, len(queued))
+			for _, q := range queued {
+				gotQueued[q.MediaID] = q.FilePath
+			}
+
+			var changed int64
+			for i, want := range tt.want {
+				id := int64(i + 1)
+				if got[id] != want {
+					t.Errorf("directory %q rebased to %q, want %q", dirs[i], got[id], want)
+				}
+				if wantFile := filepath.Join(want, "f.jpg"); gotQueued[id] != wantFile {
+					t.Errorf("queued %q rebased to %q, want %q", filepath.Join(dirs[i], "f.jpg"), gotQueued[id], wantFile)
+				}
+				if want != dirs[i] {
+					changed++
+				}
+			}
+			if result.Media != changed || result.Queued != changed {
+				t.Errorf("result = %+v, want %d media and queued rows", result, changed)
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+// =============================================================================
+// FILE: internal/db/search.go
+// PURPOSE: Full-text search over post text. Maintains the post_search FTS5
+//          index alongside the post-shaped tables and queries it with
+//          snippet highlighting and date, area, and price filters.
+// =============================================================================
+
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// ---------------------------------------------------------------------------
+// Index
+// ---------------------------------------------------------------------------
+
+// searchAreas are the post-shaped tables indexed in post_search, with the
+// code that keeps their rowids apart: rowid = id * 8 + code, where id is
+// the row's key in its table.
+var searchAreas = []struct {
+	table string
+	code  int64
+}{
+	{"posts", 1},
+	{"messages", 2},
+	{"stories", 3},
+	{"others", 4},
+	{"products", 5},
+}
+
+// SearchAreas returns the names of the indexed tables, usable as areas in
+// a SearchQuery.
+//
+// Returns:
+//   - The table names.
+func SearchAreas() []string {
+	names := make([]string, len(searchAreas))
+	for i, a := range searchAreas {
+		names[i] = a.table
+	}
+	return names
+}
+
+// searchCode returns the rowid code of a post-shaped table.
+func searchCode(table string) (int64, bool) {
+	for _, a := range searchAreas {
+		if a.table == table {
+			return a.code, true
+		}
+	}
+	return 0, false
+}
+
+// indexPost adds or replaces the post_search entry of one model's post,
+// copying the row as stored in table.
+func indexPost(ctx context.Context, ex execer, table string, postID, modelID int64) error {
+	code, ok := searchCode(table)
+	if !ok {
+		return nil
+	}
+	_, err := ex.ExecContext(ctx,
+		`INSERT OR REPLACE INTO post_search (rowid, text, area, post_id, model_id, price, paid, created_at)
+		 SELECT id * 8 + ?, COALESCE(text, ''), ?, post_id, model_id, price, paid, created_at
+		 FROM `+table+` WHERE model_id = ? AND post_id = ?`,
+		code, table, modelID, postID,
+	)
+	if err != nil {
+		return fmt.Errorf("index %s %d: %w", table, postID, err)
+	}
+	return nil
+}
+
+// indexTableSQL returns the statement that (re)indexes every row of one
+// post-shaped table.
+func indexTableSQL(table string, code int64) string {
+	return fmt.Sprintf(
+		`INSERT OR REPLACE INTO post_search (rowid, text, area, post_id, model_id, price, paid, created_at)
+		 SELECT id * 8 + %d, COALESCE(text, ''), '%s', post_id, model_id, price, paid, created_at FROM %s`,
+		code, table, table,
+	)
+}
+
+// indexTable (re)indexes every row of one post-shaped table, for writes
+// that bypass upsertPostRow such as merges.
+func indexTable(ctx context.Context, ex execer, table string) error {
+	for _, a := range searchAreas {
+		if a.table == table {
+			if _, err := ex.ExecContext(ctx, indexTableSQL(a.table, a.code)); err != nil {
+				return fmt.Errorf("index %s: %w", table, err)
+			}
+		}
+	}
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// Query
+// ---------------------------------------------------------------------------
+
+// SearchQuery selects posts by their text and metadata.
+type SearchQuery struct {
+	Match    string   // FTS5 query, e.g. `beach`, `"red dress"`, `sun*`
+	Areas    []string // Tables to search (posts, messages, ...); empty = all
+	After    string   // Only posts created on or after this date (YYYY-MM-DD)
+	Before   string   // Only posts created before this date (YYYY-MM-DD)
+	MinPrice float64  // Minimum price (0 = no minimum)
+	MaxPrice float64  // Maximum price (0 = no maximum)
+	FreeOnly bool     // Only posts with a price of 0
+	Limit    int      // Maximum hits (0 = no limit)
+
+	// Markers placed around matched terms in Snippet.
+	HighlightStart string
+	HighlightEnd   string
+}
+
+// SearchHit is one matching post.
+type SearchHit struct {
+	Area      string // Table the post is stored in
+	PostID    int64
+	Snippet   string // Excerpt around the match, terms highlighted
+	Text      string // Full stored text
+	Price     float64
+	Paid      bool
+	CreatedAt string
+	Rank      float64 // bm25 rank; lower is a better match
+}
+
+// SearchPosts runs a full-text query against one database's post text, or
+// one model's partition of a consolidated database.
+//
+// Parameters:
+//   - ctx: Context for cancellation.
+//   - conn: Database connection.
+//   - q: The query and filters.
+//
+// Returns:
+//   - Matching posts, best match first, and any error (including FTS5
+//     syntax errors in q.Match).
+func SearchPosts(ctx context.Context, conn *Conn, q SearchQuery) ([]SearchHit, error) {
+	if strings.TrimSpace(q.Match) == "" {
+		return nil, fmt.Errorf("empty search query")
+	}
+
+	filter, filterArgs := conn.modelFilter("model_id")
+	where := []string{"post_search MATCH ?", filter}
+	args := append([]any{q.HighlightStart, q.HighlightEnd, q.Match}, filterArgs...)
+	if len(q.Areas) > 0 {
+		where = append(where, "area IN (?"+strings.Repeat(", ?", len(q.Areas)-1)+")")
+		for _, a := range q.Areas {
+			args = append(args, a)
+		}
+	}
+	if q.After != "" {
+		where = append(where, "created_at >= ?")
+		args = append(args, q.After)
+	}
+	if q.Before != "" {
+		where = append(where, "created_at < ?")
+		args = append(args, q.Before)
+	}
+	if q.FreeOnly {
+		where = append(where, "price = 0")
+	}
+	if q.MinPrice > 0 {
+		where = append(where, "price >= ?")
+		args = append(args, q.MinPrice)
+	}
+	if q.MaxPrice > 0 {
+		where = append(where, "price <= ?")
+		args = append(args, q.MaxPrice)
+	}
+
+	query := `SELECT area, post_id, snippet(post_search, 0, ?, ?, '…', 16), text,
+	                 COALESCE(price, 0), COALESCE(paid, 0), COALESCE(created_at, ''), bm25(post_search)
+	          FROM post_search WHERE ` + strings.Join(where, " AND ") + ` ORDER BY bm25(post_search)`
+	if q.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", q.Limit)
+	}
+
+	rows, err := conn.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, searchError(q.Match, err)
+	}
+	defer rows.Close()
+
+	var hits []SearchHit
+	for rows.Next() {
+		var h SearchHit
+		var paid int
+		if err := rows.Scan(&h.Area, &h.PostID, &h.Snippet, &h.Text, &h.Price, &paid, &h.CreatedAt, &h.Rank); err != nil {
+			return nil, fmt.Errorf("search: %w", err)
+		}
+		h.Paid = paid != 0
+		hits = append(hits, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, searchError(q.Match, err)
+	}
+	return hits, nil
+}
+
+// searchError wraps an error from running a search. The statement itself
+// is fixed, so SQLite's generic SQLITE_ERROR code (rather than a busy,
+// I/O, or corruption code) means the FTS5 parser rejected match.
+func searchError(match string, err error) error {
+	var se *sqlite.Error
+	if errors.As(err, &se) && se.Code()&0xff == sqlite3.SQLITE_ERROR {
+		return fmt.Errorf("invalid search query %q: %w", match, err)
+	}
+	return fmt.Errorf("search: %w", err)
+}
@@ -0,0 +1,131 @@
+// =============================================================================
+// FILE: internal/db/search_test.go
+// PURPOSE: Tests for full-text search: area, date, and price filters,
+//          highlighted snippets, ranking and limits, and FTS5 syntax errors
+//          reported as invalid queries.
+// =============================================================================
+
+package db_test
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"gofscraper/internal/db"
+)
+
+// openSearchDB opens a database holding a few posts, messages, and stories.
+func openSearchDB(t *testing.T) *db.Conn {
+	t.Helper()
+	conn, err := db.Open("search_test", filepath.Join(t.TempDir(), "user_data.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close("search_test") })
+
+	ctx := context.Background()
+	type row struct {
+		upsert func(context.Context, *db.Conn, int64, string, float64, bool, bool, string, int64) error
+		id     int64
+		text   string
+		price  float64
+		date   string
+	}
+	for _, r := range []row{
+		{db.UpsertPost, 1, "A day at the beach with friends", 0, "2024-01-10T12:00:00"},
+		{db.UpsertPost, 2, "Sunset on the beach, beach beach", 9.99, "2024-03-05T12:00:00"},
+		{db.UpsertPost, 3, "Red dress photoshoot", 4.99, "2024-02-01T12:00:00"},
+		{db.UpsertMessage, 4, "Thanks for the beach pics", 0, "2024-02-20T12:00:00"},
+		{db.UpsertStory, 5, "Sunny beach morning", 0, "2024-04-01T12:00:00"},
+	} {
+		if err := r.upsert(ctx, conn, r.id, r.text, r.price, r.price > 0, false, r.date, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return conn
+}
+
+func TestSearchPostsFilters(t *testing.T) {
+	conn := openSearchDB(t)
+
+	tests := []struct {
+		name string
+		q    db.SearchQuery
+		want []int64 // Post IDs, sorted
+	}{
+		{name: "all areas", q: db.SearchQuery{Match: "beach"}, want: []int64{1, 2, 4, 5}},
+		{name: "phrase", q: db.SearchQuery{Match: `"red dress"`}, want: []int64{3}},
+		{name: "prefix", q: db.SearchQuery{Match: "sun*"}, want: []int64{2, 5}},
+		{name: "one area", q: db.SearchQuery{Match: "beach", Areas: []string{"messages"}}, want: []int64{4}},
+		{name: "two areas", q: db.SearchQuery{Match: "beach", Areas: []string{"posts", "stories"}}, want: []int64{1, 2, 5}},
+		{name: "after", q: db.SearchQuery{Match: "beach", After: "2024-02-20"}, want: []int64{2, 4, 5}},
+		{name: "before", q: db.SearchQuery{Match: "beach", Before: "2024-02-20"}, want: []int64{1}},
+		{name: "date range", q: db.SearchQuery{Match: "beach", After: "2024-02-01", Before: "2024-04-01"}, want: []int64{2, 4}},
+		{name: "free only", q: db.SearchQuery{Match: "beach", FreeOnly: true}, want: []int64{1, 4, 5}},
+		{name: "min price", q: db.SearchQuery{Match: "beach OR dress", MinPrice: 5}, want: []int64{2}},
+		{name: "max price", q: db.SearchQuery{Match: "beach OR dress", MaxPrice: 5}, want: []int64{1, 3, 4, 5}},
+		{name: "no match", q: db.SearchQuery{Match: "mountain"}, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hits, err := db.SearchPosts(context.Background(), conn, tt.q)
+			if err != nil {
+				t.Fatalf("SearchPosts: %v", err)
+			}
+			var got []int64
+			for _, h := range hits {
+				got = append(got, h.PostID)
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("hits = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchPostsSnippetAndRank(t *testing.T) {
+	conn := openSearchDB(t)
+
+	hits, err := db.SearchPosts(context.Background(), conn, db.SearchQuery{
+		Match:          "beach",
+		Areas:          []string{"posts"},
+		Limit:          1,
+		HighlightStart: "[",
+		HighlightEnd:   "]",
+	})
+	if err != nil {
+		t.Fatalf("SearchPosts: %v", err)
+	}
+	// The post saying "beach" three times ranks first.
+	if len(hits) != 1 || hits[0].PostID != 2 {
+		t.Fatalf("hits = %+v, want only post 2", hits)
+	}
+	h := hits[0]
+	if !strings.Contains(h.Snippet, "[beach]") || strings.Contains(h.Snippet, "[Sunset]") {
+		t.Errorf("snippet = %q, want only the matched term highlighted", h.Snippet)
+	}
+	if h.Area != "posts" || h.Text != "Sunset on the beach, beach beach" || h.Price != 9.99 || !h.Paid ||
+		!strings.HasPrefix(h.CreatedAt, "2024-03-05") {
+		t.Errorf("hit = %+v, want the stored post fields", h)
+	}
+}
+
+func TestSearchPostsInvalidQuery(t *testing.T) {
+	conn := openSearchDB(t)
+
+	for _, match := range []string{"beach AND", `"unterminated`, "(beach", "  "} {
+		_, err := db.SearchPosts(context.Background(), conn, db.SearchQuery{Match: match})
+		if err == nil {
+			t.Errorf("SearchPosts(%q) succeeded, want an error", match)
+			continue
+		}
+		if strings.TrimSpace(match) != "" && !strings.Contains(err.Error(), "invalid search query") {
+			t.Errorf("SearchPosts(%q) = %v, want an invalid search query error", match, err)
+		}
+	}
+}
@@ -1,194 +1,683 @@
 // =============================================================================
 // FILE: internal/db/transition.go
-// PURPOSE: Schema migration for SQLite databases. Runs table creation and
-//          ALTER TABLE statements to bring databases up to the current schema
-//          version. Ports Python db/transition.py.
+// PURPOSE: Schema migration for SQLite databases. Keeps an ordered registry of
+//          forward-only migrations and applies pending steps, each in its own
+//          transaction, to bring databases up to the current schema version.
+//          Ports Python db/transition.py.
 // =============================================================================
 
 package db
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"os"
+	"strings"
 )
 
 // ---------------------------------------------------------------------------
-// Schema version
+// Migration registry
 // ---------------------------------------------------------------------------
 
-// currentSchemaVersion is the latest schema version.
-const currentSchemaVersion = 1
+// Migration is a single forward-only schema step. Versions are consecutive
+// from 1; a released migration is never edited, only followed by new ones.
+type Migration struct {
+	Version     int
+	Description string
+	Statements  []string
+}
+
+// migrations is the ordered registry of schema steps. Append new steps to
+// the end with the next version number.
+var migrations = []Migration{
+	{Version: 1, Description: "create tables", Statements: v1Statements},
+	{Version: 2, Description: "add download_attempts", Statements: v2Statements},
+	{Version: 3, Description: "add download_queue", Statements: v3Statements},
+	{Version: 4, Description: "add medias.quality", Statements: v4Statements},
+	{Version: 5, Description: "add probed media columns", Statements: v5Statements},
+	{Version: 6, Description: "add post_search full-text index", Statements: v6Statements},
+	{Version: 7, Description: "partition tables by model_id", Statements: v7Statements},
+	{Version: 8, Description: "make post and media keys unique per model", Statements: v8Statements},
+}
+
+// currentSchemaVersion is the latest schema version this binary knows.
+var currentSchemaVersion = migrations[len(migrations)-1].Version
+
+// ErrSchemaTooNew is returned when a database was written by a newer binary.
+var ErrSchemaTooNew = errors.New("database schema is newer than this version of gofscraper")
+
+// CurrentSchemaVersion returns the latest schema version this binary knows.
+func CurrentSchemaVersion() int {
+	return currentSchemaVersion
+}
 
 // ---------------------------------------------------------------------------
 // Migration
 // ---------------------------------------------------------------------------
 
-// migrate runs all necessary schema migrations on the database.
+// migrate applies all pending schema migrations to the database. Existing
+// databases are backed up before the first pending step, and each step runs
+// in its own transaction together with its version bump.
 //
 // Parameters:
 //   - db: The database connection.
+//   - dbPath: Path to the database file, used for the pre-migration backup.
 //
 // Returns:
-//   - Error if any migration step fails.
-func migrate(db *sql.DB) error {
-	// Create schema_flags table if it doesn't exist (used to track version).
-	_, err := db.Exec(`
-		CREATE TABLE IF NOT EXISTS schema_flags (
-			flag_name  TEXT PRIMARY KEY,
-			flag_value TEXT NOT NULL
-		)
-	`)
-	if err != nil {
-		return fmt.Errorf("failed to create schema_flags table: %w", err)
+//   - ErrSchemaTooNew if the database is ahead of this binary, or an error
+//     if the backup or any migration step fails.
+func migrate(db *sql.DB, dbPath string) error {
+	if err := ensureSchemaFlags(db); err != nil {
+		return err
 	}
 
 	version := getSchemaVersion(db)
 	slog.Debug("database schema version", "current", version, "target", currentSchemaVersion)
 
-	if version < 1 {
-		if err := migrateV1(db); err != nil {
+	pending, err := pendingMigrations(version)
+	if err != nil {
+		return err
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	populated, err := hasUserTables(db)
+	if err != nil {
+		return err
+	}
+	if populated {
+		// Flush the WAL so the copy contains every committed write.
+		_, _ = db.Exec(`PRAGMA wal_checkpoint(TRUNCATE)`)
+		backupPath, err := Backup(dbPath)
+		if err != nil {
+			return fmt.Errorf("pre-migration backup failed: %w", err)
+		}
+		slog.Info("backed up database before migration",
+			"path", dbPath, "backup", backupPath,
+			"from", version, "to", currentSchemaVersion,
+		)
+	}
+
+	for _, m := range pending {
+		if err := applyMigration(db, m); err != nil {
 			return err
 		}
-		setSchemaVersion(db, 1)
+		slog.Debug("applied schema migration", "version", m.Version, "description", m.Description)
 	}
 
 	return nil
 }
 
+// applyMigration runs one migration and records its version in a single
+// transaction, so a failed step leaves the database at the previous version.
+func applyMigration(db *sql.DB, m Migration) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("migration v%d: begin: %w", m.Version, err)
+	}
+	defer tx.Rollback()
+
+	for _, stmt := range m.Statements {
+		if _, err := tx.Exec(stmt); err != nil {
+			return fmt.Errorf("migration v%d failed: %w\nStatement: %s", m.Version, err, stmt)
+		}
+	}
+	if _, err := tx.Exec(
+		`INSERT OR REPLACE INTO schema_flags (flag_name, flag_value) VALUES ('schema_version', ?)`,
+		fmt.Sprintf("%d", m.Version),
+	); err != nil {
+		return fmt.Errorf("migration v%d: set version: %w", m.Version, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("migration v%d: commit: %w", m.Version, err)
+	}
+	return nil
+}
+
+// pendingMigrations returns the registry steps after version.
+func pendingMigrations(version int) ([]Migration, error) {
+	if version > currentSchemaVersion {
+		return nil, fmt.Errorf("%w (database v%d, supported v%d)", ErrSchemaTooNew, version, currentSchemaVersion)
+	}
+	var pending []Migration
+	for _, m := range migrations {
+		if m.Version > version {
+			pending = append(pending, m)
+		}
+	}
+	return pending, nil
+}
+
 // ---------------------------------------------------------------------------
-// V1 migration: Create all tables
-// ---------------------------------------------------------------------------
-
-func migrateV1(db *sql.DB) error {
-	statements := []string{
-		// Posts table.
-		`CREATE TABLE IF NOT EXISTS posts (
-			id         INTEGER PRIMARY KEY,
-			post_id    INTEGER NOT NULL,
-			text       TEXT,
-			price      REAL DEFAULT 0,
-			paid       INTEGER DEFAULT 0,
-			archived   INTEGER DEFAULT 0,
-			created_at TEXT,
-			model_id   INTEGER,
-			UNIQUE(post_id)
-		)`,
-
-		// Messages table.
-		`CREATE TABLE IF NOT EXISTS messages (
-			id         INTEGER PRIMARY KEY,
-			post_id    INTEGER NOT NULL,
-			text       TEXT,
-			price      REAL DEFAULT 0,
-			paid       INTEGER DEFAULT 0,
-			archived   INTEGER DEFAULT 0,
-			created_at TEXT,
-			model_id   INTEGER,
-			UNIQUE(post_id)
-		)`,
-
-		// Media table.
-		`CREATE TABLE IF NOT EXISTS medias (
-			id            INTEGER PRIMARY KEY,
-			media_id      INTEGER NOT NULL,
-			post_id       INTEGER NOT NULL,
-			link          TEXT,
-			directory     TEXT,
-			filename      TEXT,
-			size          INTEGER DEFAULT 0,
-			api_type      TEXT,
-			media_type    TEXT,
-			preview       INTEGER DEFAULT 0,
-			linked        TEXT,
-			downloaded    INTEGER DEFAULT 0,
-			created_at    TEXT,
-			posted_at     TEXT,
-			hash          TEXT,
-			model_id      INTEGER,
-			UNIQUE(media_id)
-		)`,
-
-		// Stories table.
-		`CREATE TABLE IF NOT EXISTS stories (
-			id         INTEGER PRIMARY KEY,
-			post_id    INTEGER NOT NULL,
-			text       TEXT,
-			price      REAL DEFAULT 0,
-			paid       INTEGER DEFAULT 0,
-			archived   INTEGER DEFAULT 0,
-			created_at TEXT,
-			model_id   INTEGER,
-			UNIQUE(post_id)
-		)`,
-
-		// Labels table.
-		`CREATE TABLE IF NOT EXISTS labels (
-			id         INTEGER PRIMARY KEY,
-			label_id   INTEGER NOT NULL,
-			name       TEXT,
-			type       TEXT,
-			post_id    INTEGER,
-			model_id   INTEGER,
-			UNIQUE(label_id, post_id)
-		)`,
-
-		// Others table.
-		`CREATE TABLE IF NOT EXISTS others (
-			id         INTEGER PRIMARY KEY,
-			post_id    INTEGER NOT NULL,
-			text       TEXT,
-			price      REAL DEFAULT 0,
-			paid       INTEGER DEFAULT 0,
-			archived   INTEGER DEFAULT 0,
-			created_at TEXT,
-			model_id   INTEGER,
-			UNIQUE(post_id)
-		)`,
-
-		// Products table.
-		`CREATE TABLE IF NOT EXISTS products (
-			id         INTEGER PRIMARY KEY,
-			post_id    INTEGER NOT NULL,
-			text       TEXT,
-			price      REAL DEFAULT 0,
-			paid       INTEGER DEFAULT 0,
-			archived   INTEGER DEFAULT 0,
-			created_at TEXT,
-			model_id   INTEGER,
-			UNIQUE(post_id)
-		)`,
-
-		// Profiles table.
-		`CREATE TABLE IF NOT EXISTS profiles (
-			id         INTEGER PRIMARY KEY,
-			user_id    INTEGER NOT NULL,
-			username   TEXT,
-			UNIQUE(user_id)
-		)`,
-
-		// Models table.
-		`CREATE TABLE IF NOT EXISTS models (
-			id         INTEGER PRIMARY KEY,
-			model_id   INTEGER NOT NULL,
-			username   TEXT,
-			UNIQUE(model_id)
-		)`,
-	}
-
-	for _, stmt := range statements {
-		if _, err := db.Exec(stmt); err != nil {
-			return fmt.Errorf("migration v1 failed: %w\nStatement: %s", err, stmt)
+// Dry run
+// ---------------------------------------------------------------------------
+
+// PlanMigrations reports the migrations that opening dbPath would apply,
+// without modifying the database. A missing file plans every migration.
+//
+// Parameters:
+//   - dbPath: Path to the database file.
+//
+// Returns:
+//   - The database's current version, the pending migrations in order,
+//     and ErrSchemaTooNew or any open error.
+func PlanMigrations(dbPath string) (int, []Migration, error) {
+	version := 0
+	if _, err := os.Stat(dbPath); err == nil {
+		sqlDB, err := sql.Open("sqlite", fmt.Sprintf("file:%s?mode=ro", dbPath))
+		if err != nil {
+			return 0, nil, fmt.Errorf("failed to open database %s: %w", dbPath, err)
 		}
+		defer sqlDB.Close()
+		version = getSchemaVersion(sqlDB)
 	}
 
+	pending, err := pendingMigrations(version)
+	return version, pending, err
+}
+
+// WriteMigrationSQL writes the SQL of the given migrations to w, one
+// commented block per step.
+//
+// Parameters:
+//   - w: Destination writer.
+//   - pending: Migrations to print, as returned by PlanMigrations.
+//
+// Returns:
+//   - Any write error.
+func WriteMigrationSQL(w io.Writer, pending []Migration) error {
+	for _, m := range pending {
+		if _, err := fmt.Fprintf(w, "-- v%d: %s\nBEGIN;\n", m.Version, m.Description); err != nil {
+			return err
+		}
+		for _, stmt := range m.Statements {
+			if _, err := fmt.Fprintf(w, "%s;\n", strings.TrimSpace(stmt)); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w,
+			"INSERT OR REPLACE INTO schema_flags (flag_name, flag_value) VALUES ('schema_version', '%d');\nCOMMIT;\n\n",
+			m.Version,
+		); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// ---------------------------------------------------------------------------
+// V1: Create all tables
+// ---------------------------------------------------------------------------
+
+var v1Statements = []string{
+	// Posts table.
+	`CREATE TABLE IF NOT EXISTS posts (
+		id         INTEGER PRIMARY KEY,
+		post_id    INTEGER NOT NULL,
+		text       TEXT,
+		price      REAL DEFAULT 0,
+		paid       INTEGER DEFAULT 0,
+		archived   INTEGER DEFAULT 0,
+		created_at TEXT,
+		model_id   INTEGER,
+		UNIQUE(post_id)
+	)`,
+
+	// Messages table.
+	`CREATE TABLE IF NOT EXISTS messages (
+		id         INTEGER PRIMARY KEY,
+		post_id    INTEGER NOT NULL,
+		text       TEXT,
+		price      REAL DEFAULT 0,
+		paid       INTEGER DEFAULT 0,
+		archived   INTEGER DEFAULT 0,
+		created_at TEXT,
+		model_id   INTEGER,
+		UNIQUE(post_id)
+	)`,
+
+	// Media table.
+	`CREATE TABLE IF NOT EXISTS medias (
+		id            INTEGER PRIMARY KEY,
+		media_id      INTEGER NOT NULL,
+		post_id       INTEGER NOT NULL,
+		link          TEXT,
+		directory     TEXT,
+		filename      TEXT,
+		size          INTEGER DEFAULT 0,
+		api_type      TEXT,
+		media_type    TEXT,
+		preview       INTEGER DEFAULT 0,
+		linked        TEXT,
+		downloaded    INTEGER DEFAULT 0,
+		created_at    TEXT,
+		posted_at     TEXT,
+		hash          TEXT,
+		model_id      INTEGER,
+		UNIQUE(media_id)
+	)`,
+
+	// Stories table.
+	`CREATE TABLE IF NOT EXISTS stories (
+		id         INTEGER PRIMARY KEY,
+		post_id    INTEGER NOT NULL,
+		text       TEXT,
+		price      REAL DEFAULT 0,
+		paid       INTEGER DEFAULT 0,
+		archived   INTEGER DEFAULT 0,
+		created_at TEXT,
+		model_id   INTEGER,
+		UNIQUE(post_id)
+	)`,
+
+	// Labels table.
+	`CREATE TABLE IF NOT EXISTS labels (
+		id         INTEGER PRIMARY KEY,
+		label_id   INTEGER NOT NULL,
+		name       TEXT,
+		type       TEXT,
+		post_id    INTEGER,
+		model_id   INTEGER,
+		UNIQUE(label_id, post_id)
+	)`,
+
+	// Others table.
+	`CREATE TABLE IF NOT EXISTS others (
+		id         INTEGER PRIMARY KEY,
+		post_id    INTEGER NOT NULL,
+		text       TEXT,
+		price      REAL DEFAULT 0,
+		paid       INTEGER DEFAULT 0,
+		archived   INTEGER DEFAULT 0,
+		created_at TEXT,
+		model_id   INTEGER,
+		UNIQUE(post_id)
+	)`,
+
+	// Products table.
+	`CREATE TABLE IF NOT EXISTS products (
+		id         INTEGER PRIMARY KEY,
+		post_id    INTEGER NOT NULL,
+		text       TEXT,
+		price      REAL DEFAULT 0,
+		paid       INTEGER DEFAULT 0,
+		archived   INTEGER DEFAULT 0,
+		created_at TEXT,
+		model_id   INTEGER,
+		UNIQUE(post_id)
+	)`,
+
+	// Profiles table.
+	`CREATE TABLE IF NOT EXISTS profiles (
+		id         INTEGER PRIMARY KEY,
+		user_id    INTEGER NOT NULL,
+		username   TEXT,
+		UNIQUE(user_id)
+	)`,
+
+	// Models table.
+	`CREATE TABLE IF NOT EXISTS models (
+		id         INTEGER PRIMARY KEY,
+		model_id   INTEGER NOT NULL,
+		username   TEXT,
+		UNIQUE(model_id)
+	)`,
+}
+
+// ---------------------------------------------------------------------------
+// V2: Download attempt history
+// ---------------------------------------------------------------------------
+
+var v2Statements = []string{
+	`CREATE TABLE IF NOT EXISTS download_attempts (
+		id            INTEGER PRIMARY KEY,
+		media_id      INTEGER NOT NULL,
+		post_id       INTEGER,
+		attempted_at  TEXT NOT NULL,
+		http_status   INTEGER DEFAULT 0,
+		error_class   TEXT,
+		error         TEXT,
+		bytes_written INTEGER DEFAULT 0,
+		duration_ms   INTEGER DEFAULT 0,
+		succeeded     INTEGER DEFAULT 0,
+		model_id      INTEGER
+	)`,
+
+	`CREATE INDEX IF NOT EXISTS idx_download_attempts_media
+		ON download_attempts (media_id, id)`,
+}
+
+// ---------------------------------------------------------------------------
+// V3: Persistent download queue
+// ---------------------------------------------------------------------------
+
+var v3Statements = []string{
+	`CREATE TABLE IF NOT EXISTS download_queue (
+		media_id   INTEGER PRIMARY KEY,
+		post_id    INTEGER,
+		model_id   INTEGER,
+		state      TEXT NOT NULL,
+		filepath   TEXT,
+		media      TEXT NOT NULL,
+		queued_at  TEXT NOT NULL,
+		updated_at TEXT NOT NULL
+	)`,
+
+	`CREATE INDEX IF NOT EXISTS idx_download_queue_state
+		ON download_queue (state, queued_at)`,
+}
+
+// ---------------------------------------------------------------------------
+// V4: Record the downloaded rendition
+// ---------------------------------------------------------------------------
+
+var v4Statements = []string{
+	`ALTER TABLE medias ADD COLUMN quality TEXT`,
+}
+
+// ---------------------------------------------------------------------------
+// V5: Probed media properties
+// ---------------------------------------------------------------------------
+
+var v5Statements = []string{
+	`ALTER TABLE medias ADD COLUMN duration REAL`,
+	`ALTER TABLE medias ADD COLUMN width INTEGER`,
+	`ALTER TABLE medias ADD COLUMN height INTEGER`,
+	`ALTER TABLE medias ADD COLUMN codec TEXT`,
+	`ALTER TABLE medias ADD COLUMN bitrate INTEGER`,
+}
+
+// ---------------------------------------------------------------------------
+// V6: Full-text index over post text
+// ---------------------------------------------------------------------------
+
+// v6Statements create the post_search FTS5 table and index existing posts.
+var v6Statements = []string{
+	`CREATE VIRTUAL TABLE IF NOT EXISTS post_search USING fts5(
+		text,
+		area UNINDEXED,
+		post_id UNINDEXED,
+		price UNINDEXED,
+		paid UNINDEXED,
+		created_at UNINDEXED,
+		tokenize = 'unicode61 remove_diacritics 2'
+	)`,
+	`INSERT OR REPLACE INTO post_search (rowid, text, area, post_id, price, paid, created_at)
+	 SELECT post_id * 8 + 1, COALESCE(text, ''), 'posts', post_id, price, paid, created_at FROM posts`,
+	`INSERT OR REPLACE INTO post_search (rowid, text, area, post_id, price, paid, created_at)
+	 SELECT post_id * 8 + 2, COALESCE(text, ''), 'messages', post_id, price, paid, created_at FROM messages`,
+	`INSERT OR REPLACE INTO post_search (rowid, text, area, post_id, price, paid, created_at)
+	 SELECT post_id * 8 + 3, COALESCE(text, ''), 'stories', post_id, price, paid, created_at FROM stories`,
+	`INSERT OR REPLACE INTO post_search (rowid, text, area, post_id, price, paid, created_at)
+	 SELECT post_id * 8 + 4, COALESCE(text, ''), 'others', post_id, price, paid, created_at FROM others`,
+	`INSERT OR REPLACE INTO post_search (rowid, text, area, post_id, price, paid, created_at)
+	 SELECT post_id * 8 + 5, COALESCE(text, ''), 'products', post_id, price, paid, created_at FROM products`,
+}
+
+// ---------------------------------------------------------------------------
+// V7: Partition by model_id
+// ---------------------------------------------------------------------------
+
+// v7Statements index model_id on every model table, so one model's rows
+// of a consolidated database are found without a scan, and rebuild
+// post_search with a model_id column.
+var v7Statements = []string{
+	`CREATE INDEX IF NOT EXISTS idx_posts_model ON posts (model_id)`,
+	`CREATE INDEX IF NOT EXISTS idx_messages_model ON messages (model_id)`,
+	`CREATE INDEX IF NOT EXISTS idx_stories_model ON stories (model_id)`,
+	`CREATE INDEX IF NOT EXISTS idx_others_model ON others (model_id)`,
+	`CREATE INDEX IF NOT EXISTS idx_products_model ON products (model_id)`,
+	`CREATE INDEX IF NOT EXISTS idx_labels_model ON labels (model_id)`,
+	`CREATE INDEX IF NOT EXISTS idx_medias_model ON medias (model_id)`,
+	`CREATE INDEX IF NOT EXISTS idx_download_attempts_model ON download_attempts (model_id)`,
+	`CREATE INDEX IF NOT EXISTS idx_download_queue_model ON download_queue (model_id)`,
+	`DROP TABLE IF EXISTS post_search`,
+	`CREATE VIRTUAL TABLE IF NOT EXISTS post_search USING fts5(
+		text,
+		area UNINDEXED,
+		post_id UNINDEXED,
+		model_id UNINDEXED,
+		price UNINDEXED,
+		paid UNINDEXED,
+		created_at UNINDEXED,
+		tokenize = 'unicode61 remove_diacritics 2'
+	)`,
+	`INSERT OR REPLACE INTO post_search (rowid, text, area, post_id, model_id, price, paid, created_at)
+	 SELECT post_id * 8 + 1, COALESCE(text, ''), 'posts', post_id, model_id, price, paid, created_at FROM posts`,
+	`INSERT OR REPLACE INTO post_search (rowid, text, area, post_id, model_id, price, paid, created_at)
+	 SELECT post_id * 8 + 2, COALESCE(text, ''), 'messages', post_id, model_id, price, paid, created_at FROM messages`,
+	`INSERT OR REPLACE INTO post_search (rowid, text, area, post_id, model_id, price, paid, created_at)
+	 SELECT post_id * 8 + 3, COALESCE(text, ''), 'stories', post_id, model_id, price, paid, created_at FROM stories`,
+	`INSERT OR REPLACE INTO post_search (rowid, text, area, post_id, model_id, price, paid, created_at)
+	 SELECT post_id * 8 + 4, COALESCE(text, ''), 'others', post_id, model_id, price, paid, created_at FROM others`,
+	`INSERT OR REPLACE INTO post_search (rowid, text, area, post_id, model_id, price, paid, created_at)
+	 SELECT post_id * 8 + 5, COALESCE(text, ''), 'products', post_id, model_id, price, paid, created_at FROM products`,
+}
+
+// ---------------------------------------------------------------------------
+// V8: Per-model unique keys
+// ---------------------------------------------------------------------------
+
+// v8Statements rebuild the model tables so post, media, label, and queue
+// keys are unique within a model rather than across the database, letting a
+// consolidated database hold two models' rows with the same ID. SQLite
+// cannot alter a constraint, so each table is copied into a new one with
+// its row ids kept. A missing model_id becomes the database's only model,
+// or 0, so the new keys never hold a NULL. The keys lead with model_id, so
+// they replace v7's model_id indexes on the rebuilt tables. post_search is
+// refilled keyed by each row's id, as post IDs alone may now repeat.
+var v8Statements = []string{
+	`CREATE TABLE posts_v8 (
+		id         INTEGER PRIMARY KEY,
+		post_id    INTEGER NOT NULL,
+		text       TEXT,
+		price      REAL DEFAULT 0,
+		paid       INTEGER DEFAULT 0,
+		archived   INTEGER DEFAULT 0,
+		created_at TEXT,
+		model_id   INTEGER NOT NULL DEFAULT 0,
+		UNIQUE(model_id, post_id)
+	)`,
+	`INSERT INTO posts_v8 (id, post_id, text, price, paid, archived, created_at, model_id)
+	 SELECT id, post_id, text, price, paid, archived, created_at,
+	        COALESCE(NULLIF(model_id, 0), (SELECT model_id FROM models WHERE (SELECT COUNT(*) FROM models) = 1), 0)
+	 FROM posts`,
+	`DROP TABLE posts`,
+	`ALTER TABLE posts_v8 RENAME TO posts`,
+
+	`CREATE TABLE messages_v8 (
+		id         INTEGER PRIMARY KEY,
+		post_id    INTEGER NOT NULL,
+		text       TEXT,
+		price      REAL DEFAULT 0,
+		paid       INTEGER DEFAULT 0,
+		archived   INTEGER DEFAULT 0,
+		created_at TEXT,
+		model_id   INTEGER NOT NULL DEFAULT 0,
+		UNIQUE(model_id, post_id)
+	)`,
+	`INSERT INTO messages_v8 (id, post_id, text, price, paid, archived, created_at, model_id)
+	 SELECT id, post_id, text, price, paid, archived, created_at,
+	        COALESCE(NULLIF(model_id, 0), (SELECT model_id FROM models WHERE (SELECT COUNT(*) FROM models) = 1), 0)
+	 FROM messages`,
+	`DROP TABLE messages`,
+	`ALTER TABLE messages_v8 RENAME TO messages`,
+
+	`CREATE TABLE stories_v8 (
+		id         INTEGER PRIMARY KEY,
+		post_id    INTEGER NOT NULL,
+		text       TEXT,
+		price      REAL DEFAULT 0,
+		paid       INTEGER DEFAULT 0,
+		archived   INTEGER DEFAULT 0,
+		created_at TEXT,
+		model_id   INTEGER NOT NULL DEFAULT 0,
+		UNIQUE(model_id, post_id)
+	)`,
+	`INSERT INTO stories_v8 (id, post_id, text, price, paid, archived, created_at, model_id)
+	 SELECT id, post_id, text, price, paid, archived, created_at,
+	        COALESCE(NULLIF(model_id, 0), (SELECT model_id FROM models WHERE (SELECT COUNT(*) FROM models) = 1), 0)
+	 FROM stories`,
+	`DROP TABLE stories`,
+	`ALTER TABLE stories_v8 RENAME TO stories`,
+
+	`CREATE TABLE others_v8 (
+		id         INTEGER PRIMARY KEY,
+		post_id    INTEGER NOT NULL,
+		text       TEXT,
+		price      REAL DEFAULT 0,
+		paid       INTEGER DEFAULT 0,
+		archived   INTEGER DEFAULT 0,
+		created_at TEXT,
+		model_id   INTEGER NOT NULL DEFAULT 0,
+		UNIQUE(model_id, post_id)
+	)`,
+	`INSERT INTO others_v8 (id, post_id, text, price, paid, archived, created_at, model_id)
+	 SELECT id, post_id, text, price, paid, archived, created_at,
+	        COALESCE(NULLIF(model_id, 0), (SELECT model_id FROM models WHERE (SELECT COUNT(*) FROM models) = 1), 0)
+	 FROM others`,
+	`DROP TABLE others`,
+	`ALTER TABLE others_v8 RENAME TO others`,
+
+	`CREATE TABLE products_v8 (
+		id         INTEGER PRIMARY KEY,
+		post_id    INTEGER NOT NULL,
+		text       TEXT,
+		price      REAL DEFAULT 0,
+		paid       INTEGER DEFAULT 0,
+		archived   INTEGER DEFAULT 0,
+		created_at TEXT,
+		model_id   INTEGER NOT NULL DEFAULT 0,
+		UNIQUE(model_id, post_id)
+	)`,
+	`INSERT INTO products_v8 (id, post_id, text, price, paid, archived, created_at, model_id)
+	 SELECT id, post_id, text, price, paid, archived, created_at,
+	        COALESCE(NULLIF(model_id, 0), (SELECT model_id FROM models WHERE (SELECT COUNT(*) FROM models) = 1), 0)
+	 FROM products`,
+	`DROP TABLE products`,
+	`ALTER TABLE products_v8 RENAME TO products`,
+
+	`CREATE TABLE medias_v8 (
+		id         INTEGER PRIMARY KEY,
+		media_id   INTEGER NOT NULL,
+		post_id    INTEGER NOT NULL,
+		link       TEXT,
+		directory  TEXT,
+		filename   TEXT,
+		size       INTEGER DEFAULT 0,
+		api_type   TEXT,
+		media_type TEXT,
+		preview    INTEGER DEFAULT 0,
+		linked     TEXT,
+		downloaded INTEGER DEFAULT 0,
+		created_at TEXT,
+		posted_at  TEXT,
+		hash       TEXT,
+		model_id   INTEGER NOT NULL DEFAULT 0,
+		quality    TEXT,
+		duration   REAL,
+		width      INTEGER,
+		height     INTEGER,
+		codec      TEXT,
+		bitrate    INTEGER,
+		UNIQUE(model_id, media_id)
+	)`,
+	`INSERT INTO medias_v8 (id, media_id, post_id, link, directory, filename, size, api_type, media_type, preview, linked,
+	                       downloaded, created_at, posted_at, hash, model_id, quality, duration, width, height, codec, bitrate)
+	 SELECT id, media_id, post_id, link, directory, filename, size, api_type, media_type, preview, linked,
+	        downloaded, created_at, posted_at, hash,
+	        COALESCE(NULLIF(model_id, 0), (SELECT model_id FROM models WHERE (SELECT COUNT(*) FROM models) = 1), 0),
+	        quality, duration, width, height, codec, bitrate
+	 FROM medias`,
+	`DROP TABLE medias`,
+	`ALTER TABLE medias_v8 RENAME TO medias`,
+
+	`CREATE TABLE labels_v8 (
+		id         INTEGER PRIMARY KEY,
+		label_id   INTEGER NOT NULL,
+		name       TEXT,
+		type       TEXT,
+		post_id    INTEGER,
+		model_id   INTEGER NOT NULL DEFAULT 0,
+		UNIQUE(model_id, label_id, post_id)
+	)`,
+	`INSERT INTO labels_v8 (id, label_id, name, type, post_id, model_id)
+	 SELECT id, label_id, name, type, post_id,
+	        COALESCE(NULLIF(model_id, 0), (SELECT model_id FROM models WHERE (SELECT COUNT(*) FROM models) = 1), 0)
+	 FROM labels`,
+	`DROP TABLE labels`,
+	`ALTER TABLE labels_v8 RENAME TO labels`,
+
+	`CREATE TABLE download_queue_v8 (
+		media_id   INTEGER NOT NULL,
+		post_id    INTEGER,
+		model_id   INTEGER NOT NULL DEFAULT 0,
+		state      TEXT NOT NULL,
+		filepath   TEXT,
+		media      TEXT NOT NULL,
+		queued_at  TEXT NOT NULL,
+		updated_at TEXT NOT NULL,
+		PRIMARY KEY (model_id, media_id)
+	)`,
+	`INSERT INTO download_queue_v8 (media_id, post_id, model_id, state, filepath, media, queued_at, updated_at)
+	 SELECT media_id, post_id,
+	        COALESCE(NULLIF(model_id, 0), (SELECT model_id FROM models WHERE (SELECT COUNT(*) FROM models) = 1), 0),
+	        state, filepath, media, queued_at, updated_at
+	 FROM download_queue`,
+	`DROP TABLE download_queue`,
+	`ALTER TABLE download_queue_v8 RENAME TO download_queue`,
+	`CREATE INDEX IF NOT EXISTS idx_download_queue_state
+		ON download_queue (state, queued_at)`,
+
+	`DELETE FROM post_search`,
+	`INSERT INTO post_search (rowid, text, area, post_id, model_id, price, paid, created_at)
+	 SELECT id * 8 + 1, COALESCE(text, ''), 'posts', post_id, model_id, price, paid, created_at FROM posts`,
+	`INSERT INTO post_search (rowid, text, area, post_id, model_id, price, paid, created_at)
+	 SELECT id * 8 + 2, COALESCE(text, ''), 'messages', post_id, model_id, price, paid, created_at FROM messages`,
+	`INSERT INTO post_search (rowid, text, area, post_id, model_id, price, paid, created_at)
+	 SELECT id * 8 + 3, COALESCE(text, ''), 'stories', post_id, model_id, price, paid, created_at FROM stories`,
+	`INSERT INTO post_search (rowid, text, area, post_id, model_id, price, paid, created_at)
+	 SELECT id * 8 + 4, COALESCE(text, ''), 'others', post_id, model_id, price, paid, created_at FROM others`,
+	`INSERT INTO post_search (rowid, text, area, post_id, model_id, price, paid, created_at)
+	 SELECT id * 8 + 5, COALESCE(text, ''), 'products', post_id, model_id, price, paid, created_at FROM products`,
+}
+
 // ---------------------------------------------------------------------------
 // Schema version helpers
 // ---------------------------------------------------------------------------
 
+// ensureSchemaFlags creates the schema_flags table used to track the version.
+func ensureSchemaFlags(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_flags (
+			flag_name  TEXT PRIMARY KEY,
+			flag_value TEXT NOT NULL
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_flags table: %w", err)
+	}
+	return nil
+}
+
+// hasUserTables reports whether the database holds any table besides
+// schema_flags, i.e. whether it predates this open.
+func hasUserTables(db *sql.DB) (bool, error) {
+	var n int
+	err := db.QueryRow(
+		`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name != 'schema_flags'`,
+	).Scan(&n)
+	if err != nil {
+		return false, fmt.Errorf("failed to inspect schema: %w", err)
+	}
+	return n > 0, nil
+}
+
 func getSchemaVersion(db *sql.DB) int {
 	var value string
 	err := db.QueryRow(
@@ -201,10 +690,3 @@ func getSchemaVersion(db *sql.DB) int {
 	fmt.Sscanf(value, "%d", &v)
 	return v
 }
-
-func setSchemaVersion(db *sql.DB, version int) {
-	_, _ = db.Exec(
-		`INSERT OR REPLACE INTO schema_flags (flag_name, flag_value) VALUES ('schema_version', ?)`,
-		fmt.Sprintf("%d", version),
-	)
-}
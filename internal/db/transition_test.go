@@ -0,0 +1,281 @@
+// =============================================================================
+// FILE: internal/db/transition_test.go
+// PURPOSE: Upgrade tests for the schema migration registry. Opens a checked-in
+//          database of every historical schema version (testdata/schema_vN.db)
+//          and checks it reaches the current version with its rows intact.
+// =============================================================================
+
+package db_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gofscraper/internal/db"
+)
+
+// fixtureVersions are the schema versions with a checked-in fixture. v0 is
+// an empty database; v1 through v7 hold the same sample rows written at
+// that version.
+var fixtureVersions = []int{0, 1, 2, 3, 4, 5, 6, 7}
+
+func TestMigrateFixtures(t *testing.T) {
+	if got := db.CurrentSchemaVersion(); got != 8 {
+		t.Fatalf("CurrentSchemaVersion() = %d; add a schema_v%d.db fixture and a case for it", got, got-1)
+	}
+
+	for _, from := range fixtureVersions {
+		t.Run(fmt.Sprintf("v%d", from), func(t *testing.T) {
+			ctx := context.Background()
+			path := copyFixture(t, fmt.Sprintf("schema_v%d.db", from))
+
+			version, pending, err := db.PlanMigrations(path)
+			if err != nil {
+				t.Fatalf("PlanMigrations: %v", err)
+			}
+			if version != from {
+				t.Fatalf("fixture version = %d, want %d", version, from)
+			}
+			if want := db.CurrentSchemaVersion() - from; len(pending) != want {
+				t.Fatalf("pending migrations = %d, want %d", len(pending), want)
+			}
+
+			username := fmt.Sprintf("fixture_v%d", from)
+			conn, err := db.Open(username, path)
+			if err != nil {
+				t.Fatalf("Open: %v", err)
+			}
+			t.Cleanup(func() { db.Close(username) })
+
+			if version, pending, err := db.PlanMigrations(path); err != nil || len(pending) != 0 {
+				t.Fatalf("after upgrade: version %d, %d pending, err %v", version, len(pending), err)
+			}
+
+			backups, err := db.ListBackups(path)
+			if err != nil {
+				t.Fatalf("ListBackups: %v", err)
+			}
+			if from == 0 {
+				if len(backups) != 0 {
+					t.Errorf("empty database was backed up: %v", backups)
+				}
+				return
+			}
+			if len(backups) != 1 {
+				t.Errorf("backups = %v, want one pre-migration backup", backups)
+			}
+
+			checkSampleRows(ctx, t, conn, from)
+		})
+	}
+}
+
+func TestMigrateFixtureReopen(t *testing.T) {
+	path := copyFixture(t, "schema_v1.db")
+
+	if _, err := db.Open("fixture_reopen", path); err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if err := db.Close("fixture_reopen"); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if _, err := db.Open("fixture_reopen", path); err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	t.Cleanup(func() { db.Close("fixture_reopen") })
+
+	// An up-to-date database is not backed up again.
+	backups, err := db.ListBackups(path)
+	if err != nil {
+		t.Fatalf("ListBackups: %v", err)
+	}
+	if len(backups) != 1 {
+		t.Errorf("backups = %v, want only the first pre-migration backup", backups)
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	path := copyFixture(t, "schema_v1.db")
+	if err := db.Migrate(path); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	if version, pending, err := db.PlanMigrations(path); err != nil || len(pending) != 0 {
+		t.Errorf("after Migrate: version %d, %d pending, err %v", version, len(pending), err)
+	}
+}
+
+func TestMigrateV8FillsModelID(t *testing.T) {
+	ctx := context.Background()
+	path := copyFixture(t, "schema_v7.db")
+
+	// Rows written before model_id was always set carry none; the v8 keys
+	// give them the database's only model.
+	raw, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, stmt := range []string{
+		`UPDATE posts SET model_id = NULL WHERE post_id = 1002`,
+		`UPDATE medias SET model_id = 0 WHERE media_id = 5001`,
+	} {
+		if _, err := raw.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	raw.Close()
+
+	conn, err := db.Open("fixture_v8_model_id", path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { db.Close("fixture_v8_model_id") })
+
+	post, err := db.GetPost(ctx, conn, 1002)
+	if err != nil || post.ModelID != 42 {
+		t.Errorf("post 1002 = %+v (err %v), want model 42", post, err)
+	}
+	media, err := db.GetMediaByPostID(ctx, conn, 1001)
+	if err != nil || len(media) != 1 || media[0].ModelID != 42 {
+		t.Errorf("media of post 1001 = %+v (err %v), want model 42", media, err)
+	}
+
+	// Writing the post again updates the migrated row.
+	if err := db.UpsertPost(ctx, conn, 1002, "Evening coffee", 3.5, true, true, "2024-01-02T10:00:00+00:00", 42); err != nil {
+		t.Fatal(err)
+	}
+	stats, err := db.GetStats(ctx, conn)
+	if err != nil || stats.PostCount != 2 {
+		t.Errorf("stats = %+v (err %v), want the 2 fixture posts", stats, err)
+	}
+}
+
+func TestWriteMigrationSQLLeavesDatabase(t *testing.T) {
+	path := copyFixture(t, "schema_v0.db")
+	before, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, pending, err := db.PlanMigrations(path)
+	if err != nil {
+		t.Fatalf("PlanMigrations: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := db.WriteMigrationSQL(&buf, pending); err != nil {
+		t.Fatalf("WriteMigrationSQL: %v", err)
+	}
+	for _, m := range pending {
+		if want := fmt.Sprintf("-- v%d: %s\nBEGIN;\n", m.Version, m.Description); !strings.Contains(buf.String(), want) {
+			t.Errorf("dry run output lacks %q", want)
+		}
+	}
+	if got := strings.Count(buf.String(), "COMMIT;"); got != len(pending) {
+		t.Errorf("dry run has %d transactions, want %d", got, len(pending))
+	}
+
+	after, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(before, after) {
+		t.Error("dry run modified the database")
+	}
+}
+
+// checkSampleRows verifies the fixture rows written at version from survived
+// the upgrade and are served by the current queries.
+func checkSampleRows(ctx context.Context, t *testing.T, conn *db.Conn, from int) {
+	t.Helper()
+
+	post, err := db.GetPost(ctx, conn, 1001)
+	if err != nil {
+		t.Fatalf("GetPost: %v", err)
+	}
+	if post.Text.String != "Sunset at the beach" || post.ModelID != 42 {
+		t.Errorf("post 1001 = %+v", post)
+	}
+
+	stats, err := db.GetStats(ctx, conn)
+	if err != nil {
+		t.Fatalf("GetStats: %v", err)
+	}
+	if stats.PostCount != 2 || stats.MessageCount != 1 || stats.StoryCount != 1 || stats.MediaCount != 2 || stats.Downloaded != 1 {
+		t.Errorf("stats = %+v", stats)
+	}
+
+	media, err := db.GetMediaByPostID(ctx, conn, 1001)
+	if err != nil || len(media) != 1 {
+		t.Fatalf("GetMediaByPostID: %v, %d rows", err, len(media))
+	}
+	if m := media[0]; m.Filename.String != "5001.jpg" || !m.Downloaded {
+		t.Errorf("media 5001 = %+v", m)
+	}
+	if from >= 4 && media[0].Quality.String != "source" {
+		t.Errorf("media 5001 quality = %q, want source", media[0].Quality.String)
+	}
+	if from >= 5 && (media[0].Width.Int64 != 1080 || media[0].Height.Int64 != 1920) {
+		t.Errorf("media 5001 size = %dx%d, want 1080x1920", media[0].Width.Int64, media[0].Height.Int64)
+	}
+
+	if from >= 2 {
+		failed, err := db.GetFailedMediaIDs(ctx, conn)
+		if err != nil {
+			t.Fatalf("GetFailedMediaIDs: %v", err)
+		}
+		if _, ok := failed[5002]; !ok {
+			t.Errorf("failed media = %v, want 5002", failed)
+		}
+	}
+
+	if from >= 3 {
+		queued, err := db.GetQueuedDownloads(ctx, conn)
+		if err != nil {
+			t.Fatalf("GetQueuedDownloads: %v", err)
+		}
+		if len(queued) != 1 || queued[0].MediaID != 5002 {
+			t.Errorf("queue = %+v, want media 5002", queued)
+		}
+	}
+
+	// post_search is built (v6) or rebuilt (v7, v8) from the existing rows.
+	hits, err := db.SearchPosts(ctx, conn, db.SearchQuery{Match: "beach"})
+	if err != nil {
+		t.Fatalf("SearchPosts: %v", err)
+	}
+	found := make(map[string]int64, len(hits))
+	for _, h := range hits {
+		found[h.Area] = h.PostID
+	}
+	if len(hits) != 2 || found["posts"] != 1001 || found["messages"] != 2001 {
+		t.Errorf("search hits = %+v, want post 1001 and message 2001", hits)
+	}
+}
+
+// copyFixture copies a testdata database into a temporary directory, so the
+// upgrade and its backup never touch the checked-in file.
+func copyFixture(t *testing.T, name string) string {
+	t.Helper()
+	src, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("open fixture: %v", err)
+	}
+	defer src.Close()
+
+	path := filepath.Join(t.TempDir(), "user_data.db")
+	dst, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("create copy: %v", err)
+	}
+	defer dst.Close()
+	if _, err := io.Copy(dst, src); err != nil {
+		t.Fatalf("copy fixture: %v", err)
+	}
+	return path
+}
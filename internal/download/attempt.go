@@ -0,0 +1,146 @@
+// =============================================================================
+// FILE: internal/download/attempt.go
+// PURPOSE: Download attempt reporting. Describes the outcome of each download
+//          attempt (HTTP status, error class, bytes, duration) and classifies
+//          failures as transient or permanent for retry decisions.
+// =============================================================================
+
+package download
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"time"
+)
+
+// ---------------------------------------------------------------------------
+// Error classes
+// ---------------------------------------------------------------------------
+
+// ErrorClass groups download failures by cause.
+type ErrorClass string
+
+const (
+	ErrorClassNone        ErrorClass = ""
+	ErrorClassNetwork     ErrorClass = "network"     // Connection reset, DNS, timeout
+	ErrorClassServer      ErrorClass = "server"      // 5xx or 429 from the CDN
+	ErrorClassExpired     ErrorClass = "expired"     // 401/403: signed link expired
+	ErrorClassGone        ErrorClass = "gone"        // 404/410: removed upstream
+	ErrorClassHTTP        ErrorClass = "http"        // Any other unexpected status
+	ErrorClassTruncated   ErrorClass = "truncated"   // Size differs from Content-Length
+	ErrorClassDRM         ErrorClass = "drm"         // Decryption failed
+	ErrorClassFile        ErrorClass = "file"        // Local filesystem error
+	ErrorClassUnavailable ErrorClass = "unavailable" // No URL or output path
+	ErrorClassCanceled    ErrorClass = "canceled"    // Run was interrupted
+	ErrorClassOther       ErrorClass = "other"
+)
+
+// Transient reports whether a retry later is likely to succeed.
+func (c ErrorClass) Transient() bool {
+	switch c {
+	case ErrorClassNetwork, ErrorClassServer, ErrorClassExpired, ErrorClassTruncated, ErrorClassCanceled:
+		return true
+	}
+	return false
+}
+
+// errUnavailable marks media that cannot be fetched as linked.
+var errUnavailable = errors.New("media unavailable")
+
+// StatusError is returned when the CDN answers with an unexpected status.
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("HTTP status: %d", e.StatusCode)
+}
+
+// ClassifyError maps a download error to its ErrorClass.
+//
+// Parameters:
+//   - err: The download error (nil for success).
+//   - protected: Whether the media went through DRM decryption.
+//
+// Returns:
+//   - The error class, and the HTTP status when the error carries one.
+func ClassifyError(err error, protected bool) (ErrorClass, int) {
+	if err == nil {
+		return ErrorClassNone, 0
+	}
+
+	var se *StatusError
+	if errors.As(err, &se) {
+		switch code := se.StatusCode; {
+		case code == 401 || code == 403:
+			return ErrorClassExpired, code
+		case code == 404 || code == 410:
+			return ErrorClassGone, code
+		case code == 429 || code >= 500:
+			return ErrorClassServer, code
+		default:
+			return ErrorClassHTTP, code
+		}
+	}
+
+	var sizeErr *SizeError
+	if errors.As(err, &sizeErr) {
+		return ErrorClassTruncated, 0
+	}
+
+	var netErr net.Error
+	var pathErr *os.PathError
+	var linkErr *os.LinkError
+	switch {
+	case errors.Is(err, context.Canceled):
+		return ErrorClassCanceled, 0
+	case errors.Is(err, errUnavailable):
+		return ErrorClassUnavailable, 0
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr):
+		return ErrorClassNetwork, 0
+	case errors.As(err, &pathErr), errors.As(err, &linkErr), errors.Is(err, errInsufficientSpace):
+		return ErrorClassFile, 0
+	case protected:
+		return ErrorClassDRM, 0
+	}
+	return ErrorClassOther, 0
+}
+
+// ---------------------------------------------------------------------------
+// Attempt
+// ---------------------------------------------------------------------------
+
+// Attempt records the outcome of one download attempt.
+type Attempt struct {
+	MediaID      int64
+	PostID       int64
+	ModelID      int64
+	Username     string
+	Started      time.Time
+	Duration     time.Duration
+	HTTPStatus   int
+	BytesWritten int64
+	ErrorClass   ErrorClass
+	Err          error
+}
+
+// Succeeded reports whether the attempt downloaded the file.
+func (a Attempt) Succeeded() bool {
+	return a.Err == nil
+}
+
+// AttemptFunc receives every download attempt. It is called concurrently
+// from download workers.
+type AttemptFunc func(ctx context.Context, a Attempt)
+
+// SetAttemptRecorder configures a callback invoked after each download
+// attempt, successful or not.
+//
+// Parameters:
+//   - fn: The callback, or nil to disable recording.
+func (o *Orchestrator) SetAttemptRecorder(fn AttemptFunc) {
+	o.recordAttempt = fn
+}
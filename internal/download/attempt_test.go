@@ -0,0 +1,90 @@
+// =============================================================================
+// FILE: internal/download/attempt_test.go
+// PURPOSE: Tests for download attempt reporting: the recorded HTTP status is
+//          the one the CDN answered with, 206 for resumed and segmented
+//          downloads.
+// =============================================================================
+
+package download
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"gofscraper/internal/api/apitest"
+	gohttp "gofscraper/internal/http"
+	"gofscraper/internal/model"
+)
+
+func TestAttemptHTTPStatus(t *testing.T) {
+	body := bytes.Repeat([]byte("0123456789abcdef"), 4096)
+
+	tests := []struct {
+		name       string
+		segments   int
+		partial    int // Bytes already in the .part file
+		missing    bool
+		wantStatus int
+		wantClass  ErrorClass
+	}{
+		{name: "fresh download", segments: 1, wantStatus: 200},
+		{name: "resumed download", segments: 1, partial: 1000, wantStatus: 206},
+		{name: "segmented download", segments: 4, wantStatus: 206},
+		{name: "missing file", segments: 1, missing: true, wantStatus: 404, wantClass: ErrorClassGone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := apitest.NewServer(t)
+			url := srv.URL + "/files/1.bin"
+			if !tt.missing {
+				url = srv.File("/files/1.bin", body)
+			}
+
+			out := filepath.Join(t.TempDir(), "1.bin")
+			if tt.partial > 0 {
+				if err := os.WriteFile(out+".part", body[:tt.partial], 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			cfg := DefaultConfig()
+			cfg.Workers = 1
+			cfg.Segments = tt.segments
+			cfg.SegmentMinSize = 1
+			o := NewOrchestrator(cfg, gohttp.New(nil))
+
+			var mu sync.Mutex
+			var attempts []Attempt
+			o.SetAttemptRecorder(func(_ context.Context, a Attempt) {
+				mu.Lock()
+				defer mu.Unlock()
+				attempts = append(attempts, a)
+			})
+
+			m := &model.Media{ID: 1, RawURL: url, FilePath: out}
+			if _, err := o.Run(context.Background(), []*model.Media{m}); err != nil {
+				t.Fatalf("Run: %v", err)
+			}
+
+			if len(attempts) != 1 {
+				t.Fatalf("recorded %d attempts, want 1", len(attempts))
+			}
+			a := attempts[0]
+			if a.HTTPStatus != tt.wantStatus || a.ErrorClass != tt.wantClass {
+				t.Errorf("attempt status %d class %q, want %d %q (err %v)",
+					a.HTTPStatus, a.ErrorClass, tt.wantStatus, tt.wantClass, a.Err)
+			}
+			if tt.wantClass == ErrorClassNone {
+				got, err := os.ReadFile(out)
+				if err != nil || !bytes.Equal(got, body) {
+					t.Errorf("downloaded file differs from the served file (err %v)", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,257 @@
+// =============================================================================
+// FILE: internal/download/bandwidth.go
+// PURPOSE: Shared bandwidth limiter. A single token bucket that every
+//          download worker and segment draws from, so the configured limit
+//          caps total throughput rather than each stream. The limit follows
+//          a time-of-day schedule and can be overridden at runtime.
+// =============================================================================
+
+package download
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ---------------------------------------------------------------------------
+// Schedule windows
+// ---------------------------------------------------------------------------
+
+// BandwidthWindow applies a limit during part of each day. Start and End
+// are minutes since midnight; a window with End before Start wraps past
+// midnight, and one with End equal to Start covers the whole day.
+type BandwidthWindow struct {
+	Start int
+	End   int
+	Limit int64 // Bytes per second (0 = unlimited)
+}
+
+// ParseBandwidthWindow builds a window from "HH:MM" start and end times.
+//
+// Parameters:
+//   - start: Start of the window, e.g. "08:00".
+//   - end: End of the window, e.g. "23:00".
+//   - limit: Bytes per second during the window (0 = unlimited).
+//
+// Returns:
+//   - The window, or an error if a time or the limit is invalid.
+func ParseBandwidthWindow(start, end string, limit int64) (BandwidthWindow, error) {
+	s, err := parseClock(start)
+	if err != nil {
+		return BandwidthWindow{}, err
+	}
+	e, err := parseClock(end)
+	if err != nil {
+		return BandwidthWindow{}, err
+	}
+	if limit < 0 {
+		return BandwidthWindow{}, fmt.Errorf("negative limit %d", limit)
+	}
+	return BandwidthWindow{Start: s, End: e, Limit: limit}, nil
+}
+
+// parseClock converts "HH:MM" to minutes since midnight.
+func parseClock(s string) (int, error) {
+	hh, mm, ok := strings.Cut(strings.TrimSpace(s), ":")
+	if !ok {
+		return 0, fmt.Errorf("invalid time %q (want HH:MM)", s)
+	}
+	h, err := strconv.Atoi(hh)
+	if err != nil || h < 0 || h > 23 {
+		return 0, fmt.Errorf("invalid hour in %q", s)
+	}
+	m, err := strconv.Atoi(mm)
+	if err != nil || m < 0 || m > 59 {
+		return 0, fmt.Errorf("invalid minute in %q", s)
+	}
+	return h*60 + m, nil
+}
+
+// contains reports whether t's local time of day falls inside the window.
+func (w BandwidthWindow) contains(t time.Time) bool {
+	m := t.Hour()*60 + t.Minute()
+	switch {
+	case w.Start == w.End:
+		return true
+	case w.Start < w.End:
+		return m >= w.Start && m < w.End
+	default:
+		return m >= w.Start || m < w.End
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Bandwidth
+// ---------------------------------------------------------------------------
+
+// bandwidthSlices is how many reads per second a limited reader is split
+// into, which keeps individual waits short and the rate smooth.
+const bandwidthSlices = 10
+
+// minBandwidthChunk is the smallest read a limited reader makes.
+const minBandwidthChunk = 1024
+
+// Bandwidth is a token bucket shared by all downloads. The effective limit
+// is the runtime override if set, else the first schedule window covering
+// the current time, else the base limit. Safe for concurrent use.
+type Bandwidth struct {
+	mu       sync.Mutex
+	base     int64
+	windows  []BandwidthWindow
+	override int64 // -1 = no override
+	rate     int64 // Limit the bucket was last filled at
+	next     time.Time
+}
+
+// NewBandwidth creates a limiter with the given base limit.
+//
+// Parameters:
+//   - limit: Bytes per second outside any schedule window (0 = unlimited).
+//
+// Returns:
+//   - A Bandwidth limiter with no schedule or override.
+func NewBandwidth(limit int64) *Bandwidth {
+	return &Bandwidth{base: limit, override: -1}
+}
+
+// SetLimit replaces the base limit.
+func (b *Bandwidth) SetLimit(limit int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.base = limit
+}
+
+// SetSchedule replaces the time-of-day schedule. The first window that
+// covers the current time wins.
+func (b *Bandwidth) SetSchedule(windows []BandwidthWindow) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.windows = windows
+}
+
+// SetOverride pins the limit until ClearOverride is called, ignoring the
+// base limit and schedule.
+//
+// Parameters:
+//   - limit: Bytes per second (0 = unlimited).
+func (b *Bandwidth) SetOverride(limit int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.override = max(limit, 0)
+}
+
+// ClearOverride returns to the base limit and schedule.
+func (b *Bandwidth) ClearOverride() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.override = -1
+}
+
+// Overridden reports whether a runtime override is in effect.
+func (b *Bandwidth) Overridden() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.override >= 0
+}
+
+// Limit returns the limit in effect now.
+//
+// Returns:
+//   - Bytes per second (0 = unlimited).
+func (b *Bandwidth) Limit() int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.limitLocked(time.Now())
+}
+
+func (b *Bandwidth) limitLocked(now time.Time) int64 {
+	if b.override >= 0 {
+		return b.override
+	}
+	for _, w := range b.windows {
+		if w.contains(now) {
+			return w.Limit
+		}
+	}
+	return b.base
+}
+
+// Wait charges n bytes already read against the bucket and blocks until
+// the limit allows them.
+//
+// Parameters:
+//   - ctx: Context for cancellation.
+//   - n: Bytes read.
+//
+// Returns:
+//   - The context's error if it is cancelled while waiting.
+func (b *Bandwidth) Wait(ctx context.Context, n int) error {
+	now := time.Now()
+
+	b.mu.Lock()
+	rate := b.limitLocked(now)
+	if rate != b.rate || b.next.Before(now) {
+		b.rate = rate
+		b.next = now
+	}
+	if rate <= 0 {
+		b.mu.Unlock()
+		return nil
+	}
+	b.next = b.next.Add(time.Duration(float64(n) / float64(rate) * float64(time.Second)))
+	delay := b.next.Sub(now)
+	b.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	t := time.NewTimer(delay)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Reader wraps r so that reads draw from the bucket.
+//
+// Parameters:
+//   - ctx: Context that cancels waiting.
+//   - r: The underlying reader.
+//
+// Returns:
+//   - A reader limited by b.
+func (b *Bandwidth) Reader(ctx context.Context, r io.Reader) io.Reader {
+	return &bandwidthReader{ctx: ctx, reader: r, bw: b}
+}
+
+// bandwidthReader is an io.Reader throttled by a shared Bandwidth.
+type bandwidthReader struct {
+	ctx    context.Context
+	reader io.Reader
+	bw     *Bandwidth
+}
+
+// Read implements io.Reader with shared rate limiting.
+func (r *bandwidthReader) Read(p []byte) (int, error) {
+	if limit := r.bw.Limit(); limit > 0 {
+		chunk := max(limit/bandwidthSlices, minBandwidthChunk)
+		if int64(len(p)) > chunk {
+			p = p[:chunk]
+		}
+	}
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		if werr := r.bw.Wait(r.ctx, n); werr != nil && err == nil {
+			err = werr
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,129 @@
+// =============================================================================
+// FILE: internal/download/bandwidth_test.go
+// PURPOSE: Tests for the shared bandwidth limiter: schedule windows, limit
+//          precedence, and one limit capping the total rate of concurrent
+//          readers.
+// =============================================================================
+
+package download
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBandwidthWindowContains(t *testing.T) {
+	at := func(h, m int) time.Time { return time.Date(2024, 5, 1, h, m, 0, 0, time.Local) }
+	tests := []struct {
+		start, end string
+		t          time.Time
+		want       bool
+	}{
+		{"08:00", "18:00", at(8, 0), true},
+		{"08:00", "18:00", at(17, 59), true},
+		{"08:00", "18:00", at(18, 0), false},
+		{"08:00", "18:00", at(7, 59), false},
+		{"23:00", "07:00", at(23, 30), true},
+		{"23:00", "07:00", at(3, 0), true},
+		{"23:00", "07:00", at(7, 0), false},
+		{"23:00", "07:00", at(12, 0), false},
+		{"00:00", "00:00", at(12, 0), true},
+	}
+	for _, tt := range tests {
+		w, err := ParseBandwidthWindow(tt.start, tt.end, 1)
+		if err != nil {
+			t.Fatalf("ParseBandwidthWindow(%s, %s): %v", tt.start, tt.end, err)
+		}
+		if got := w.contains(tt.t); got != tt.want {
+			t.Errorf("%s-%s contains %s = %v, want %v", tt.start, tt.end, tt.t.Format("15:04"), got, tt.want)
+		}
+	}
+}
+
+func TestParseBandwidthWindowInvalid(t *testing.T) {
+	for _, tt := range []struct {
+		start, end string
+		limit      int64
+	}{
+		{"8", "18:00", 1},
+		{"24:00", "18:00", 1},
+		{"08:60", "18:00", 1},
+		{"08:00", "xx:00", 1},
+		{"08:00", "18:00", -1},
+	} {
+		if _, err := ParseBandwidthWindow(tt.start, tt.end, tt.limit); err == nil {
+			t.Errorf("ParseBandwidthWindow(%q, %q, %d) succeeded", tt.start, tt.end, tt.limit)
+		}
+	}
+}
+
+func TestBandwidthLimitPrecedence(t *testing.T) {
+	b := NewBandwidth(100)
+	if got := b.Limit(); got != 100 {
+		t.Errorf("base limit = %d, want 100", got)
+	}
+
+	b.SetSchedule([]BandwidthWindow{
+		{Start: 0, End: 0, Limit: 200}, // Whole day
+		{Start: 0, End: 0, Limit: 300}, // Shadowed by the first
+	})
+	if got := b.Limit(); got != 200 {
+		t.Errorf("scheduled limit = %d, want the first window's 200", got)
+	}
+
+	b.SetOverride(0)
+	if got := b.Limit(); got != 0 || !b.Overridden() {
+		t.Errorf("override limit = %d (overridden %v), want unlimited", got, b.Overridden())
+	}
+
+	b.ClearOverride()
+	b.SetSchedule(nil)
+	b.SetLimit(50)
+	if got := b.Limit(); got != 50 || b.Overridden() {
+		t.Errorf("limit = %d (overridden %v), want base 50", got, b.Overridden())
+	}
+}
+
+func TestBandwidthSharedAcrossReaders(t *testing.T) {
+	const (
+		limit   = 256 * 1024 // Bytes per second
+		readers = 4
+		each    = 32 * 1024
+	)
+	b := NewBandwidth(limit)
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for range readers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r := b.Reader(context.Background(), bytes.NewReader(make([]byte, each)))
+			if n, err := io.Copy(io.Discard, r); err != nil || n != each {
+				t.Errorf("read %d bytes, %v", n, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Four readers share one limit: 128 KiB at 256 KiB/s takes about half
+	// a second, not the eighth of a second four separate limits would.
+	want := time.Duration(float64(readers*each) / limit * float64(time.Second))
+	if elapsed := time.Since(start); elapsed < want*3/4 {
+		t.Errorf("read %d bytes in %s, want about %s at the shared limit", readers*each, elapsed, want)
+	}
+}
+
+func TestBandwidthWaitCancelled(t *testing.T) {
+	b := NewBandwidth(1)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := b.Wait(ctx, 1024); !errors.Is(err, context.Canceled) {
+		t.Errorf("Wait = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,249 @@
+// =============================================================================
+// FILE: internal/download/diskguard.go
+// PURPOSE: Disk-space guard for downloads. Checks free space on the
+//          filesystem each file is written to before downloading it and
+//          pauses every worker while it is below the configured minimum,
+//          alerting on pause and resume, and resuming once space is freed.
+//          Ports Python utils/system/free.py checks in the download loop.
+// =============================================================================
+
+package download
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/dustin/go-humanize"
+
+	gohttp "gofscraper/internal/http"
+	"gofscraper/internal/model"
+	"gofscraper/internal/utils/system"
+)
+
+// ---------------------------------------------------------------------------
+// Disk guard
+// ---------------------------------------------------------------------------
+
+// AlertFunc receives a user-facing alert, e.g. to forward to Discord.
+type AlertFunc func(message string)
+
+// diskCheckInterval is how often a paused queue re-checks free space.
+const diskCheckInterval = 30 * time.Second
+
+// errInsufficientSpace fails a file that cannot fit above the minimum
+// even with no other download in progress, so waiting would never end.
+var errInsufficientSpace = errors.New("insufficient disk space")
+
+// diskGuard pauses downloads while free space is below minFree. Bytes
+// expected by downloads in progress are reserved so concurrent workers do
+// not all claim the same free space.
+type diskGuard struct {
+	minFree  int64
+	logger   *slog.Logger
+	alert    AlertFunc                        // Optional; see Config.DiskAlert
+	free     func(path string) (int64, error) // freeSpace; replaced in tests
+	mu       sync.Mutex
+	reserved int64
+	resumed  chan struct{} // Non-nil while paused; closed on resume
+	released chan struct{} // Non-nil while a file waits on others; closed by release
+}
+
+// newDiskGuard creates a guard keeping minFree bytes free. alert, if not
+// nil, is called once per pause and once per resume.
+func newDiskGuard(minFree int64, logger *slog.Logger, alert AlertFunc) *diskGuard {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &diskGuard{minFree: minFree, logger: logger, alert: alert, free: freeSpace}
+}
+
+// reserve blocks until dir's filesystem has room for need more bytes above
+// the minimum, then reserves them. The caller must release the reservation
+// once the file is written.
+//
+// When free space is below the minimum, every worker pauses until space is
+// freed. When there is room above the minimum but not for this file, it
+// waits for other downloads to release their reservations; with nothing
+// else reserved the file can never fit and fails instead.
+//
+// Parameters:
+//   - ctx: Context for cancellation.
+//   - dir: Directory the file will be written to.
+//   - need: Bytes the file still needs (0 if unknown).
+//
+// Returns:
+//   - errInsufficientSpace if the file cannot fit on its own, or the
+//     context's error if it is cancelled while waiting.
+func (g *diskGuard) reserve(ctx context.Context, dir string, need int64) error {
+	for {
+		// A cancelled run must not pause, and alert, again.
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		var alert string
+		var wait chan struct{}
+		g.mu.Lock()
+		switch {
+		case g.resumed != nil:
+			wait = g.resumed
+		default:
+			free, err := g.free(dir)
+			switch {
+			case err != nil || free-g.reserved-need >= g.minFree:
+				g.reserved += need
+				g.mu.Unlock()
+				return nil
+			case free < g.minFree:
+				g.resumed = make(chan struct{})
+				wait = g.resumed
+				g.logger.Warn("low disk space, pausing downloads",
+					"path", dir,
+					"free", humanize.IBytes(uint64(max(free, 0))),
+					"needed", humanize.IBytes(uint64(need)),
+					"minimum", humanize.IBytes(uint64(g.minFree)),
+				)
+				alert = fmt.Sprintf("**gofscraper paused downloads**\nFree space on `%s` is `%s`, below the `%s` minimum.",
+					dir, humanize.IBytes(uint64(max(free, 0))), humanize.IBytes(uint64(g.minFree)))
+				go g.watch(ctx, dir)
+			case g.reserved > 0:
+				// Room above the minimum, but not for this file until
+				// other downloads finish.
+				if g.released == nil {
+					g.released = make(chan struct{})
+				}
+				wait = g.released
+			default:
+				// Nothing else is reserved, so only the user freeing
+				// space would make this file fit.
+				g.mu.Unlock()
+				return fmt.Errorf("%w: need %s, %s has %s free above the %s minimum",
+					errInsufficientSpace, humanize.IBytes(uint64(need)), dir,
+					humanize.IBytes(uint64(free-g.minFree)), humanize.IBytes(uint64(g.minFree)))
+			}
+		}
+		g.mu.Unlock()
+		if alert != "" {
+			g.notify(alert)
+		}
+
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// release returns a reservation made by reserve and wakes files waiting
+// for one.
+func (g *diskGuard) release(n int64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.reserved -= n
+	if g.released != nil {
+		close(g.released)
+		g.released = nil
+	}
+}
+
+// watch polls free space while paused and resumes the queue once it is back
+// above the minimum, or when ctx is cancelled. Resumed workers re-check
+// whether their own file fits.
+func (g *diskGuard) watch(ctx context.Context, dir string) {
+	ticker := time.NewTicker(diskCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+		case <-ticker.C:
+			g.mu.Lock()
+			free, err := g.free(dir)
+			ok := err == nil && free-g.reserved >= g.minFree
+			g.mu.Unlock()
+			if !ok {
+				continue
+			}
+			g.logger.Warn("disk space available, resuming downloads",
+				"path", dir, "free", humanize.IBytes(uint64(free)))
+			g.notify(fmt.Sprintf("**gofscraper resumed downloads**\nFree space on `%s` is `%s`.",
+				dir, humanize.IBytes(uint64(free))))
+		}
+
+		g.mu.Lock()
+		close(g.resumed)
+		g.resumed = nil
+		g.mu.Unlock()
+		return
+	}
+}
+
+// notify sends message to the alert callback, if any.
+func (g *diskGuard) notify(message string) {
+	if g.alert != nil {
+		g.alert(message)
+	}
+}
+
+// freeSpace returns the bytes available on the filesystem holding path,
+// checking its nearest existing ancestor if it does not exist yet.
+func freeSpace(path string) (int64, error) {
+	for {
+		if _, err := os.Stat(path); err == nil || !errors.Is(err, os.ErrNotExist) {
+			break
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			break
+		}
+		path = parent
+	}
+	du, err := system.GetDiskUsage(path)
+	if err != nil {
+		return 0, err
+	}
+	return int64(du.Free), nil
+}
+
+// ---------------------------------------------------------------------------
+// Expected size
+// ---------------------------------------------------------------------------
+
+// remainingSize estimates how many more bytes m needs on disk: its known
+// size (from the API or a HEAD request) less any partial file already
+// written. Returns 0 when the size cannot be determined.
+func (o *Orchestrator) remainingSize(ctx context.Context, m *model.Media) int64 {
+	size := int64(m.Size)
+	if size <= 0 && m.RawURL != "" && !m.IsProtected() {
+		size = o.headSize(ctx, m.RawURL)
+	}
+	if size <= 0 {
+		return 0
+	}
+	if info, err := os.Stat(o.workPath(m.FilePath, ".part")); err == nil {
+		size -= info.Size()
+	}
+	return max(size, 0)
+}
+
+// headSize asks the server for a file's Content-Length. Returns 0 on any
+// failure.
+func (o *Orchestrator) headSize(ctx context.Context, rawURL string) int64 {
+	req := gohttp.NewRequest(rawURL)
+	req.Method = http.MethodHead
+	resp, err := o.session.Do(ctx, req)
+	if err != nil {
+		return 0
+	}
+	defer resp.Close()
+	if !resp.IsOK() {
+		return 0
+	}
+	return max(expectedSize(resp), 0)
+}
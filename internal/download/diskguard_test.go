@@ -0,0 +1,158 @@
+// =============================================================================
+// FILE: internal/download/diskguard_test.go
+// PURPOSE: Tests for the disk-space guard: workers waiting on one pause
+//          raise a single alert, cancelling a pause sends no resume, and a
+//          file too large for the free space waits for other downloads or
+//          fails on its own instead of stalling the batch.
+// =============================================================================
+
+package download
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gofscraper/internal/api/apitest"
+	gohttp "gofscraper/internal/http"
+	"gofscraper/internal/model"
+)
+
+// fixedFree reports free bytes of free space for every path.
+func fixedFree(free int64) func(string) (int64, error) {
+	return func(string) (int64, error) { return free, nil }
+}
+
+func TestDiskGuardAlertsOncePerPause(t *testing.T) {
+	var mu sync.Mutex
+	var alerts []string
+	paused := make(chan struct{}, 1)
+	g := newDiskGuard(100, nil, func(message string) {
+		mu.Lock()
+		defer mu.Unlock()
+		alerts = append(alerts, message)
+		paused <- struct{}{}
+	})
+	g.free = fixedFree(50)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	errs := make(chan error, 3)
+	for range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- g.reserve(ctx, t.TempDir(), 1)
+		}()
+	}
+	<-paused
+	cancel()
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != context.Canceled {
+			t.Errorf("reserve = %v, want context.Canceled", err)
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(alerts) != 1 || !strings.Contains(alerts[0], "paused downloads") {
+		t.Errorf("alerts = %q, want one pause alert", alerts)
+	}
+}
+
+func TestDiskGuardFileLargerThanFreeSpace(t *testing.T) {
+	g := newDiskGuard(100, nil, func(message string) {
+		t.Errorf("unexpected alert %q", message)
+	})
+	g.free = fixedFree(150)
+
+	// Nothing else reserved: waiting would never end, so the file fails.
+	err := g.reserve(context.Background(), t.TempDir(), 80)
+	if !errors.Is(err, errInsufficientSpace) {
+		t.Fatalf("reserve = %v, want errInsufficientSpace", err)
+	}
+	if class, _ := ClassifyError(err, false); class != ErrorClassFile {
+		t.Errorf("ClassifyError = %s, want %s", class, ErrorClassFile)
+	}
+
+	// A file that fits is still reserved.
+	if err := g.reserve(context.Background(), t.TempDir(), 50); err != nil {
+		t.Fatalf("reserve small file: %v", err)
+	}
+}
+
+func TestDiskGuardWaitsForReservations(t *testing.T) {
+	g := newDiskGuard(100, nil, nil)
+	g.free = fixedFree(150)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := g.reserve(ctx, t.TempDir(), 30); err != nil {
+		t.Fatalf("first reserve: %v", err)
+	}
+
+	// 40 bytes fit above the minimum, but not alongside the first file.
+	done := make(chan error, 1)
+	go func() { done <- g.reserve(ctx, t.TempDir(), 40) }()
+
+	// Let the second file wait, then finish the first.
+	for {
+		g.mu.Lock()
+		waiting := g.released != nil
+		g.mu.Unlock()
+		if waiting {
+			break
+		}
+		select {
+		case err := <-done:
+			t.Fatalf("reserve returned %v before the first file was released", err)
+		case <-time.After(time.Millisecond):
+		}
+	}
+	g.release(30)
+
+	if err := <-done; err != nil {
+		t.Fatalf("second reserve: %v", err)
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.reserved != 40 {
+		t.Errorf("reserved = %d, want 40", g.reserved)
+	}
+}
+
+func TestDownloadTooLargeForDiskFails(t *testing.T) {
+	srv := apitest.NewServer(t)
+	url := srv.File("/files/1.bin", make([]byte, 64))
+
+	cfg := DefaultConfig()
+	cfg.Workers = 1
+	cfg.MinFreeSpace = 100
+	o := NewOrchestrator(cfg, gohttp.New(nil))
+	o.disk.free = fixedFree(120)
+
+	var attempts []Attempt
+	o.SetAttemptRecorder(func(_ context.Context, a Attempt) { attempts = append(attempts, a) })
+
+	m := &model.Media{ID: 1, RawURL: url, FilePath: filepath.Join(t.TempDir(), "1.bin"), Size: 64}
+	result, err := o.Run(context.Background(), []*model.Media{m})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if result.Failed != 1 || result.Skipped != 0 || !errors.Is(result.Errors[0].Err, errInsufficientSpace) {
+		t.Errorf("result failed %d skipped %d errors %v, want one insufficient space failure",
+			result.Failed, result.Skipped, result.Errors)
+	}
+	if len(attempts) != 1 || attempts[0].ErrorClass != ErrorClassFile {
+		t.Errorf("attempts = %+v, want one file error", attempts)
+	}
+	if got := len(srv.Requests()); got != 0 {
+		t.Errorf("server got %d requests, want none", got)
+	}
+}
@@ -11,7 +11,9 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"path/filepath"
 	"sync"
+	"time"
 
 	"gofscraper/internal/drm"
 	gohttp "gofscraper/internal/http"
@@ -24,28 +26,39 @@ import (
 
 // Config holds download configuration.
 type Config struct {
-	Workers       int     // Number of concurrent download workers
-	ChunkSize     int64   // Download chunk size in bytes
-	MaxRetries    int     // Max retries per download
-	SpeedLimit    int64   // Bandwidth limit in bytes/sec (0 = unlimited)
-	TempDir       string  // Temp directory for in-progress downloads
-	SkipPrevious  bool    // Skip previously downloaded media
-	ResumeEnabled bool    // Enable resume for interrupted downloads
-	FFmpegPath    string  // Path to FFmpeg binary
-	Logger        *slog.Logger
+	Workers        int        // Number of concurrent download workers
+	ChunkSize      int64      // Download chunk size in bytes
+	MaxRetries     int        // Max retries per download
+	SpeedLimit     int64      // Bandwidth limit in bytes/sec (0 = unlimited)
+	Bandwidth      *Bandwidth // Shared limiter; built from SpeedLimit when nil
+	Segments       int        // Parallel byte-range segments per large file (1 = single stream)
+	SegmentMinSize int64      // Smallest file size downloaded in segments
+	MinFreeSpace   int64      // Free bytes to keep on the disk downloads are written to (0 = no check)
+	DiskAlert      AlertFunc  // Told when downloads pause for disk space and resume (nil = log only)
+	TempDir        string     // Temp directory for in-progress downloads
+	SetFileTimes   bool       // Set file mtime/atime to the post date
+	EmbedMetadata  bool       // Embed post date/caption into JPEG and MP4 files
+	ProbeMedia     bool       // Probe duration, dimensions, and codec after download
+	WriteSidecars  bool       // Write a <file>.json sidecar with post context
+	SkipPrevious   bool       // Skip previously downloaded media
+	ResumeEnabled  bool       // Enable resume for interrupted downloads
+	FFmpegPath     string     // Path to FFmpeg binary
+	Logger         *slog.Logger
 }
 
 // DefaultConfig returns sensible download defaults.
 func DefaultConfig() Config {
 	return Config{
-		Workers:       5,
-		ChunkSize:     1024 * 1024, // 1 MB
-		MaxRetries:    3,
-		SpeedLimit:    0,
-		TempDir:       "",
-		SkipPrevious:  true,
-		ResumeEnabled: true,
-		FFmpegPath:    "ffmpeg",
+		Workers:        5,
+		ChunkSize:      1024 * 1024, // 1 MB
+		MaxRetries:     3,
+		SpeedLimit:     0,
+		Segments:       4,
+		SegmentMinSize: 64 * 1024 * 1024, // 64 MB
+		TempDir:        "",
+		SkipPrevious:   true,
+		ResumeEnabled:  true,
+		FFmpegPath:     "ffmpeg",
 	}
 }
 
@@ -113,7 +126,14 @@ type Orchestrator struct {
 	cfg     Config
 	session *gohttp.SessionManager
 	drm     *drm.Manager
+	disk    *diskGuard
 	logger  *slog.Logger
+
+	recordAttempt AttemptFunc
+	queue         Queue
+
+	ffprobeOnce sync.Once
+	ffprobe     string // Resolved ffprobe path ("" if unavailable)
 }
 
 // NewOrchestrator creates a download orchestrator.
@@ -125,11 +145,18 @@ type Orchestrator struct {
 // Returns:
 //   - Configured Orchestrator.
 func NewOrchestrator(cfg Config, session *gohttp.SessionManager) *Orchestrator {
-	return &Orchestrator{
+	if cfg.Bandwidth == nil {
+		cfg.Bandwidth = NewBandwidth(cfg.SpeedLimit)
+	}
+	o := &Orchestrator{
 		cfg:     cfg,
 		session: session,
 		logger:  cfg.Logger,
 	}
+	if cfg.MinFreeSpace > 0 {
+		o.disk = newDiskGuard(cfg.MinFreeSpace, cfg.Logger, cfg.DiskAlert)
+	}
+	return o
 }
 
 // Run executes the download pipeline for the given media items.
@@ -180,19 +207,66 @@ func (o *Orchestrator) Run(ctx context.Context, media []*model.Media) (*Result,
 	return result, nil
 }
 
-// downloadOne handles a single media download.
+// downloadOne handles a single media download and reports the attempt.
 func (o *Orchestrator) downloadOne(ctx context.Context, m *model.Media, result *Result) {
 	if !m.IsLinked() {
 		result.AddSkipped()
 		m.MarkDownloadSkipped()
+		if o.queue != nil {
+			o.queue.Finished(ctx, m)
+		}
 		return
 	}
 
+	// Wait for disk space where the file is written (temp_dir when set);
+	// every worker pauses while that disk is low. A file too large to ever
+	// fit fails below without being downloaded.
+	var diskErr error
+	if o.disk != nil && m.FilePath != "" {
+		need := o.remainingSize(ctx, m)
+		diskErr = o.disk.reserve(ctx, filepath.Dir(o.workPath(m.FilePath, ".part")), need)
+		if diskErr == nil {
+			defer o.disk.release(need)
+		} else if ctx.Err() != nil {
+			// Cancelled while paused; the item stays queued.
+			result.AddSkipped()
+			m.MarkDownloadSkipped()
+			if o.logger != nil {
+				o.logger.Debug("download cancelled while waiting for disk space", "media_id", m.ID)
+			}
+			return
+		}
+	}
+	if o.queue != nil {
+		o.queue.Started(ctx, m)
+	}
+
+	attempt := Attempt{
+		MediaID:  m.ID,
+		PostID:   m.PostID,
+		ModelID:  m.ModelID,
+		Username: m.Username,
+		Started:  time.Now(),
+	}
+
 	var err error
-	if m.IsProtected() {
-		err = o.downloadProtected(ctx, m)
-	} else {
-		err = o.downloadNormal(ctx, m)
+	var status int
+	switch {
+	case diskErr != nil:
+		err = diskErr
+	case m.IsProtected():
+		attempt.BytesWritten, status, err = o.downloadProtected(ctx, m)
+	default:
+		attempt.BytesWritten, status, err = o.downloadNormal(ctx, m)
+	}
+
+	attempt.Duration = time.Since(attempt.Started)
+	attempt.Err = err
+	attempt.ErrorClass, attempt.HTTPStatus = ClassifyError(err, m.IsProtected())
+	if attempt.HTTPStatus == 0 {
+		// The status of the response the bytes came from, e.g. 206 for a
+		// resumed or segmented download that failed after it was received.
+		attempt.HTTPStatus = status
 	}
 
 	if err != nil {
@@ -201,11 +275,21 @@ func (o *Orchestrator) downloadOne(ctx context.Context, m *model.Media, result *
 		if o.logger != nil {
 			o.logger.Error("download failed",
 				"media_id", m.ID,
+				"class", attempt.ErrorClass,
 				"error", err,
 			)
 		}
 	} else {
 		result.AddSuccess()
 		m.MarkDownloadSucceeded()
+		o.applyFileMetadata(ctx, m)
+	}
+
+	if o.recordAttempt != nil {
+		o.recordAttempt(ctx, attempt)
+	}
+	// An attempt cut short by cancellation stays queued for the next run.
+	if o.queue != nil && (err == nil || ctx.Err() == nil) {
+		o.queue.Finished(ctx, m)
 	}
 }
@@ -10,6 +10,8 @@ package download
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 
 	"gofscraper/internal/model"
 )
@@ -19,26 +21,37 @@ import (
 // ---------------------------------------------------------------------------
 
 // downloadProtected handles a DRM-protected media download.
-func (o *Orchestrator) downloadProtected(ctx context.Context, m *model.Media) error {
+//
+// Returns:
+//   - Size of the decrypted output file, the HTTP status of the encrypted
+//     content fetch, and any error.
+func (o *Orchestrator) downloadProtected(ctx context.Context, m *model.Media) (int64, int, error) {
 	if o.drm == nil || !o.drm.IsEnabled() {
-		return fmt.Errorf("DRM decryption not configured")
+		return 0, 0, fmt.Errorf("DRM decryption not configured")
 	}
 
 	if m.MpdURL == "" {
-		return fmt.Errorf("no MPD URL for protected media %d", m.ID)
+		return 0, 0, fmt.Errorf("no MPD URL for protected media %d: %w", m.ID, errUnavailable)
 	}
 
 	outputPath := m.FilePath
 	if outputPath == "" {
-		return fmt.Errorf("no output path set for media %d", m.ID)
+		return 0, 0, fmt.Errorf("no output path set for media %d: %w", m.ID, errUnavailable)
 	}
 
 	// Build license URL from media info.
 	licenseURL := fmt.Sprintf("https://onlyfans.com/api2/v2/users/media/%d/drm/post/widevine", m.ID)
 
-	result, err := o.drm.Decrypt(ctx, m.MpdURL, licenseURL, outputPath)
+	// FFmpeg writes to the working path, keeping the extension it uses to
+	// pick the container; the result is promoted once complete.
+	workPath := o.workPath(outputPath, "")
+	if err := os.MkdirAll(filepath.Dir(workPath), 0o755); err != nil {
+		return 0, 0, fmt.Errorf("create directory: %w", err)
+	}
+	result, err := o.drm.Decrypt(ctx, m.MpdURL, licenseURL, workPath)
 	if err != nil {
-		return fmt.Errorf("DRM decrypt: %w", err)
+		os.Remove(workPath)
+		return 0, 0, fmt.Errorf("DRM decrypt: %w", err)
 	}
 
 	if o.logger != nil {
@@ -49,5 +62,8 @@ func (o *Orchestrator) downloadProtected(ctx context.Context, m *model.Media) er
 		)
 	}
 
-	return nil
+	if err := o.finishFile(m, result.OutputPath, outputPath, -1, ""); err != nil {
+		return 0, result.HTTPStatus, err
+	}
+	return int64(m.Size), result.HTTPStatus, nil
 }
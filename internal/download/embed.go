@@ -0,0 +1,147 @@
+// =============================================================================
+// FILE: internal/download/embed.go
+// PURPOSE: Embedded file metadata. Writes the post date, caption, and
+//          creator into downloaded files: EXIF/XMP for JPEGs (pure Go) and
+//          container tags for MP4/M4A/MOV (FFmpeg remux, streams copied).
+// =============================================================================
+
+package download
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gofscraper/internal/hash"
+	"gofscraper/internal/model"
+	"gofscraper/internal/paths"
+)
+
+// ---------------------------------------------------------------------------
+// Embed info
+// ---------------------------------------------------------------------------
+
+// maxEmbedTitle is the longest title derived from a caption, in runes.
+const maxEmbedTitle = 100
+
+// EmbedInfo is the metadata written into a file.
+type EmbedInfo struct {
+	Created time.Time // Post date (zero if unknown)
+	Title   string    // Post title, or the caption's first line
+	Caption string    // Post text with HTML stripped
+	Creator string    // Creator's username
+}
+
+// NewEmbedInfo builds the metadata for a media item from its parent post,
+// or from the values copied onto m when the post is not loaded.
+//
+// Parameters:
+//   - m: The downloaded media item.
+//
+// Returns:
+//   - The metadata to embed.
+func NewEmbedInfo(m *model.Media) EmbedInfo {
+	info := EmbedInfo{
+		Caption: strings.TrimSpace(model.DBCleanup(m.Text)),
+		Creator: m.Username,
+	}
+	if t, err := postTime(m); err == nil {
+		info.Created = t
+	}
+	if m.Post != nil && m.Post.Title != "" {
+		info.Title = m.Post.Title
+	} else {
+		info.Title = firstLine(info.Caption, maxEmbedTitle)
+	}
+	return info
+}
+
+// firstLine returns the first line of s, cut to at most n runes.
+func firstLine(s string, n int) string {
+	s, _, _ = strings.Cut(s, "\n")
+	s = strings.TrimSpace(s)
+	if r := []rune(s); len(r) > n {
+		s = strings.TrimSpace(string(r[:n]))
+	}
+	return s
+}
+
+// ---------------------------------------------------------------------------
+// Embed
+// ---------------------------------------------------------------------------
+
+// embedMetadata writes m's metadata into its file, then re-hashes it so
+// m.Hash and m.Size describe the file as saved. Formats other than JPEG
+// and MP4-family containers are left alone.
+func (o *Orchestrator) embedMetadata(ctx context.Context, m *model.Media) error {
+	info := NewEmbedInfo(m)
+	switch strings.ToLower(filepath.Ext(m.FilePath)) {
+	case ".jpg", ".jpeg":
+		if err := WriteJPEGMetadata(m.FilePath, info); err != nil {
+			return err
+		}
+	case ".mp4", ".m4a", ".m4v", ".mov":
+		if err := o.tagContainer(ctx, m.FilePath, info); err != nil {
+			return err
+		}
+	default:
+		return nil
+	}
+
+	fi, err := os.Stat(m.FilePath)
+	if err != nil {
+		return err
+	}
+	sum, err := hash.File(m.FilePath)
+	if err != nil {
+		return fmt.Errorf("hash file: %w", err)
+	}
+	m.Hash = sum
+	m.Size = float64(fi.Size())
+	return nil
+}
+
+// tagContainer sets creation_time, title, and comment on an MP4-family
+// file by remuxing it with FFmpeg to the working path and promoting the
+// result over the original.
+func (o *Orchestrator) tagContainer(ctx context.Context, path string, info EmbedInfo) error {
+	ffmpeg, err := FindFFmpeg(o.cfg.FFmpegPath)
+	if err != nil {
+		return err
+	}
+
+	tags := make(map[string]string)
+	if !info.Created.IsZero() {
+		tags["creation_time"] = info.Created.UTC().Format("2006-01-02T15:04:05.000000Z")
+	}
+	if info.Title != "" {
+		tags["title"] = info.Title
+	}
+	if info.Caption != "" {
+		tags["comment"] = info.Caption
+	}
+	if info.Creator != "" {
+		tags["artist"] = info.Creator
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+
+	// Keep the extension so FFmpeg picks the same muxer.
+	tmp := o.workPath(path, ".tag"+filepath.Ext(path))
+	if err := os.MkdirAll(filepath.Dir(tmp), 0o755); err != nil {
+		return fmt.Errorf("create temp directory: %w", err)
+	}
+	if err := TagMedia(ctx, ffmpeg, path, tmp, tags); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := paths.PromoteTemp(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("promote file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+// =============================================================================
+// FILE: internal/download/embed_test.go
+// PURPOSE: Tests for container tagging: the FFmpeg remux is written under
+//          temp_dir and promoted over the original, whose hash and size are
+//          updated, and a failed remux leaves the original untouched.
+// =============================================================================
+
+package download
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"gofscraper/internal/hash"
+	gohttp "gofscraper/internal/http"
+	"gofscraper/internal/model"
+)
+
+// fakeFFmpeg writes a script standing in for FFmpeg. It records its output
+// path in log, copies the -i input to the output, and appends each
+// -metadata value on its own line. With fail set it writes a partial output
+// and exits with an error.
+func fakeFFmpeg(t *testing.T, log string, fail bool) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake FFmpeg is a shell script")
+	}
+	script := `#!/bin/sh
+in=""; out=""; prev=""; meta=""
+for a in "$@"; do
+	[ "$prev" = "-i" ] && in="$a"
+	[ "$prev" = "-metadata" ] && meta="$meta$a
+"
+	prev="$a"; out="$a"
+done
+echo "$out" > '%s'
+cat "$in" > "$out"
+%s
+printf '%%s' "$meta" >> "$out"
+`
+	exit := ""
+	if fail {
+		exit = `echo "Invalid data found when processing input" >&2; exit 1`
+	}
+	path := filepath.Join(t.TempDir(), "ffmpeg")
+	if err := os.WriteFile(path, []byte(fmt.Sprintf(script, log, exit)), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestTagContainer(t *testing.T) {
+	for _, fail := range []bool{false, true} {
+		t.Run(fmt.Sprintf("fail=%v", fail), func(t *testing.T) {
+			log := filepath.Join(t.TempDir(), "ffmpeg.log")
+			cfg := DefaultConfig()
+			cfg.FFmpegPath = fakeFFmpeg(t, log, fail)
+			cfg.TempDir = t.TempDir()
+			o := NewOrchestrator(cfg, gohttp.New(nil))
+
+			original := []byte("ftyp mp4 video data")
+			path := filepath.Join(t.TempDir(), "model", "video.mp4")
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, original, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			m := &model.Media{
+				ID:       1,
+				Username: "creator",
+				Text:     "Beach day<br>second line",
+				FilePath: path,
+				Size:     float64(len(original)),
+				Post:     &model.Post{ID: 10, Title: "Summer"},
+			}
+
+			err := o.embedMetadata(context.Background(), m)
+
+			logged, lerr := os.ReadFile(log)
+			if lerr != nil {
+				t.Fatalf("fake FFmpeg did not run: %v", lerr)
+			}
+			tmp := strings.TrimSpace(string(logged))
+			if !strings.HasPrefix(tmp, cfg.TempDir+string(filepath.Separator)) || filepath.Ext(tmp) != ".mp4" {
+				t.Errorf("remux written to %s, want an .mp4 under temp_dir %s", tmp, cfg.TempDir)
+			}
+			if _, serr := os.Stat(tmp); !os.IsNotExist(serr) {
+				t.Errorf("remux output %s left behind (err %v)", tmp, serr)
+			}
+
+			got, rerr := os.ReadFile(path)
+			if rerr != nil {
+				t.Fatal(rerr)
+			}
+			if fail {
+				if err == nil {
+					t.Error("embedMetadata succeeded, want the FFmpeg error")
+				}
+				if string(got) != string(original) || m.Hash != "" || m.Size != float64(len(original)) {
+					t.Errorf("failed remux changed the file or media: %q hash %q size %v", got, m.Hash, m.Size)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("embedMetadata: %v", err)
+			}
+			for _, tag := range []string{"title=Summer", "artist=creator", "comment=Beach day"} {
+				if !strings.Contains(string(got), tag) {
+					t.Errorf("tagged file %q lacks %q", got, tag)
+				}
+			}
+			want, _ := hash.File(path)
+			if m.Hash != want || m.Size != float64(len(got)) {
+				t.Errorf("media hash %q size %v, want %q %d", m.Hash, m.Size, want, len(got))
+			}
+		})
+	}
+}
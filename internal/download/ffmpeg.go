@@ -11,6 +11,7 @@ import (
 	"context"
 	"fmt"
 	"os/exec"
+	"sort"
 	"strings"
 )
 
@@ -97,3 +98,35 @@ func ConcatFiles(ctx context.Context, ffmpegPath string, inputs []string, output
 	}
 	return nil
 }
+
+// TagMedia copies input to output with FFmpeg, keeping every stream and
+// existing tag, and sets the given container metadata tags.
+//
+// Parameters:
+//   - ctx: Context for cancellation.
+//   - ffmpegPath: Path to FFmpeg.
+//   - input: Input file path.
+//   - output: Output file path (same container as input).
+//   - tags: Metadata tags to set, e.g. "title", "comment", "creation_time".
+//
+// Returns:
+//   - Error if the operation fails.
+func TagMedia(ctx context.Context, ffmpegPath, input, output string, tags map[string]string) error {
+	args := []string{"-y", "-v", "error", "-i", input, "-map", "0", "-c", "copy", "-map_metadata", "0"}
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		args = append(args, "-metadata", k+"="+tags[k])
+	}
+	args = append(args, output)
+
+	cmd := exec.CommandContext(ctx, ffmpegPath, args...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("ffmpeg tag: %w\noutput: %s", err, string(out))
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+// =============================================================================
+// FILE: internal/download/integrity.go
+// PURPOSE: Download integrity checks. Verifies a finished .part file against
+//          the size the server announced, records its XXHash128, and promotes
+//          it from its working path to the final path.
+// =============================================================================
+
+package download
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"gofscraper/internal/hash"
+	gohttp "gofscraper/internal/http"
+	"gofscraper/internal/model"
+	"gofscraper/internal/paths"
+)
+
+// ---------------------------------------------------------------------------
+// Size errors
+// ---------------------------------------------------------------------------
+
+// SizeError is returned when a downloaded file's size differs from the
+// size the server announced.
+type SizeError struct {
+	Expected int64
+	Actual   int64
+}
+
+func (e *SizeError) Error() string {
+	return fmt.Sprintf("size mismatch: expected %d bytes, got %d", e.Expected, e.Actual)
+}
+
+// expectedSize returns the full file size announced by a download response:
+// the Content-Range total for a 206, else Content-Length. Returns -1 when
+// the server did not say.
+func expectedSize(resp *gohttp.Response) int64 {
+	if resp.StatusCode == 206 {
+		if size := contentRangeSize(resp.Headers.Get("Content-Range")); size > 0 {
+			return size
+		}
+		return -1
+	}
+	n, err := strconv.ParseInt(resp.Headers.Get("Content-Length"), 10, 64)
+	if err != nil || n < 0 {
+		return -1
+	}
+	return n
+}
+
+// ---------------------------------------------------------------------------
+// Finalize
+// ---------------------------------------------------------------------------
+
+// workPath returns where an in-progress file for outputPath is written:
+// under TempDir when staging is configured, else beside outputPath. suffix
+// is appended (".part" for HTTP downloads, "" where FFmpeg needs the real
+// extension).
+func (o *Orchestrator) workPath(outputPath, suffix string) string {
+	if o.cfg.TempDir == "" {
+		return outputPath + suffix
+	}
+	return paths.TempFilePath(o.cfg.TempDir, outputPath) + suffix
+}
+
+// finishFile checks the .part file against the expected size, hashes it
+// (unless sum was computed while streaming), and promotes it to outputPath.
+// On success m.Hash and m.Size describe the file on disk. A short file is
+// kept for resume; an oversized one is removed.
+//
+// Parameters:
+//   - m: The media item being downloaded.
+//   - partPath: The finished .part file.
+//   - outputPath: The final file path.
+//   - expected: The announced size, or -1 if unknown.
+//   - sum: The streamed hash of the whole file, or "" to hash from disk.
+//
+// Returns:
+//   - A *SizeError on mismatch, or any hashing or rename error.
+func (o *Orchestrator) finishFile(m *model.Media, partPath, outputPath string, expected int64, sum string) error {
+	info, err := os.Stat(partPath)
+	if err != nil {
+		return fmt.Errorf("stat file: %w", err)
+	}
+	if expected >= 0 && info.Size() != expected {
+		if info.Size() > expected {
+			os.Remove(partPath)
+		}
+		return &SizeError{Expected: expected, Actual: info.Size()}
+	}
+
+	if sum == "" {
+		if sum, err = hash.File(partPath); err != nil {
+			return fmt.Errorf("hash file: %w", err)
+		}
+	}
+
+	if err := paths.PromoteTemp(partPath, outputPath); err != nil {
+		return fmt.Errorf("promote file: %w", err)
+	}
+	m.Hash = sum
+	m.Size = float64(info.Size())
+	return nil
+}
@@ -0,0 +1,76 @@
+// =============================================================================
+// FILE: internal/download/integrity_test.go
+// PURPOSE: Tests for download integrity: a finished file is promoted with
+//          its hash only when its size matches the announced size; a short
+//          file is kept to resume and an oversized one is removed.
+// =============================================================================
+
+package download
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gofscraper/internal/hash"
+	"gofscraper/internal/model"
+)
+
+func TestFinishFile(t *testing.T) {
+	content := []byte("downloaded bytes")
+
+	tests := []struct {
+		name     string
+		expected int64
+		wantErr  bool
+		wantPart bool // .part file left for resume
+	}{
+		{name: "size matches", expected: int64(len(content))},
+		{name: "size unknown", expected: -1},
+		{name: "short file kept", expected: int64(len(content)) + 10, wantErr: true, wantPart: true},
+		{name: "oversized file removed", expected: int64(len(content)) - 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			out := filepath.Join(dir, "final", "1.jpg")
+			part := filepath.Join(dir, "1.jpg.part")
+			if err := os.WriteFile(part, content, 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			o := NewOrchestrator(DefaultConfig(), nil)
+			m := &model.Media{ID: 1}
+			err := o.finishFile(m, part, out, tt.expected, "")
+
+			var sizeErr *SizeError
+			if tt.wantErr {
+				if !errors.As(err, &sizeErr) || sizeErr.Actual != int64(len(content)) {
+					t.Fatalf("finishFile = %v, want a *SizeError", err)
+				}
+				if _, err := os.Stat(out); !os.IsNotExist(err) {
+					t.Error("mismatched file was promoted")
+				}
+				if _, err := os.Stat(part); (err == nil) != tt.wantPart {
+					t.Errorf(".part kept = %v, want %v", err == nil, tt.wantPart)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("finishFile: %v", err)
+			}
+			if got, err := os.ReadFile(out); err != nil || string(got) != string(content) {
+				t.Errorf("promoted file = %q, %v", got, err)
+			}
+			if _, err := os.Stat(part); !os.IsNotExist(err) {
+				t.Error(".part left after promotion")
+			}
+			if m.Hash != hash.Bytes(content) || m.Size != float64(len(content)) {
+				t.Errorf("media hash %q size %v, want the file's", m.Hash, m.Size)
+			}
+		})
+	}
+}
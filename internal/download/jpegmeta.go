@@ -0,0 +1,512 @@
+// =============================================================================
+// FILE: internal/download/jpegmeta.go
+// PURPOSE: Pure-Go JPEG metadata writer. Sets the post date and caption in
+//          a JPEG's EXIF segment, keeping its other entries (orientation,
+//          camera, GPS), and replaces its XMP segment with one carrying the
+//          date, caption, and creator, leaving the image data untouched.
+// =============================================================================
+
+package download
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"time"
+	"unicode/utf8"
+)
+
+// ---------------------------------------------------------------------------
+// Constants
+// ---------------------------------------------------------------------------
+
+const (
+	jpegSOI  = 0xD8
+	jpegSOS  = 0xDA
+	jpegEOI  = 0xD9
+	jpegAPP0 = 0xE0
+	jpegAPP1 = 0xE1
+
+	// jpegMaxSegment is the largest segment payload, including the two
+	// length bytes.
+	jpegMaxSegment = 0xFFFF
+
+	// jpegMaxCaption caps the caption embedded in each segment so the
+	// segments stay within jpegMaxSegment even after XML escaping.
+	jpegMaxCaption = 8000
+)
+
+var (
+	exifHeader = []byte("Exif\x00\x00")
+	xmpHeader  = []byte("http://ns.adobe.com/xap/1.0/\x00")
+)
+
+// EXIF tags and types used by buildExif.
+const (
+	tagImageDescription   = 0x010E
+	tagDateTime           = 0x0132
+	tagArtist             = 0x013B
+	tagExifIFD            = 0x8769
+	tagGPSIFD             = 0x8825
+	tagDateTimeOriginal   = 0x9003
+	tagOffsetTimeOriginal = 0x9011
+	tagInteropIFD         = 0xA005
+
+	exifASCII = 2
+	exifLong  = 4
+)
+
+// ---------------------------------------------------------------------------
+// Writer
+// ---------------------------------------------------------------------------
+
+// WriteJPEGMetadata rewrites the JPEG at path with metadata from info. The
+// existing EXIF entries are kept, with the original date and description
+// set from info; the XMP segment is replaced. All other segments and the
+// image data are kept byte for byte. The file is replaced atomically.
+//
+// Parameters:
+//   - path: The JPEG file.
+//   - info: The metadata to embed.
+//
+// Returns:
+//   - Error if the file is not a JPEG or cannot be rewritten.
+func WriteJPEGMetadata(path string, info EmbedInfo) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	out, err := rewriteJPEG(data, info)
+	if err != nil {
+		return err
+	}
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	tmp := filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+".meta")
+	if err := os.WriteFile(tmp, out, fi.Mode().Perm()); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// rewriteJPEG returns data with info merged into its EXIF segment and its
+// XMP segment replaced. An EXIF segment that cannot be parsed is replaced.
+func rewriteJPEG(data []byte, info EmbedInfo) ([]byte, error) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != jpegSOI {
+		return nil, fmt.Errorf("not a JPEG file")
+	}
+
+	var head, kept [][]byte // head holds leading APP0 (JFIF) segments
+	var existing exifIFDs
+	parsed := false
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != 0xFF {
+			return nil, fmt.Errorf("corrupt JPEG: no marker at offset %d", pos)
+		}
+		code := data[pos+1]
+		switch {
+		case code == 0xFF: // Fill byte
+			pos++
+			continue
+		case code == 0x01 || (code >= 0xD0 && code <= 0xD7): // No payload
+			kept = append(kept, data[pos:pos+2])
+			pos += 2
+			continue
+		case code == jpegSOS || code == jpegEOI:
+			// Entropy-coded data follows; keep the rest as is.
+			kept = append(kept, data[pos:])
+			pos = len(data)
+			continue
+		}
+
+		end := pos + 2 + int(binary.BigEndian.Uint16(data[pos+2:]))
+		if end > len(data) {
+			return nil, fmt.Errorf("corrupt JPEG: segment at offset %d overruns file", pos)
+		}
+		seg := data[pos:end]
+		payload := seg[4:]
+		switch {
+		case code == jpegAPP1 && bytes.HasPrefix(payload, exifHeader):
+			// Merged into the segment written below.
+			if !parsed {
+				if ifds, err := parseExif(payload[len(exifHeader):]); err == nil {
+					existing, parsed = ifds, true
+				}
+			}
+		case code == jpegAPP1 && bytes.HasPrefix(payload, xmpHeader):
+			// Dropped; replaced below.
+		case code == jpegAPP0 && len(kept) == 0:
+			head = append(head, seg)
+		default:
+			kept = append(kept, seg)
+		}
+		pos = end
+	}
+	if pos < len(data) {
+		kept = append(kept, data[pos:])
+	}
+
+	exif, err := jpegSegment(jpegAPP1, slices.Concat(exifHeader, buildExif(existing, info)))
+	if err != nil {
+		return nil, err
+	}
+	xmp, err := jpegSegment(jpegAPP1, slices.Concat(xmpHeader, buildXMP(info)))
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.Grow(len(data) + len(exif) + len(xmp))
+	buf.Write([]byte{0xFF, jpegSOI})
+	for _, seg := range head {
+		buf.Write(seg)
+	}
+	buf.Write(exif)
+	buf.Write(xmp)
+	for _, seg := range kept {
+		buf.Write(seg)
+	}
+	return buf.Bytes(), nil
+}
+
+// jpegSegment frames payload as a marker segment.
+func jpegSegment(code byte, payload []byte) ([]byte, error) {
+	if len(payload)+2 > jpegMaxSegment {
+		return nil, fmt.Errorf("metadata segment too large (%d bytes)", len(payload))
+	}
+	seg := make([]byte, 4, 4+len(payload))
+	seg[0], seg[1] = 0xFF, code
+	binary.BigEndian.PutUint16(seg[2:], uint16(len(payload)+2))
+	return append(seg, payload...), nil
+}
+
+// ---------------------------------------------------------------------------
+// EXIF
+// ---------------------------------------------------------------------------
+
+// exifEntry is one IFD entry with its raw big-endian value.
+type exifEntry struct {
+	tag   uint16
+	typ   uint16
+	count uint32
+	value []byte
+}
+
+// exifIFDs holds the entries of each IFD in an EXIF segment. Sub-IFD
+// pointers are not stored; encodeExif recreates them.
+type exifIFDs struct {
+	ifd0, exif, gps, interop []exifEntry
+}
+
+// exifString builds an ASCII entry.
+func exifString(tag uint16, s string) exifEntry {
+	v := append([]byte(s), 0)
+	return exifEntry{tag: tag, typ: exifASCII, count: uint32(len(v)), value: v}
+}
+
+// exifUint32 builds a LONG entry.
+func exifUint32(tag uint16, n uint32) exifEntry {
+	return exifEntry{tag: tag, typ: exifLong, count: 1, value: binary.BigEndian.AppendUint32(nil, n)}
+}
+
+// hasEntry reports whether entries contain tag.
+func hasEntry(entries []exifEntry, tag uint16) bool {
+	return slices.ContainsFunc(entries, func(e exifEntry) bool { return e.tag == tag })
+}
+
+// setEntry replaces the entry with e's tag, or adds e.
+func setEntry(entries []exifEntry, e exifEntry) []exifEntry {
+	if i := slices.IndexFunc(entries, func(x exifEntry) bool { return x.tag == e.tag }); i >= 0 {
+		entries[i] = e
+		return entries
+	}
+	return append(entries, e)
+}
+
+// buildExif encodes existing with the caption as the description and the
+// post date as the original date (with its offset). The artist and IFD0
+// date are only added when missing, so a camera's own values are kept.
+func buildExif(existing exifIFDs, info EmbedInfo) []byte {
+	ifds := existing
+	if caption := truncateUTF8(info.Caption, jpegMaxCaption); caption != "" {
+		ifds.ifd0 = setEntry(ifds.ifd0, exifString(tagImageDescription, caption))
+	}
+	if info.Creator != "" && !hasEntry(ifds.ifd0, tagArtist) {
+		ifds.ifd0 = append(ifds.ifd0, exifString(tagArtist, info.Creator))
+	}
+	if !info.Created.IsZero() {
+		stamp := info.Created.UTC().Format("2006:01:02 15:04:05")
+		if !hasEntry(ifds.ifd0, tagDateTime) {
+			ifds.ifd0 = append(ifds.ifd0, exifString(tagDateTime, stamp))
+		}
+		ifds.exif = setEntry(ifds.exif, exifString(tagDateTimeOriginal, stamp))
+		ifds.exif = setEntry(ifds.exif, exifString(tagOffsetTimeOriginal, "+00:00"))
+	}
+	return encodeExif(ifds)
+}
+
+// encodeExif encodes a big-endian TIFF structure holding IFD0 followed by
+// the Exif, Interop, and GPS IFDs that have entries.
+func encodeExif(ifds exifIFDs) []byte {
+	const tiffHeaderLen = 8
+
+	ifd0 := slices.Clone(ifds.ifd0)
+	exif := slices.Clone(ifds.exif)
+	if len(ifds.interop) > 0 {
+		exif = append(exif, exifUint32(tagInteropIFD, 0))
+	}
+	if len(exif) > 0 {
+		ifd0 = append(ifd0, exifUint32(tagExifIFD, 0))
+	}
+	if len(ifds.gps) > 0 {
+		ifd0 = append(ifd0, exifUint32(tagGPSIFD, 0))
+	}
+
+	// An IFD's size does not depend on its pointers' values, so encode
+	// once to lay the IFDs out, then again with the pointers set.
+	size := func(entries []exifEntry, start uint32) uint32 {
+		if len(entries) == 0 {
+			return 0
+		}
+		return uint32(len(encodeIFD(entries, start)))
+	}
+	exifOffset := tiffHeaderLen + size(ifd0, tiffHeaderLen)
+	interopOffset := exifOffset + size(exif, exifOffset)
+	gpsOffset := interopOffset + size(ifds.interop, interopOffset)
+	setPointer(ifd0, tagExifIFD, exifOffset)
+	setPointer(exif, tagInteropIFD, interopOffset)
+	setPointer(ifd0, tagGPSIFD, gpsOffset)
+
+	buf := []byte{'M', 'M', 0x00, 0x2A, 0x00, 0x00, 0x00, tiffHeaderLen}
+	buf = append(buf, encodeIFD(ifd0, tiffHeaderLen)...)
+	for _, sub := range []struct {
+		entries []exifEntry
+		start   uint32
+	}{{exif, exifOffset}, {ifds.interop, interopOffset}, {ifds.gps, gpsOffset}} {
+		if len(sub.entries) > 0 {
+			buf = append(buf, encodeIFD(sub.entries, sub.start)...)
+		}
+	}
+	return buf
+}
+
+// setPointer sets the offset of the sub-IFD pointer tag in entries.
+func setPointer(entries []exifEntry, tag uint16, offset uint32) {
+	for i := range entries {
+		if entries[i].tag == tag {
+			entries[i] = exifUint32(tag, offset)
+		}
+	}
+}
+
+// encodeIFD encodes entries as an IFD starting at TIFF offset start,
+// followed by the values too large to fit in an entry.
+func encodeIFD(entries []exifEntry, start uint32) []byte {
+	sort.Slice(entries, func(i, j int) bool { return entries[i].tag < entries[j].tag })
+
+	dataOffset := start + 2 + 12*uint32(len(entries)) + 4
+	var head, data []byte
+	head = binary.BigEndian.AppendUint16(head, uint16(len(entries)))
+	for _, e := range entries {
+		head = binary.BigEndian.AppendUint16(head, e.tag)
+		head = binary.BigEndian.AppendUint16(head, e.typ)
+		head = binary.BigEndian.AppendUint32(head, e.count)
+		if len(e.value) <= 4 {
+			v := make([]byte, 4)
+			copy(v, e.value)
+			head = append(head, v...)
+			continue
+		}
+		head = binary.BigEndian.AppendUint32(head, dataOffset+uint32(len(data)))
+		data = append(data, e.value...)
+		if len(data)%2 == 1 {
+			data = append(data, 0) // Keep offsets word-aligned
+		}
+	}
+	head = binary.BigEndian.AppendUint32(head, 0) // No next IFD
+	return append(head, data...)
+}
+
+// parseExif decodes the IFD0, Exif, GPS, and Interop IFDs of a TIFF
+// structure (the EXIF segment after its header) in either byte order.
+// IFD1 (the thumbnail) is not kept. Entries of unknown type or with
+// values outside the structure are skipped, as are unreadable sub-IFDs.
+func parseExif(tiff []byte) (exifIFDs, error) {
+	var ifds exifIFDs
+	if len(tiff) < 8 {
+		return ifds, fmt.Errorf("short TIFF header")
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return ifds, fmt.Errorf("unknown TIFF byte order %q", tiff[:2])
+	}
+	if order.Uint16(tiff[2:]) != 0x2A {
+		return ifds, fmt.Errorf("bad TIFF magic")
+	}
+
+	ifd0, pointers, err := readIFD(tiff, order, order.Uint32(tiff[4:]))
+	if err != nil {
+		return ifds, err
+	}
+	ifds.ifd0 = ifd0
+	if off, ok := pointers[tagExifIFD]; ok {
+		var sub map[uint16]uint32
+		if ifds.exif, sub, err = readIFD(tiff, order, off); err == nil {
+			if off, ok := sub[tagInteropIFD]; ok {
+				ifds.interop, _, _ = readIFD(tiff, order, off)
+			}
+		}
+	}
+	if off, ok := pointers[tagGPSIFD]; ok {
+		ifds.gps, _, _ = readIFD(tiff, order, off)
+	}
+	return ifds, nil
+}
+
+// readIFD decodes the IFD at offset, converting values to big-endian.
+// Sub-IFD pointers are returned by tag instead of as entries.
+func readIFD(tiff []byte, order binary.ByteOrder, offset uint32) ([]exifEntry, map[uint16]uint32, error) {
+	if uint64(offset)+2 > uint64(len(tiff)) {
+		return nil, nil, fmt.Errorf("IFD offset %d out of range", offset)
+	}
+	n := int(order.Uint16(tiff[offset:]))
+	start := int(offset) + 2
+	if start+12*n > len(tiff) {
+		return nil, nil, fmt.Errorf("IFD at %d overruns the segment", offset)
+	}
+
+	var entries []exifEntry
+	pointers := make(map[uint16]uint32)
+	for i := range n {
+		raw := tiff[start+12*i : start+12*i+12]
+		tag, typ, count := order.Uint16(raw), order.Uint16(raw[2:]), order.Uint32(raw[4:])
+		if tag == tagExifIFD || tag == tagGPSIFD || tag == tagInteropIFD {
+			pointers[tag] = order.Uint32(raw[8:])
+			continue
+		}
+		size, unit := exifTypeSize(typ)
+		if size == 0 {
+			continue
+		}
+		total := uint64(count) * uint64(size)
+		value := raw[8:]
+		if total > 4 {
+			off := uint64(order.Uint32(raw[8:]))
+			if off+total > uint64(len(tiff)) {
+				continue
+			}
+			value = tiff[off:]
+		}
+		entries = append(entries, exifEntry{
+			tag:   tag,
+			typ:   typ,
+			count: count,
+			value: toBigEndian(value[:total], unit, order),
+		})
+	}
+	return entries, pointers, nil
+}
+
+// exifTypeSize returns the byte size of one value of an EXIF type and the
+// size of the integers it is made of (rationals are two LONGs). Both are 0
+// for unknown types.
+func exifTypeSize(typ uint16) (size, unit int) {
+	switch typ {
+	case 1, 2, 6, 7: // BYTE, ASCII, SBYTE, UNDEFINED
+		return 1, 1
+	case 3, 8: // SHORT, SSHORT
+		return 2, 2
+	case 4, 9, 11: // LONG, SLONG, FLOAT
+		return 4, 4
+	case 5, 10: // RATIONAL, SRATIONAL
+		return 8, 4
+	case 12: // DOUBLE
+		return 8, 8
+	}
+	return 0, 0
+}
+
+// toBigEndian copies b, byte-swapping each unit-sized integer when order
+// is little-endian.
+func toBigEndian(b []byte, unit int, order binary.ByteOrder) []byte {
+	out := slices.Clone(b)
+	if order == binary.BigEndian || unit == 1 {
+		return out
+	}
+	for i := 0; i+unit <= len(out); i += unit {
+		slices.Reverse(out[i : i+unit])
+	}
+	return out
+}
+
+// ---------------------------------------------------------------------------
+// XMP
+// ---------------------------------------------------------------------------
+
+// buildXMP encodes an XMP packet with dc:creator, dc:title,
+// dc:description, and the creation date.
+func buildXMP(info EmbedInfo) []byte {
+	var b bytes.Buffer
+	b.WriteString("<?xpacket begin=\"\uFEFF\" id=\"W5M0MpCehiHzreSzNTczkc9d\"?>\n")
+	b.WriteString(`<x:xmpmeta xmlns:x="adobe:ns:meta/">` + "\n")
+	b.WriteString(`<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">` + "\n")
+	b.WriteString(`<rdf:Description rdf:about=""` +
+		` xmlns:dc="http://purl.org/dc/elements/1.1/"` +
+		` xmlns:xmp="http://ns.adobe.com/xap/1.0/"` +
+		` xmlns:photoshop="http://ns.adobe.com/photoshop/1.0/">` + "\n")
+
+	if info.Creator != "" {
+		b.WriteString("<dc:creator><rdf:Seq><rdf:li>")
+		xml.EscapeText(&b, []byte(info.Creator))
+		b.WriteString("</rdf:li></rdf:Seq></dc:creator>\n")
+	}
+	if info.Title != "" {
+		b.WriteString(`<dc:title><rdf:Alt><rdf:li xml:lang="x-default">`)
+		xml.EscapeText(&b, []byte(info.Title))
+		b.WriteString("</rdf:li></rdf:Alt></dc:title>\n")
+	}
+	if caption := truncateUTF8(info.Caption, jpegMaxCaption); caption != "" {
+		b.WriteString(`<dc:description><rdf:Alt><rdf:li xml:lang="x-default">`)
+		xml.EscapeText(&b, []byte(caption))
+		b.WriteString("</rdf:li></rdf:Alt></dc:description>\n")
+	}
+	if !info.Created.IsZero() {
+		stamp := info.Created.UTC().Format(time.RFC3339)
+		b.WriteString("<xmp:CreateDate>" + stamp + "</xmp:CreateDate>\n")
+		b.WriteString("<photoshop:DateCreated>" + stamp + "</photoshop:DateCreated>\n")
+	}
+
+	b.WriteString("</rdf:Description>\n</rdf:RDF>\n</x:xmpmeta>\n")
+	b.WriteString(`<?xpacket end="w"?>`)
+	return b.Bytes()
+}
+
+// truncateUTF8 shortens s to at most n bytes without splitting a rune.
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	s = s[:n]
+	for len(s) > 0 && !utf8.ValidString(s) {
+		s = s[:len(s)-1]
+	}
+	return s
+}
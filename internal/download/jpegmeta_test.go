@@ -0,0 +1,126 @@
+// =============================================================================
+// FILE: internal/download/jpegmeta_test.go
+// PURPOSE: Tests for the JPEG metadata writer: existing EXIF entries in
+//          either byte order survive, and only the original date,
+//          description, and missing artist are written.
+// =============================================================================
+
+package download
+
+import (
+	"bytes"
+	"encoding/binary"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRewriteJPEGKeepsExistingExif(t *testing.T) {
+	// A little-endian EXIF segment as a camera writes it.
+	le := binary.LittleEndian
+	tiff := []byte{'I', 'I', 0x2A, 0x00, 0x08, 0x00, 0x00, 0x00}
+	tiff = appendLEIFD(tiff, []exifEntry{
+		{tag: 0x010F, typ: exifASCII, count: 6, value: []byte("Canon\x00")},         // Make
+		{tag: 0x0112, typ: 3, count: 1, value: le.AppendUint16(nil, 6)},             // Orientation
+		{tag: tagExifIFD, typ: exifLong, count: 1, value: le.AppendUint32(nil, 68)}, // -> Exif IFD
+		{tag: tagGPSIFD, typ: exifLong, count: 1, value: le.AppendUint32(nil, 126)}, // -> GPS IFD
+	})
+	tiff = appendLEIFD(tiff, []exifEntry{
+		{tag: 0x829D, typ: 5, count: 1, value: le.AppendUint32(le.AppendUint32(nil, 28), 10)}, // FNumber f/2.8
+		{tag: tagDateTimeOriginal, typ: exifASCII, count: 20, value: []byte("2019:01:01 00:00:00\x00")},
+	})
+	tiff = appendLEIFD(tiff, []exifEntry{
+		{tag: 0x0001, typ: exifASCII, count: 2, value: []byte("N\x00")}, // GPSLatitudeRef
+	})
+
+	scan := []byte{0xFF, jpegSOS, 0x00, 0x02, 0x12, 0x34, 0xFF, jpegEOI}
+	exif, _ := jpegSegment(jpegAPP1, slices.Concat(exifHeader, tiff))
+	oldXMP, _ := jpegSegment(jpegAPP1, slices.Concat(xmpHeader, []byte("<old/>")))
+	jpeg := slices.Concat([]byte{0xFF, jpegSOI}, exif, oldXMP, scan)
+
+	out, err := rewriteJPEG(jpeg, EmbedInfo{
+		Created: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+		Caption: "Sunset",
+		Creator: "alice",
+	})
+	if err != nil {
+		t.Fatalf("rewriteJPEG: %v", err)
+	}
+	if !bytes.HasSuffix(out, scan) {
+		t.Error("image data changed")
+	}
+	if bytes.Contains(out, []byte("<old/>")) {
+		t.Error("old XMP packet kept")
+	}
+
+	// The new EXIF segment directly follows SOI.
+	n := int(binary.BigEndian.Uint16(out[4:]))
+	ifds, err := parseExif(out[4+2+len(exifHeader) : 4+n])
+	if err != nil {
+		t.Fatalf("parseExif: %v", err)
+	}
+
+	checks := []struct {
+		name    string
+		entries []exifEntry
+		tag     uint16
+		want    []byte
+	}{
+		{"make", ifds.ifd0, 0x010F, []byte("Canon\x00")},
+		{"orientation", ifds.ifd0, 0x0112, []byte{0x00, 0x06}},
+		{"description", ifds.ifd0, tagImageDescription, []byte("Sunset\x00")},
+		{"artist", ifds.ifd0, tagArtist, []byte("alice\x00")},
+		{"f-number", ifds.exif, 0x829D, []byte{0, 0, 0, 28, 0, 0, 0, 10}},
+		{"original date", ifds.exif, tagDateTimeOriginal, []byte("2024:05:01 10:00:00\x00")},
+		{"latitude ref", ifds.gps, 0x0001, []byte("N\x00")},
+	}
+	for _, c := range checks {
+		i := slices.IndexFunc(c.entries, func(e exifEntry) bool { return e.tag == c.tag })
+		if i < 0 {
+			t.Errorf("%s: entry missing", c.name)
+			continue
+		}
+		if got := c.entries[i].value; !bytes.Equal(got, c.want) {
+			t.Errorf("%s = %q, want %q", c.name, got, c.want)
+		}
+	}
+}
+
+func TestRewriteJPEGWithoutExif(t *testing.T) {
+	jpeg := []byte{0xFF, jpegSOI, 0xFF, jpegSOS, 0x00, 0x02, 0x12, 0x34, 0xFF, jpegEOI}
+	out, err := rewriteJPEG(jpeg, EmbedInfo{Created: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatalf("rewriteJPEG: %v", err)
+	}
+	n := int(binary.BigEndian.Uint16(out[4:]))
+	ifds, err := parseExif(out[4+2+len(exifHeader) : 4+n])
+	if err != nil {
+		t.Fatalf("parseExif: %v", err)
+	}
+	if !hasEntry(ifds.exif, tagDateTimeOriginal) || !hasEntry(ifds.ifd0, tagDateTime) {
+		t.Errorf("dates not written: %+v", ifds)
+	}
+}
+
+// appendLEIFD appends a little-endian IFD of entries, whose values are
+// already little-endian, followed by the values too large to inline.
+func appendLEIFD(buf []byte, entries []exifEntry) []byte {
+	le := binary.LittleEndian
+	dataOffset := uint32(len(buf)) + 2 + 12*uint32(len(entries)) + 4
+	var data []byte
+	buf = le.AppendUint16(buf, uint16(len(entries)))
+	for _, e := range entries {
+		buf = le.AppendUint16(buf, e.tag)
+		buf = le.AppendUint16(buf, e.typ)
+		buf = le.AppendUint32(buf, e.count)
+		if len(e.value) <= 4 {
+			buf = append(buf, make([]byte, 4)...)
+			copy(buf[len(buf)-4:], e.value)
+			continue
+		}
+		buf = le.AppendUint32(buf, dataOffset+uint32(len(data)))
+		data = append(data, e.value...)
+	}
+	buf = le.AppendUint32(buf, 0)
+	return append(buf, data...)
+}
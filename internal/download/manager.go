@@ -18,11 +18,16 @@ import (
 // DRM integration
 // ---------------------------------------------------------------------------
 
-// SetDRMManager configures the DRM manager for protected downloads.
+// SetDRMManager configures the DRM manager for protected downloads. Its
+// encrypted track downloads draw from the orchestrator's shared bandwidth
+// limiter.
 //
 // Parameters:
 //   - dm: The DRM manager to use.
 func (o *Orchestrator) SetDRMManager(dm *drm.Manager) {
+	if dm != nil {
+		dm.SetLimiter(o.cfg.Bandwidth)
+	}
 	o.drm = dm
 }
 
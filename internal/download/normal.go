@@ -2,7 +2,9 @@
 // FILE: internal/download/normal.go
 // PURPOSE: Normal HTTP download handler. Downloads unprotected media files
 //          using direct HTTP GET with chunked transfer, resume support, and
-//          progress tracking. Ports Python managers/main_download.py.
+//          progress tracking, handing large files to segmented downloads.
+//          Completed files are size-checked and hashed.
+//          Ports Python managers/main_download.py.
 // =============================================================================
 
 package download
@@ -11,9 +13,11 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"path/filepath"
 
+	"gofscraper/internal/hash"
 	gohttp "gofscraper/internal/http"
 	"gofscraper/internal/model"
 )
@@ -23,26 +27,48 @@ import (
 // ---------------------------------------------------------------------------
 
 // downloadNormal downloads a non-DRM media file via direct HTTP.
-func (o *Orchestrator) downloadNormal(ctx context.Context, m *model.Media) error {
+//
+// Returns:
+//   - Bytes written during this attempt, the HTTP status of the response
+//     they came from (206 for ranged and segmented downloads, 0 if none
+//     was received), and any error.
+func (o *Orchestrator) downloadNormal(ctx context.Context, m *model.Media) (int64, int, error) {
 	if m.RawURL == "" {
-		return fmt.Errorf("no download URL")
+		return 0, 0, fmt.Errorf("no download URL: %w", errUnavailable)
 	}
 
 	// Determine output path.
 	outputPath := m.FilePath
 	if outputPath == "" {
-		return fmt.Errorf("no output path set for media %d", m.ID)
+		return 0, 0, fmt.Errorf("no output path set for media %d: %w", m.ID, errUnavailable)
 	}
 
 	// Ensure parent directory exists.
 	if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil {
-		return fmt.Errorf("create directory: %w", err)
+		return 0, 0, fmt.Errorf("create directory: %w", err)
+	}
+
+	// Large files go over parallel range requests when the server allows.
+	partPath := o.workPath(outputPath, ".part")
+	if err := os.MkdirAll(filepath.Dir(partPath), 0o755); err != nil {
+		return 0, 0, fmt.Errorf("create temp directory: %w", err)
+	}
+	if written, size, handled, err := o.downloadSegmented(ctx, m, partPath); handled {
+		// Segments only accept 206 responses.
+		status := 0
+		if err == nil || written > 0 {
+			status = http.StatusPartialContent
+		}
+		if err != nil {
+			return written, status, err
+		}
+		return written, status, o.finishFile(m, partPath, outputPath, size, "")
 	}
 
 	// Check for resume.
 	var startByte int64
 	if o.cfg.ResumeEnabled {
-		if info, err := os.Stat(outputPath + ".part"); err == nil {
+		if info, err := os.Stat(partPath); err == nil {
 			startByte = info.Size()
 		}
 	}
@@ -55,18 +81,18 @@ func (o *Orchestrator) downloadNormal(ctx context.Context, m *model.Media) error
 
 	resp, err := o.session.Do(ctx, req)
 	if err != nil {
-		return fmt.Errorf("HTTP request: %w", err)
+		return 0, 0, fmt.Errorf("HTTP request: %w", err)
 	}
 	defer resp.Close()
 
-	if !resp.IsOK() && resp.StatusCode != 206 {
-		return fmt.Errorf("HTTP status: %d", resp.StatusCode)
+	status := resp.StatusCode
+	if !resp.IsOK() && status != http.StatusPartialContent {
+		return 0, status, &StatusError{StatusCode: status}
 	}
 
 	// Open output file.
-	partPath := outputPath + ".part"
 	flags := os.O_CREATE | os.O_WRONLY
-	if startByte > 0 && resp.StatusCode == 206 {
+	if startByte > 0 && status == http.StatusPartialContent {
 		flags |= os.O_APPEND
 	} else {
 		flags |= os.O_TRUNC
@@ -75,25 +101,32 @@ func (o *Orchestrator) downloadNormal(ctx context.Context, m *model.Media) error
 
 	f, err := os.OpenFile(partPath, flags, 0o644)
 	if err != nil {
-		return fmt.Errorf("open file: %w", err)
+		return 0, status, fmt.Errorf("open file: %w", err)
 	}
 	defer f.Close()
 
-	// Copy with optional speed limit.
-	var reader io.Reader = resp.Body
-	if o.cfg.SpeedLimit > 0 {
-		reader = NewSpeedLimitReader(resp.Body, o.cfg.SpeedLimit)
+	// Copy through the shared bandwidth limiter. A fresh download is hashed
+	// as it streams; a resumed one is hashed from disk once complete.
+	reader := o.cfg.Bandwidth.Reader(ctx, resp.Body)
+	var w io.Writer = f
+	var hasher *hash.Hasher
+	if startByte == 0 {
+		hasher = hash.New()
+		w = io.MultiWriter(f, hasher)
 	}
 
-	_, err = io.Copy(f, reader)
+	written, err := io.Copy(w, reader)
 	if err != nil {
-		return fmt.Errorf("write file: %w", err)
+		return written, status, fmt.Errorf("write file: %w", err)
 	}
-
-	// Promote .part to final path.
-	if err := os.Rename(partPath, outputPath); err != nil {
-		return fmt.Errorf("rename file: %w", err)
+	if err := f.Close(); err != nil {
+		return written, status, fmt.Errorf("close file: %w", err)
 	}
 
-	return nil
+	// Verify and promote .part to final path.
+	var sum string
+	if hasher != nil {
+		sum = hasher.Sum()
+	}
+	return written, status, o.finishFile(m, partPath, outputPath, expectedSize(resp), sum)
 }
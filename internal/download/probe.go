@@ -0,0 +1,175 @@
+// =============================================================================
+// FILE: internal/download/probe.go
+// PURPOSE: Post-download media probing. Reads the real duration, dimensions,
+//          codec, and bitrate of a downloaded file: ffprobe (found beside the
+//          configured FFmpeg) for video and audio, image.DecodeConfig for
+//          images.
+// =============================================================================
+
+package download
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"  // Register GIF for image.DecodeConfig
+	_ "image/jpeg" // Register JPEG for image.DecodeConfig
+	_ "image/png"  // Register PNG for image.DecodeConfig
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+
+	"gofscraper/internal/model"
+)
+
+// ---------------------------------------------------------------------------
+// ffprobe detection
+// ---------------------------------------------------------------------------
+
+// FindFFprobe locates ffprobe: beside the FFmpeg binary first, then on PATH.
+//
+// Parameters:
+//   - ffmpegPath: Configured FFmpeg path (may be empty).
+//
+// Returns:
+//   - The path to ffprobe, or error if not found.
+func FindFFprobe(ffmpegPath string) (string, error) {
+	name := "ffprobe"
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	if ffmpeg, err := FindFFmpeg(ffmpegPath); err == nil {
+		candidate := filepath.Join(filepath.Dir(ffmpeg), name)
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		}
+	}
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return "", fmt.Errorf("ffprobe not found beside ffmpeg or in PATH: %w", err)
+	}
+	return path, nil
+}
+
+// ---------------------------------------------------------------------------
+// Probing
+// ---------------------------------------------------------------------------
+
+// ffprobeOutput is the subset of `ffprobe -print_format json` output used.
+type ffprobeOutput struct {
+	Streams []struct {
+		CodecType string `json:"codec_type"`
+		CodecName string `json:"codec_name"`
+		Width     int    `json:"width"`
+		Height    int    `json:"height"`
+		BitRate   string `json:"bit_rate"`
+	} `json:"streams"`
+	Format struct {
+		Duration string `json:"duration"`
+		BitRate  string `json:"bit_rate"`
+	} `json:"format"`
+}
+
+// ProbeFile reads a video or audio file's properties with ffprobe. The
+// codec and dimensions are those of the first video stream, or the first
+// audio stream when there is no video.
+//
+// Parameters:
+//   - ctx: Context for cancellation.
+//   - ffprobePath: Path to ffprobe.
+//   - path: The file to probe.
+//
+// Returns:
+//   - The probed properties, or error.
+func ProbeFile(ctx context.Context, ffprobePath, path string) (*model.MediaInfo, error) {
+	cmd := exec.CommandContext(ctx, ffprobePath,
+		"-v", "error", "-print_format", "json", "-show_format", "-show_streams", path)
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("ffprobe: %w", err)
+	}
+
+	var probe ffprobeOutput
+	if err := json.Unmarshal(out, &probe); err != nil {
+		return nil, fmt.Errorf("parse ffprobe output: %w", err)
+	}
+
+	info := &model.MediaInfo{}
+	info.Duration, _ = strconv.ParseFloat(probe.Format.Duration, 64)
+	info.Bitrate, _ = strconv.ParseInt(probe.Format.BitRate, 10, 64)
+	for _, want := range []string{"video", "audio"} {
+		for _, s := range probe.Streams {
+			if s.CodecType != want {
+				continue
+			}
+			info.Codec = s.CodecName
+			info.Width, info.Height = s.Width, s.Height
+			if info.Bitrate == 0 {
+				info.Bitrate, _ = strconv.ParseInt(s.BitRate, 10, 64)
+			}
+			return info, nil
+		}
+	}
+	return info, nil
+}
+
+// ProbeImage reads an image's dimensions and format from its header.
+// JPEG, PNG, and GIF are supported.
+//
+// Parameters:
+//   - path: The image file.
+//
+// Returns:
+//   - The probed properties, or error if the format is not supported.
+func ProbeImage(path string) (*model.MediaInfo, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	cfg, format, err := image.DecodeConfig(f)
+	if err != nil {
+		return nil, fmt.Errorf("decode image header: %w", err)
+	}
+	return &model.MediaInfo{Width: cfg.Width, Height: cfg.Height, Codec: format}, nil
+}
+
+// probeMedia probes m's downloaded file and stores the result in m.Info.
+// ffprobe is looked up once per orchestrator; without it only images are
+// probed.
+func (o *Orchestrator) probeMedia(ctx context.Context, m *model.Media) error {
+	var (
+		info *model.MediaInfo
+		err  error
+	)
+	switch m.MediaType() {
+	case model.MediaTypeImages:
+		info, err = ProbeImage(m.FilePath)
+	case model.MediaTypeVideos, model.MediaTypeAudios:
+		o.ffprobeOnce.Do(func() {
+			o.ffprobe, err = FindFFprobe(o.cfg.FFmpegPath)
+			if err != nil && o.logger != nil {
+				o.logger.Warn("ffprobe unavailable, video and audio will not be probed", "error", err)
+			}
+		})
+		if o.ffprobe == "" {
+			return nil
+		}
+		info, err = ProbeFile(ctx, o.ffprobe, m.FilePath)
+	default:
+		return nil
+	}
+	if errors.Is(err, image.ErrFormat) {
+		return nil // An image format without a registered decoder
+	}
+	if err != nil {
+		return err
+	}
+	m.Info = info
+	return nil
+}
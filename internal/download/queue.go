@@ -0,0 +1,38 @@
+// =============================================================================
+// FILE: internal/download/queue.go
+// PURPOSE: Download queue hooks. Lets a durable queue follow each media item
+//          through a Run so that an interrupted run can be resumed.
+// =============================================================================
+
+package download
+
+import (
+	"context"
+
+	"gofscraper/internal/model"
+)
+
+// ---------------------------------------------------------------------------
+// Queue
+// ---------------------------------------------------------------------------
+
+// Queue is told when a worker starts and finishes each media item. Items
+// skipped or interrupted because the run was cancelled are never reported
+// finished, so the queue can hand them to the next run. Methods are called
+// concurrently from download workers.
+type Queue interface {
+	// Started is called when a worker picks up m, before downloading.
+	Started(ctx context.Context, m *model.Media)
+
+	// Finished is called once m has a final outcome, recorded in its
+	// download status.
+	Finished(ctx context.Context, m *model.Media)
+}
+
+// SetQueue configures the queue told about each item's progress.
+//
+// Parameters:
+//   - q: The queue, or nil to disable tracking.
+func (o *Orchestrator) SetQueue(q Queue) {
+	o.queue = q
+}
@@ -0,0 +1,320 @@
+// =============================================================================
+// FILE: internal/download/segmented.go
+// PURPOSE: Multi-connection segmented downloads. Splits large files into
+//          byte-range segments fetched in parallel and written at their
+//          offsets into the .part file, with per-segment progress kept in a
+//          sidecar so an interrupted download resumes only missing ranges.
+// =============================================================================
+
+package download
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	gohttp "gofscraper/internal/http"
+	"gofscraper/internal/model"
+)
+
+// ---------------------------------------------------------------------------
+// Segment state
+// ---------------------------------------------------------------------------
+
+// segmentSaveInterval limits how often segment progress is written to disk.
+const segmentSaveInterval = 2 * time.Second
+
+// errRangeIgnored is returned when the server answers a range request with
+// the full body.
+var errRangeIgnored = errors.New("server ignored range request")
+
+// segment is one byte range of a file. End is inclusive; Done counts bytes
+// already written from Start.
+type segment struct {
+	Start int64 `json:"start"`
+	End   int64 `json:"end"`
+	Done  int64 `json:"done"`
+}
+
+// remaining returns the bytes still to fetch.
+func (s *segment) remaining() int64 {
+	return s.End - s.Start + 1 - s.Done
+}
+
+// segmentState is the sidecar describing a segmented download in progress.
+type segmentState struct {
+	Size     int64      `json:"size"`
+	Segments []*segment `json:"segments"`
+
+	mu       sync.Mutex
+	path     string
+	lastSave time.Time
+}
+
+// segmentStatePath returns the sidecar path for a .part file.
+func segmentStatePath(partPath string) string {
+	return partPath + ".segments"
+}
+
+// newSegmentState splits size bytes into n near-equal segments.
+func newSegmentState(path string, size int64, n int) *segmentState {
+	st := &segmentState{Size: size, path: path}
+	step := size / int64(n)
+	for i := range n {
+		start := int64(i) * step
+		end := start + step - 1
+		if i == n-1 {
+			end = size - 1
+		}
+		st.Segments = append(st.Segments, &segment{Start: start, End: end})
+	}
+	return st
+}
+
+// loadSegmentState reads a sidecar. Returns nil if it is missing or invalid.
+func loadSegmentState(path string) *segmentState {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	st := &segmentState{path: path}
+	if err := json.Unmarshal(data, st); err != nil || st.Size <= 0 || len(st.Segments) == 0 {
+		return nil
+	}
+	return st
+}
+
+// advance records n more bytes written to seg and periodically saves the
+// sidecar.
+func (st *segmentState) advance(seg *segment, n int64) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	seg.Done += n
+	if time.Since(st.lastSave) >= segmentSaveInterval {
+		st.saveLocked()
+	}
+}
+
+// save writes the sidecar.
+func (st *segmentState) save() error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return st.saveLocked()
+}
+
+func (st *segmentState) saveLocked() error {
+	st.lastSave = time.Now()
+	data, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(st.path, data, 0o644)
+}
+
+// ---------------------------------------------------------------------------
+// Segmented download
+// ---------------------------------------------------------------------------
+
+// downloadSegmented downloads m to partPath over parallel range requests
+// when the file is large enough and the server supports ranges. handled is
+// false when the caller should fall back to a single stream; in that case
+// any leftover segmented .part file and sidecar have been removed.
+//
+// Returns:
+//   - Bytes written during this attempt, the full file size, whether the
+//     segmented path was used, and any error.
+func (o *Orchestrator) downloadSegmented(ctx context.Context, m *model.Media, partPath string) (written, size int64, handled bool, err error) {
+	statePath := segmentStatePath(partPath)
+	if o.cfg.Segments <= 1 {
+		if _, err := os.Stat(statePath); err == nil {
+			o.discardSegments(partPath, true)
+		}
+		return 0, 0, false, nil
+	}
+
+	var st *segmentState
+	if o.cfg.ResumeEnabled {
+		st = loadSegmentState(statePath)
+	}
+	if st == nil && m.Size > 0 && int64(m.Size) < o.cfg.SegmentMinSize {
+		return 0, 0, false, nil
+	}
+
+	size, err = o.probeSize(ctx, m.RawURL)
+	if err != nil {
+		return 0, 0, true, err
+	}
+	if size <= 0 || (st == nil && size < o.cfg.SegmentMinSize) {
+		o.discardSegments(partPath, st != nil)
+		return 0, 0, false, nil
+	}
+	if st != nil && st.Size != size {
+		st = nil
+	}
+
+	f, err := os.OpenFile(partPath, os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return 0, 0, true, fmt.Errorf("open file: %w", err)
+	}
+	defer f.Close()
+
+	if st == nil {
+		st = newSegmentState(statePath, size, o.cfg.Segments)
+		if err := f.Truncate(size); err != nil {
+			return 0, 0, true, fmt.Errorf("allocate file: %w", err)
+		}
+	}
+	if err := st.save(); err != nil {
+		return 0, 0, true, fmt.Errorf("write segment state: %w", err)
+	}
+
+	written, err = o.fetchSegments(ctx, m.RawURL, f, st)
+	if errors.Is(err, errRangeIgnored) {
+		f.Close()
+		o.discardSegments(partPath, true)
+		return 0, 0, false, nil
+	}
+	if err != nil {
+		st.save()
+		return written, size, true, err
+	}
+
+	if err := f.Close(); err != nil {
+		return written, size, true, fmt.Errorf("close file: %w", err)
+	}
+	os.Remove(statePath)
+	return written, size, true, nil
+}
+
+// fetchSegments downloads every unfinished segment concurrently. The first
+// failure cancels the remaining segments.
+func (o *Orchestrator) fetchSegments(ctx context.Context, rawURL string, f *os.File, st *segmentState) (int64, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var pending []*segment
+	for _, seg := range st.Segments {
+		if seg.remaining() > 0 {
+			pending = append(pending, seg)
+		}
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		total    int64
+		firstErr error
+	)
+	for _, seg := range pending {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			n, err := o.fetchSegment(ctx, rawURL, f, st, seg)
+			mu.Lock()
+			defer mu.Unlock()
+			total += n
+			if err != nil && firstErr == nil {
+				firstErr = err
+				cancel()
+			}
+		}()
+	}
+	wg.Wait()
+	return total, firstErr
+}
+
+// fetchSegment downloads the rest of one segment and writes it at its
+// offset.
+func (o *Orchestrator) fetchSegment(ctx context.Context, rawURL string, f *os.File, st *segmentState, seg *segment) (int64, error) {
+	from := seg.Start + seg.Done
+	req := gohttp.NewRequest(rawURL).
+		WithHeader("Range", fmt.Sprintf("bytes=%d-%d", from, seg.End))
+
+	resp, err := o.session.Do(ctx, req)
+	if err != nil {
+		return 0, fmt.Errorf("HTTP request: %w", err)
+	}
+	defer resp.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		return 0, errRangeIgnored
+	case resp.StatusCode != http.StatusPartialContent:
+		return 0, &StatusError{StatusCode: resp.StatusCode}
+	}
+
+	reader := o.cfg.Bandwidth.Reader(ctx, io.LimitReader(resp.Body, seg.remaining()))
+
+	w := io.NewOffsetWriter(f, from)
+	buf := make([]byte, 256*1024)
+	var written int64
+	for {
+		n, rerr := reader.Read(buf)
+		if n > 0 {
+			if _, werr := w.Write(buf[:n]); werr != nil {
+				return written, fmt.Errorf("write file: %w", werr)
+			}
+			written += int64(n)
+			st.advance(seg, int64(n))
+		}
+		if rerr == io.EOF {
+			break
+		}
+		if rerr != nil {
+			return written, fmt.Errorf("read segment: %w", rerr)
+		}
+	}
+	if seg.remaining() > 0 {
+		return written, fmt.Errorf("segment %d-%d ended early: %w", seg.Start, seg.End, io.ErrUnexpectedEOF)
+	}
+	return written, nil
+}
+
+// probeSize asks for the first byte to learn the file size and whether the
+// server honours ranges. Returns 0 when it does not.
+func (o *Orchestrator) probeSize(ctx context.Context, rawURL string) (int64, error) {
+	resp, err := o.session.Do(ctx, gohttp.NewRequest(rawURL).WithHeader("Range", "bytes=0-0"))
+	if err != nil {
+		return 0, fmt.Errorf("HTTP request: %w", err)
+	}
+	defer resp.Close()
+
+	if resp.StatusCode != http.StatusPartialContent {
+		if !resp.IsOK() {
+			return 0, &StatusError{StatusCode: resp.StatusCode}
+		}
+		return 0, nil
+	}
+	return contentRangeSize(resp.Headers.Get("Content-Range")), nil
+}
+
+// contentRangeSize parses the total from "bytes 0-0/12345". Returns 0 if
+// the total is missing or unknown ("*").
+func contentRangeSize(header string) int64 {
+	_, total, ok := strings.Cut(header, "/")
+	if !ok {
+		return 0
+	}
+	size, err := strconv.ParseInt(strings.TrimSpace(total), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return size
+}
+
+// discardSegments removes the sidecar and, when it was a segmented
+// download, the preallocated .part file a single stream cannot resume.
+func (o *Orchestrator) discardSegments(partPath string, hadState bool) {
+	os.Remove(segmentStatePath(partPath))
+	if hadState {
+		os.Remove(partPath)
+	}
+}
@@ -0,0 +1,222 @@
+// =============================================================================
+// FILE: internal/download/segmented_test.go
+// PURPOSE: Tests for segmented downloads: an interrupted download keeps its
+//          progress in the .segments sidecar, and the next attempt requests
+//          only the missing ranges and assembles the complete file.
+// =============================================================================
+
+package download
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"gofscraper/internal/api/apitest"
+	gohttp "gofscraper/internal/http"
+	"gofscraper/internal/model"
+)
+
+// rangeServer serves body at /files/1.bin and records each Range header
+// other than the size probe's, by the attempt named in the "try" query
+// parameter. The first request for failRange is cut off after failAfter
+// bytes.
+type rangeServer struct {
+	mu     sync.Mutex
+	ranges map[string][]string
+	failed bool
+}
+
+func (rs *rangeServer) handler(body []byte, failRange string, failAfter int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rng := r.Header.Get("Range")
+		rs.mu.Lock()
+		if rng != "bytes=0-0" {
+			if rs.ranges == nil {
+				rs.ranges = make(map[string][]string)
+			}
+			try := r.URL.Query().Get("try")
+			rs.ranges[try] = append(rs.ranges[try], rng)
+		}
+		fail := rng == failRange && !rs.failed
+		if fail {
+			rs.failed = true
+		}
+		rs.mu.Unlock()
+
+		if fail {
+			var start, end int
+			fmt.Sscanf(rng, "bytes=%d-%d", &start, &end)
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, len(body)))
+			w.Header().Set("Content-Length", fmt.Sprint(end-start+1))
+			w.WriteHeader(http.StatusPartialContent)
+			w.Write(body[start : start+failAfter])
+			w.(http.Flusher).Flush()
+			panic(http.ErrAbortHandler)
+		}
+		http.ServeContent(w, r, "1.bin", time.Time{}, bytes.NewReader(body))
+	}
+}
+
+// rangesOf returns the ranges requested by one attempt, sorted. Requests
+// of a cancelled attempt may still arrive after it returned, so attempts
+// are told apart by URL.
+func (rs *rangeServer) rangesOf(try string) []string {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	got := slices.Clone(rs.ranges[try])
+	slices.Sort(got)
+	return got
+}
+
+// pendingRanges returns the Range headers that fetch what st is missing.
+func pendingRanges(st *segmentState) []string {
+	var want []string
+	for _, seg := range st.Segments {
+		if seg.remaining() > 0 {
+			want = append(want, fmt.Sprintf("bytes=%d-%d", seg.Start+seg.Done, seg.End))
+		}
+	}
+	slices.Sort(want)
+	return want
+}
+
+func newSegmentOrchestrator(segments int) *Orchestrator {
+	cfg := DefaultConfig()
+	cfg.Workers = 1
+	cfg.Segments = segments
+	cfg.SegmentMinSize = 1
+	return NewOrchestrator(cfg, gohttp.New(nil))
+}
+
+func TestSegmentedResumesInterruptedDownload(t *testing.T) {
+	body := bytes.Repeat([]byte("0123456789abcdef"), 8192) // 128 KiB
+	partPath := filepath.Join(t.TempDir(), "1.bin.part")
+	statePath := segmentStatePath(partPath)
+
+	// The third of four segments is cut off 1000 bytes in.
+	seg := int64(len(body) / 4)
+	failRange := fmt.Sprintf("bytes=%d-%d", 2*seg, 3*seg-1)
+	rs := &rangeServer{}
+	srv := apitest.NewServer(t)
+	url := srv.Handle("/files/1.bin", rs.handler(body, failRange, 1000))
+	m := &model.Media{ID: 1, RawURL: url + "?try=1"}
+
+	o := newSegmentOrchestrator(4)
+	if _, _, handled, err := o.downloadSegmented(context.Background(), m, partPath); !handled || err == nil {
+		t.Fatalf("first attempt: handled %v, err %v; want a handled failure", handled, err)
+	}
+
+	st := loadSegmentState(statePath)
+	if st == nil {
+		t.Fatal("no segment sidecar after the interrupted attempt")
+	}
+	if st.Size != int64(len(body)) || len(st.Segments) != 4 {
+		t.Fatalf("sidecar = size %d, %d segments", st.Size, len(st.Segments))
+	}
+	if done := st.Segments[2].Done; done != 1000 {
+		t.Errorf("interrupted segment done = %d, want 1000", done)
+	}
+	want := pendingRanges(st)
+
+	m.RawURL = url + "?try=2"
+	written, size, handled, err := o.downloadSegmented(context.Background(), m, partPath)
+	if !handled || err != nil {
+		t.Fatalf("resumed attempt: handled %v, err %v", handled, err)
+	}
+	if size != int64(len(body)) {
+		t.Errorf("size = %d, want %d", size, len(body))
+	}
+	if got := rs.rangesOf("2"); !slices.Equal(got, want) {
+		t.Errorf("resumed ranges = %q, want only the missing %q", got, want)
+	}
+	var missing int64
+	for _, s := range st.Segments {
+		missing += s.remaining()
+	}
+	if written != missing {
+		t.Errorf("resumed attempt wrote %d bytes, want the %d missing", written, missing)
+	}
+
+	if got, err := os.ReadFile(partPath); err != nil || !bytes.Equal(got, body) {
+		t.Errorf("assembled file differs from the source (err %v)", err)
+	}
+	if _, err := os.Stat(statePath); !os.IsNotExist(err) {
+		t.Errorf("sidecar kept after completion: %v", err)
+	}
+}
+
+func TestSegmentedResumeFromSidecar(t *testing.T) {
+	body := bytes.Repeat([]byte("fedcba9876543210"), 8192)
+	partPath := filepath.Join(t.TempDir(), "1.bin.part")
+	statePath := segmentStatePath(partPath)
+
+	// A sidecar left by an earlier process: segments 0 and 3 finished,
+	// segment 1 half done, segment 2 not started.
+	st := newSegmentState(statePath, int64(len(body)), 4)
+	st.Segments[0].Done = st.Segments[0].End - st.Segments[0].Start + 1
+	st.Segments[1].Done = (st.Segments[1].End - st.Segments[1].Start + 1) / 2
+	st.Segments[3].Done = st.Segments[3].End - st.Segments[3].Start + 1
+	part := make([]byte, len(body))
+	for _, s := range st.Segments {
+		copy(part[s.Start:s.Start+s.Done], body[s.Start:])
+	}
+	if err := os.WriteFile(partPath, part, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.save(); err != nil {
+		t.Fatal(err)
+	}
+
+	rs := &rangeServer{}
+	srv := apitest.NewServer(t)
+	m := &model.Media{ID: 1, RawURL: srv.Handle("/files/1.bin", rs.handler(body, "", 0))}
+
+	o := newSegmentOrchestrator(4)
+	if _, _, handled, err := o.downloadSegmented(context.Background(), m, partPath); !handled || err != nil {
+		t.Fatalf("downloadSegmented: handled %v, err %v", handled, err)
+	}
+	if got, want := rs.rangesOf(""), pendingRanges(st); !slices.Equal(got, want) {
+		t.Errorf("ranges = %q, want only the missing %q", got, want)
+	}
+	if got, err := os.ReadFile(partPath); err != nil || !bytes.Equal(got, body) {
+		t.Errorf("assembled file differs from the source (err %v)", err)
+	}
+}
+
+func TestSegmentedSidecarForOtherSizeRestarts(t *testing.T) {
+	body := bytes.Repeat([]byte("x"), 64*1024)
+	partPath := filepath.Join(t.TempDir(), "1.bin.part")
+
+	// The file changed size on the server since the sidecar was written.
+	st := newSegmentState(segmentStatePath(partPath), int64(len(body))*2, 4)
+	st.Segments[0].Done = 100
+	if err := st.save(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(partPath, make([]byte, len(body)*2), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	rs := &rangeServer{}
+	srv := apitest.NewServer(t)
+	m := &model.Media{ID: 1, RawURL: srv.Handle("/files/1.bin", rs.handler(body, "", 0))}
+
+	o := newSegmentOrchestrator(4)
+	if _, _, handled, err := o.downloadSegmented(context.Background(), m, partPath); !handled || err != nil {
+		t.Fatalf("downloadSegmented: handled %v, err %v", handled, err)
+	}
+	if got, want := rs.rangesOf(""), pendingRanges(newSegmentState("", int64(len(body)), 4)); !slices.Equal(got, want) {
+		t.Errorf("ranges = %q, want every segment %q", got, want)
+	}
+	if got, err := os.ReadFile(partPath); err != nil || !bytes.Equal(got, body) {
+		t.Errorf("file differs from the source (err %v)", err)
+	}
+}
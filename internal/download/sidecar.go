@@ -0,0 +1,153 @@
+// =============================================================================
+// FILE: internal/download/sidecar.go
+// PURPOSE: File metadata for completed downloads. Sets a file's timestamps
+//          to its post date and writes a <file>.json sidecar carrying the
+//          post context (caption, price, labels, area, position), so the
+//          context survives outside the database.
+// =============================================================================
+
+package download
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"gofscraper/internal/model"
+	"gofscraper/internal/utils"
+)
+
+// ---------------------------------------------------------------------------
+// Sidecar
+// ---------------------------------------------------------------------------
+
+// SidecarExt is appended to a media file's name to form its sidecar path.
+const SidecarExt = ".json"
+
+// Sidecar is the JSON document written beside a downloaded file.
+type Sidecar struct {
+	MediaID   int64    `json:"media_id"`
+	PostID    int64    `json:"post_id"`
+	ModelID   int64    `json:"model_id"`
+	Username  string   `json:"username"`
+	Area      string   `json:"area"`  // Response type, e.g. "timeline"
+	Index     int      `json:"index"` // 1-based position in the post
+	MediaType string   `json:"media_type"`
+	Label     string   `json:"label,omitempty"`
+	Title     string   `json:"title,omitempty"`
+	Text      string   `json:"text,omitempty"`
+	Price     *float64 `json:"price,omitempty"` // Unknown when the post is not loaded
+	Paid      bool     `json:"paid"`
+	Value     string   `json:"value"` // "free" or "paid"
+	PostedAt  string   `json:"posted_at,omitempty"`
+	CreatedAt string   `json:"created_at,omitempty"`
+	Duration  string   `json:"duration,omitempty"`
+	Size      int64    `json:"size"`
+	Hash      string   `json:"hash,omitempty"`
+
+	Info *model.MediaInfo `json:"info,omitempty"` // Probed properties
+}
+
+// NewSidecar builds the sidecar for a downloaded media item. Post fields
+// come from m.Post when it is linked, else from the values copied onto m.
+//
+// Parameters:
+//   - m: The downloaded media item.
+//
+// Returns:
+//   - The sidecar document.
+func NewSidecar(m *model.Media) Sidecar {
+	sc := Sidecar{
+		MediaID:   m.ID,
+		PostID:    m.PostID,
+		ModelID:   m.ModelID,
+		Username:  m.Username,
+		Area:      m.ResponseType,
+		Index:     m.Count,
+		MediaType: string(m.MediaType()),
+		Label:     m.Label,
+		Text:      m.Text,
+		Paid:      m.Value == "paid",
+		Value:     m.Value,
+		PostedAt:  m.PostedAt,
+		CreatedAt: m.CreatedAt,
+		Duration:  m.Duration,
+		Size:      int64(m.Size),
+		Hash:      m.Hash,
+		Info:      m.Info,
+	}
+	if p := m.Post; p != nil {
+		price := p.Price
+		sc.Price = &price
+		sc.Paid = p.Paid
+		sc.Title = p.Title
+	}
+	return sc
+}
+
+// ---------------------------------------------------------------------------
+// Apply
+// ---------------------------------------------------------------------------
+
+// applyFileMetadata embeds metadata into m's file, probes it, writes its
+// sidecar, and sets its timestamps, as configured. Embedding runs first
+// because it changes the file's hash and resets its times. Failures are
+// logged; the download itself has succeeded.
+func (o *Orchestrator) applyFileMetadata(ctx context.Context, m *model.Media) {
+	if o.cfg.EmbedMetadata {
+		if err := o.embedMetadata(ctx, m); err != nil && o.logger != nil {
+			o.logger.Warn("failed to embed metadata", "media_id", m.ID, "error", err)
+		}
+	}
+	if o.cfg.ProbeMedia {
+		if err := o.probeMedia(ctx, m); err != nil && o.logger != nil {
+			o.logger.Warn("failed to probe media", "media_id", m.ID, "error", err)
+		}
+	}
+	if o.cfg.WriteSidecars {
+		if err := writeSidecar(m); err != nil && o.logger != nil {
+			o.logger.Warn("failed to write sidecar", "media_id", m.ID, "error", err)
+		}
+	}
+	if o.cfg.SetFileTimes {
+		if err := setFileTimes(m); err != nil && o.logger != nil {
+			o.logger.Warn("failed to set file times", "media_id", m.ID, "error", err)
+		}
+	}
+}
+
+// writeSidecar writes m's sidecar beside its file.
+func writeSidecar(m *model.Media) error {
+	data, err := json.MarshalIndent(NewSidecar(m), "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode sidecar: %w", err)
+	}
+	return os.WriteFile(m.FilePath+SidecarExt, append(data, '\n'), 0o644)
+}
+
+// setFileTimes sets the access and modification times of m's file, and of
+// its sidecar if present, to the post date.
+func setFileTimes(m *model.Media) error {
+	t, err := postTime(m)
+	if err != nil {
+		return err
+	}
+	if err := os.Chtimes(m.FilePath, t, t); err != nil {
+		return err
+	}
+	if err := os.Chtimes(m.FilePath+SidecarExt, t, t); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// postTime returns m's post date, falling back to its own date.
+func postTime(m *model.Media) (time.Time, error) {
+	raw := m.PostedAt
+	if raw == "" {
+		raw = m.Date()
+	}
+	return utils.ParseFlexibleDate(raw)
+}
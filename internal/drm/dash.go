@@ -64,6 +64,7 @@ type SegmentTemplate struct {
 	Initialization string `xml:"initialization,attr"`
 	Timescale      int    `xml:"timescale,attr"`
 	Duration       int    `xml:"duration,attr"`
+	StartNumber    int    `xml:"startNumber,attr"` // First $Number$; 0 means 1
 }
 
 // ---------------------------------------------------------------------------
@@ -2,8 +2,9 @@
 // FILE: internal/drm/drm.go
 // PURPOSE: DRM coordinator. Manages the DRM decryption pipeline: detects
 //          protected content, fetches manifests, obtains decryption keys,
-//          and delegates to FFmpeg for final decryption. Ports Python
-//          alt_download + keyhelpers logic.
+//          downloads the encrypted tracks through the shared bandwidth
+//          limiter, and delegates to FFmpeg for final decryption. Ports
+//          Python alt_download + keyhelpers logic.
 // =============================================================================
 
 package drm
@@ -12,6 +13,8 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"net/http"
+	"os"
 )
 
 // ---------------------------------------------------------------------------
@@ -54,9 +57,10 @@ func DefaultConfig() Config {
 
 // Manager coordinates DRM decryption operations.
 type Manager struct {
-	cfg    Config
-	cdrm   *CDRMClient
-	logger *slog.Logger
+	cfg     Config
+	cdrm    *CDRMClient
+	logger  *slog.Logger
+	limiter Limiter // Throttles encrypted track downloads
 }
 
 // NewManager creates a DRM manager with the given config.
@@ -68,8 +72,9 @@ type Manager struct {
 //   - A configured Manager.
 func NewManager(cfg Config) *Manager {
 	m := &Manager{
-		cfg:    cfg,
-		logger: cfg.Logger,
+		cfg:     cfg,
+		logger:  cfg.Logger,
+		limiter: unlimited{},
 	}
 
 	if cfg.Mode == ModeCDRM && cfg.CDRMServer != "" {
@@ -79,6 +84,18 @@ func NewManager(cfg Config) *Manager {
 	return m
 }
 
+// SetLimiter sets the limiter encrypted track downloads are read through,
+// normally the downloaders' shared bandwidth limiter. Nil removes it.
+//
+// Parameters:
+//   - l: The limiter.
+func (m *Manager) SetLimiter(l Limiter) {
+	if l == nil {
+		l = unlimited{}
+	}
+	m.limiter = l
+}
+
 // ---------------------------------------------------------------------------
 // Decryption
 // ---------------------------------------------------------------------------
@@ -88,6 +105,7 @@ type DecryptResult struct {
 	OutputPath string // Path to the decrypted file
 	Key        string // Decryption key used (hex)
 	KID        string // Key ID (hex)
+	HTTPStatus int    // Status of the last encrypted content response
 }
 
 // Decrypt handles the full DRM decryption pipeline for a media item.
@@ -135,8 +153,38 @@ func (m *Manager) Decrypt(ctx context.Context, mpdURL, licenseURL, outputPath st
 		return nil, fmt.Errorf("obtain key: %w", err)
 	}
 
-	// Step 4: Download and decrypt with FFmpeg.
-	err = FFmpegDecrypt(ctx, m.cfg.FFmpegPath, mpdURL, key, kid, outputPath)
+	// Step 4: Download the best video and audio tracks next to the output.
+	var inputs []string
+	defer func() {
+		for _, in := range inputs {
+			os.Remove(in)
+		}
+	}()
+	var status int
+	for _, track := range []struct {
+		name string
+		reps []Representation
+	}{
+		{"video", manifest.VideoRepresentations()},
+		{"audio", manifest.AudioRepresentations()},
+	} {
+		rep, ok := bestRepresentation(track.reps)
+		if !ok {
+			continue
+		}
+		path := outputPath + "." + track.name + ".enc"
+		inputs = append(inputs, path)
+		status, err = fetchTrack(ctx, http.DefaultClient, m.limiter, manifest, rep, path)
+		if err != nil {
+			return nil, fmt.Errorf("download %s track: %w", track.name, err)
+		}
+	}
+	if len(inputs) == 0 {
+		return nil, fmt.Errorf("no video or audio representation in manifest")
+	}
+
+	// Step 5: Decrypt and mux with FFmpeg.
+	err = FFmpegDecrypt(ctx, m.cfg.FFmpegPath, inputs, key, kid, outputPath)
 	if err != nil {
 		return nil, fmt.Errorf("ffmpeg decrypt: %w", err)
 	}
@@ -145,6 +193,7 @@ func (m *Manager) Decrypt(ctx context.Context, mpdURL, licenseURL, outputPath st
 		OutputPath: outputPath,
 		Key:        key,
 		KID:        kid,
+		HTTPStatus: status,
 	}, nil
 }
 
@@ -0,0 +1,191 @@
+// =============================================================================
+// FILE: internal/drm/fetch.go
+// PURPOSE: Encrypted track download. Fetches the chosen video and audio
+//          representations of a DASH manifest, whole-file or segment by
+//          segment, through the shared download bandwidth limiter so
+//          protected media count against the same limit as other downloads.
+// =============================================================================
+
+package drm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ---------------------------------------------------------------------------
+// Limiter
+// ---------------------------------------------------------------------------
+
+// Limiter throttles reads. The download package's shared Bandwidth
+// implements it.
+type Limiter interface {
+	// Reader returns r limited by the shared rate; ctx cancels waiting.
+	Reader(ctx context.Context, r io.Reader) io.Reader
+}
+
+// unlimited is the Limiter used when none is configured.
+type unlimited struct{}
+
+func (unlimited) Reader(_ context.Context, r io.Reader) io.Reader { return r }
+
+// ---------------------------------------------------------------------------
+// Track selection
+// ---------------------------------------------------------------------------
+
+// maxSegments bounds a template track whose segments never run out.
+const maxSegments = 100000
+
+// bestRepresentation returns the representation with the highest
+// bandwidth, or false if there is none.
+func bestRepresentation(reps []Representation) (Representation, bool) {
+	if len(reps) == 0 {
+		return Representation{}, false
+	}
+	best := reps[0]
+	for _, r := range reps[1:] {
+		if r.Bandwidth > best.Bandwidth {
+			best = r
+		}
+	}
+	return best, true
+}
+
+// ---------------------------------------------------------------------------
+// Track download
+// ---------------------------------------------------------------------------
+
+// fetchTrack downloads one representation's encrypted stream to path: its
+// BaseURL as a single file, or its SegmentTemplate's initialization segment
+// followed by numbered media segments until the server answers 404.
+//
+// Parameters:
+//   - ctx: Context for cancellation.
+//   - client: HTTP client for the requests.
+//   - limiter: Limiter every response body is read through.
+//   - manifest: The manifest the representation belongs to.
+//   - rep: The representation to download.
+//   - path: Destination file for the encrypted stream.
+//
+// Returns:
+//   - The HTTP status of the last response used, and any error.
+func fetchTrack(ctx context.Context, client *http.Client, limiter Limiter, manifest *Manifest, rep Representation, path string) (int, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return 0, fmt.Errorf("create track file: %w", err)
+	}
+	defer f.Close()
+
+	status, err := fetchTrackTo(ctx, client, limiter, manifest, rep, f)
+	if err != nil {
+		return status, err
+	}
+	if err := f.Close(); err != nil {
+		return status, fmt.Errorf("close track file: %w", err)
+	}
+	return status, nil
+}
+
+// fetchTrackTo writes the representation's stream to w.
+func fetchTrackTo(ctx context.Context, client *http.Client, limiter Limiter, manifest *Manifest, rep Representation, w io.Writer) (int, error) {
+	if rep.BaseURL != "" {
+		return fetchSegment(ctx, client, limiter, manifest.resolve(rep.BaseURL), w)
+	}
+
+	tmpl := rep.SegmentTemplate
+	if tmpl == nil || tmpl.Media == "" {
+		return 0, fmt.Errorf("representation %s has no BaseURL or SegmentTemplate", rep.ID)
+	}
+
+	var status int
+	if tmpl.Initialization != "" {
+		s, err := fetchSegment(ctx, client, limiter, manifest.resolve(expandTemplate(tmpl.Initialization, rep, 0)), w)
+		if err != nil {
+			return s, fmt.Errorf("initialization segment: %w", err)
+		}
+		status = s
+	}
+
+	start := tmpl.StartNumber
+	if start == 0 {
+		start = 1
+	}
+	for n := start; n < start+maxSegments; n++ {
+		s, err := fetchSegment(ctx, client, limiter, manifest.resolve(expandTemplate(tmpl.Media, rep, n)), w)
+		var se *segmentStatusError
+		if errors.As(err, &se) && se.status == http.StatusNotFound && n > start {
+			// The segment past the last one.
+			return status, nil
+		}
+		if err != nil {
+			return s, fmt.Errorf("segment %d: %w", n, err)
+		}
+		status = s
+	}
+	return status, fmt.Errorf("track exceeds %d segments", maxSegments)
+}
+
+// segmentStatusError is a segment response with an unexpected status.
+type segmentStatusError struct {
+	status int
+}
+
+func (e *segmentStatusError) Error() string {
+	return fmt.Sprintf("segment fetch status: %d", e.status)
+}
+
+// fetchSegment appends the body at rawURL to w through the limiter.
+func fetchSegment(ctx context.Context, client *http.Client, limiter Limiter, rawURL string, w io.Writer) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return 0, fmt.Errorf("create request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("fetch segment: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		return resp.StatusCode, &segmentStatusError{status: resp.StatusCode}
+	}
+	if _, err := io.Copy(w, limiter.Reader(ctx, resp.Body)); err != nil {
+		return resp.StatusCode, fmt.Errorf("read segment: %w", err)
+	}
+	return resp.StatusCode, nil
+}
+
+// ---------------------------------------------------------------------------
+// URL helpers
+// ---------------------------------------------------------------------------
+
+// resolve resolves a manifest-relative URL against the manifest's base.
+func (m *Manifest) resolve(ref string) string {
+	base, err := url.Parse(m.BaseURL)
+	if err != nil {
+		return ref
+	}
+	r, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return base.ResolveReference(r).String()
+}
+
+// expandTemplate fills a segment URL template's $RepresentationID$,
+// $Bandwidth$ and $Number$ identifiers.
+func expandTemplate(s string, rep Representation, number int) string {
+	return strings.NewReplacer(
+		"$RepresentationID$", rep.ID,
+		"$Bandwidth$", strconv.Itoa(rep.Bandwidth),
+		"$Number$", strconv.Itoa(number),
+		"$$", "$",
+	).Replace(s)
+}
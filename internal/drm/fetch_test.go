@@ -0,0 +1,97 @@
+// =============================================================================
+// FILE: internal/drm/fetch_test.go
+// PURPOSE: Tests for encrypted track download: every segment of a template
+//          or BaseURL track is read through the configured limiter.
+// =============================================================================
+
+package drm
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+)
+
+// countingLimiter counts the readers it wraps.
+type countingLimiter struct {
+	readers atomic.Int32
+}
+
+func (l *countingLimiter) Reader(_ context.Context, r io.Reader) io.Reader {
+	l.readers.Add(1)
+	return r
+}
+
+func TestFetchTrack(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v/init.mp4", "/v/seg-1.m4s", "/v/seg-2.m4s", "/v/seg-3.m4s", "/v/full.mp4":
+			fmt.Fprint(w, r.URL.Path)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	manifest := &Manifest{BaseURL: srv.URL + "/v/"}
+
+	tests := []struct {
+		name        string
+		rep         Representation
+		want        string
+		wantReaders int32
+	}{
+		{
+			name: "segment template until 404",
+			rep: Representation{ID: "v1", SegmentTemplate: &SegmentTemplate{
+				Initialization: "init.mp4",
+				Media:          "seg-$Number$.m4s",
+			}},
+			want:        "/v/init.mp4/v/seg-1.m4s/v/seg-2.m4s/v/seg-3.m4s",
+			wantReaders: 4,
+		},
+		{
+			name:        "single BaseURL file",
+			rep:         Representation{ID: "v2", BaseURL: "full.mp4"},
+			want:        "/v/full.mp4",
+			wantReaders: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := &countingLimiter{}
+			path := filepath.Join(t.TempDir(), "track.enc")
+			status, err := fetchTrack(context.Background(), srv.Client(), limiter, manifest, tt.rep, path)
+			if err != nil {
+				t.Fatalf("fetchTrack: %v", err)
+			}
+			if status != http.StatusOK {
+				t.Errorf("status = %d, want 200", status)
+			}
+			got, err := os.ReadFile(path)
+			if err != nil || string(got) != tt.want {
+				t.Errorf("track = %q (err %v), want %q", got, err, tt.want)
+			}
+			if n := limiter.readers.Load(); n != tt.wantReaders {
+				t.Errorf("limited readers = %d, want %d", n, tt.wantReaders)
+			}
+		})
+	}
+}
+
+func TestFetchTrackMissingFirstSegment(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	rep := Representation{ID: "v1", SegmentTemplate: &SegmentTemplate{Media: "seg-$Number$.m4s"}}
+	_, err := fetchTrack(context.Background(), srv.Client(), unlimited{}, &Manifest{BaseURL: srv.URL + "/"}, rep, filepath.Join(t.TempDir(), "track.enc"))
+	if err == nil {
+		t.Fatal("fetchTrack: want error when the first segment is missing")
+	}
+}
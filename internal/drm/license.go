@@ -11,6 +11,7 @@ import (
 	"context"
 	"fmt"
 	"os/exec"
+	"strconv"
 	"strings"
 	"sync"
 )
@@ -87,29 +88,30 @@ func BuildLicenseURL(template string, mediaID int64, drmType string) string {
 // FFmpeg decryption
 // ---------------------------------------------------------------------------
 
-// FFmpegDecrypt downloads and decrypts DRM content using FFmpeg with the
-// given decryption key.
+// FFmpegDecrypt decrypts downloaded DRM tracks with FFmpeg, muxing them
+// into one output file.
 //
 // Parameters:
 //   - ctx: Context for cancellation.
 //   - ffmpegPath: Path to the FFmpeg binary.
-//   - inputURL: The DASH manifest or encrypted content URL.
+//   - inputs: The encrypted track files (e.g., video and audio).
 //   - key: The decryption key (hex).
 //   - kid: The key ID (hex).
 //   - outputPath: Path for the decrypted output file.
 //
 // Returns:
 //   - Error if the operation fails.
-func FFmpegDecrypt(ctx context.Context, ffmpegPath, inputURL, key, kid, outputPath string) error {
+func FFmpegDecrypt(ctx context.Context, ffmpegPath string, inputs []string, key, kid, outputPath string) error {
 	decryptionKey := fmt.Sprintf("%s:%s", kid, key)
 
-	args := []string{
-		"-y",
-		"-decryption_key", decryptionKey,
-		"-i", inputURL,
-		"-c", "copy",
-		outputPath,
+	args := []string{"-y"}
+	for _, in := range inputs {
+		args = append(args, "-decryption_key", decryptionKey, "-i", in)
 	}
+	for i := range inputs {
+		args = append(args, "-map", strconv.Itoa(i))
+	}
+	args = append(args, "-c", "copy", outputPath)
 
 	cmd := exec.CommandContext(ctx, ffmpegPath, args...)
 	output, err := cmd.CombinedOutput()
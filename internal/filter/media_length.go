@@ -20,7 +20,8 @@ import (
 // ---------------------------------------------------------------------------
 
 // ByMediaLength returns a filter that keeps only media within the given
-// duration range. Media without a parseable duration is included by default.
+// duration range. The probed duration is preferred over the API's. Media
+// without a known duration is included by default.
 //
 // Parameters:
 //   - minLen: Minimum duration (zero = no lower bound).
@@ -36,7 +37,7 @@ func ByMediaLength(minLen, maxLen time.Duration) MediaFilter {
 	return func(media []*model.Media) []*model.Media {
 		var result []*model.Media
 		for _, m := range media {
-			dur := parseDuration(m.Duration)
+			dur := mediaDuration(m)
 			if dur < 0 {
 				// Unparseable — include by default.
 				result = append(result, m)
@@ -54,6 +55,15 @@ func ByMediaLength(minLen, maxLen time.Duration) MediaFilter {
 	}
 }
 
+// mediaDuration returns m's probed duration if known, else the API's.
+// Returns -1 if neither is available.
+func mediaDuration(m *model.Media) time.Duration {
+	if m.Info != nil && m.Info.Duration > 0 {
+		return time.Duration(m.Info.Duration * float64(time.Second))
+	}
+	return parseDuration(m.Duration)
+}
+
 // parseDuration attempts to parse a duration string in formats like
 // "123" (seconds), "1:23" (mm:ss), "1:23:45" (hh:mm:ss).
 // Returns -1 if the string can't be parsed.
@@ -0,0 +1,40 @@
+// =============================================================================
+// FILE: internal/filter/media_retry.go
+// PURPOSE: Retry-failed filter. Keeps only media whose most recent download
+//          attempt failed (based on a set of failed media IDs).
+// =============================================================================
+
+package filter
+
+import (
+	"gofscraper/internal/model"
+)
+
+// ---------------------------------------------------------------------------
+// Retry-failed filter
+// ---------------------------------------------------------------------------
+
+// ByRetryFailed returns a filter that keeps only media whose last download
+// attempt failed. An empty failedIDs set keeps nothing.
+//
+// Parameters:
+//   - failedIDs: Set of media IDs whose last attempt failed.
+//   - retryFailed: Whether to actually filter. If false, returns nil.
+//
+// Returns:
+//   - A MediaFilter, or nil if retryFailed is false.
+func ByRetryFailed(failedIDs map[int64]bool, retryFailed bool) MediaFilter {
+	if !retryFailed {
+		return nil
+	}
+
+	return func(media []*model.Media) []*model.Media {
+		var result []*model.Media
+		for _, m := range media {
+			if failedIDs[m.ID] {
+				result = append(result, m)
+			}
+		}
+		return result
+	}
+}
@@ -1,7 +1,8 @@
 // =============================================================================
 // FILE: internal/filter/media_sort.go
 // PURPOSE: Media sort filter. Sorts media by various criteria (date, ID, type,
-//          size). Ports Python filters/media/filters.py final_media_sort.
+//          size, duration, resolution, bitrate). Ports Python
+//          filters/media/filters.py final_media_sort.
 // =============================================================================
 
 package filter
@@ -21,7 +22,9 @@ import (
 // SortMedia returns a filter that sorts media by the given key and direction.
 //
 // Parameters:
-//   - sortBy: Sort key ("date", "id", "type", "size", "random"). Empty = no sort.
+//   - sortBy: Sort key ("date", "id", "type", "size", "duration",
+//     "resolution", "bitrate"). Empty = no sort. Media without a known
+//     value sort first.
 //   - descending: If true, sort in descending order.
 //
 // Returns:
@@ -63,7 +66,21 @@ func compareMedia(a, b *model.Media, key string) bool {
 		return a.Type < b.Type
 	case "size":
 		return a.Size < b.Size
+	case "duration", "length":
+		return mediaDuration(a) < mediaDuration(b)
+	case "resolution":
+		return a.Info.Pixels() < b.Info.Pixels()
+	case "bitrate":
+		return mediaBitrate(a) < mediaBitrate(b)
 	default:
 		return a.ID < b.ID
 	}
 }
+
+// mediaBitrate returns m's probed bitrate, or 0 if unknown.
+func mediaBitrate(m *model.Media) int64 {
+	if m.Info == nil {
+		return 0
+	}
+	return m.Info.Bitrate
+}
@@ -36,6 +36,7 @@ func ByMediaType(allowedTypes []string) MediaFilter {
 		switch strings.ToLower(t) {
 		case "images", "image", "photo", "photos":
 			allowed["photo"] = true
+			allowed["gif"] = true
 		case "videos", "video":
 			allowed["video"] = true
 		case "audios", "audio":
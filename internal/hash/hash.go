@@ -8,6 +8,7 @@
 package hash
 
 import (
+	"encoding/binary"
 	"encoding/hex"
 	"fmt"
 	"io"
@@ -66,27 +67,7 @@ func Reader(r io.Reader) (string, error) {
 		}
 	}
 
-	sum := h.Sum128()
-	// Encode as 32-char hex string (128 bits = 16 bytes).
-	var hashBytes [16]byte
-	hashBytes[0] = byte(sum.Lo >> 56)
-	hashBytes[1] = byte(sum.Lo >> 48)
-	hashBytes[2] = byte(sum.Lo >> 40)
-	hashBytes[3] = byte(sum.Lo >> 32)
-	hashBytes[4] = byte(sum.Lo >> 24)
-	hashBytes[5] = byte(sum.Lo >> 16)
-	hashBytes[6] = byte(sum.Lo >> 8)
-	hashBytes[7] = byte(sum.Lo)
-	hashBytes[8] = byte(sum.Hi >> 56)
-	hashBytes[9] = byte(sum.Hi >> 48)
-	hashBytes[10] = byte(sum.Hi >> 40)
-	hashBytes[11] = byte(sum.Hi >> 32)
-	hashBytes[12] = byte(sum.Hi >> 24)
-	hashBytes[13] = byte(sum.Hi >> 16)
-	hashBytes[14] = byte(sum.Hi >> 8)
-	hashBytes[15] = byte(sum.Hi)
-
-	return hex.EncodeToString(hashBytes[:]), nil
+	return encode(h.Sum128()), nil
 }
 
 // Bytes computes the XXHash128 of a byte slice.
@@ -99,29 +80,48 @@ func Reader(r io.Reader) (string, error) {
 func Bytes(data []byte) string {
 	h := xxh3.New()
 	_, _ = h.Write(data)
-	sum := h.Sum128()
+	return encode(h.Sum128())
+}
 
+// encode formats a 128-bit hash as a 32-char hex string (low half first).
+func encode(sum xxh3.Uint128) string {
 	var hashBytes [16]byte
-	hashBytes[0] = byte(sum.Lo >> 56)
-	hashBytes[1] = byte(sum.Lo >> 48)
-	hashBytes[2] = byte(sum.Lo >> 40)
-	hashBytes[3] = byte(sum.Lo >> 32)
-	hashBytes[4] = byte(sum.Lo >> 24)
-	hashBytes[5] = byte(sum.Lo >> 16)
-	hashBytes[6] = byte(sum.Lo >> 8)
-	hashBytes[7] = byte(sum.Lo)
-	hashBytes[8] = byte(sum.Hi >> 56)
-	hashBytes[9] = byte(sum.Hi >> 48)
-	hashBytes[10] = byte(sum.Hi >> 40)
-	hashBytes[11] = byte(sum.Hi >> 32)
-	hashBytes[12] = byte(sum.Hi >> 24)
-	hashBytes[13] = byte(sum.Hi >> 16)
-	hashBytes[14] = byte(sum.Hi >> 8)
-	hashBytes[15] = byte(sum.Hi)
-
+	binary.BigEndian.PutUint64(hashBytes[:8], sum.Lo)
+	binary.BigEndian.PutUint64(hashBytes[8:], sum.Hi)
 	return hex.EncodeToString(hashBytes[:])
 }
 
+// ---------------------------------------------------------------------------
+// Streaming hasher
+// ---------------------------------------------------------------------------
+
+// Hasher computes the XXHash128 of data written to it, so a download can be
+// hashed while it streams to disk. The result matches File and Reader.
+type Hasher struct {
+	h *xxh3.Hasher
+}
+
+// New creates an empty streaming hasher.
+//
+// Returns:
+//   - A new Hasher.
+func New() *Hasher {
+	return &Hasher{h: xxh3.New()}
+}
+
+// Write adds p to the hash. It never returns an error.
+func (h *Hasher) Write(p []byte) (int, error) {
+	return h.h.Write(p)
+}
+
+// Sum returns the hex-encoded hash of everything written so far.
+//
+// Returns:
+//   - The hex-encoded 128-bit hash string.
+func (h *Hasher) Sum() string {
+	return encode(h.h.Sum128())
+}
+
 // ---------------------------------------------------------------------------
 // Deduplication
 // ---------------------------------------------------------------------------
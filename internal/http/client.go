@@ -14,6 +14,7 @@ import (
 	"log/slog"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"gofscraper/internal/auth"
@@ -32,9 +33,9 @@ type SessionManager struct {
 	sleeper   *AdaptiveSleeper
 	mu        sync.RWMutex
 
-	// Request counters for stats.
-	totalRequests int64
-	failedRequests int64
+	// Request counters for stats; Do is called from concurrent workers.
+	totalRequests  atomic.Int64
+	failedRequests atomic.Int64
 }
 
 // New creates a SessionManager with the given auth credentials.
@@ -104,12 +105,12 @@ func (sm *SessionManager) Do(ctx context.Context, req *Request) (*Response, erro
 		httpReq.Header.Set("Cookie", auth.MakeCookies(sm.authData))
 	}
 
-	sm.totalRequests++
+	sm.totalRequests.Add(1)
 
 	// Execute request.
 	resp, err := sm.client.Do(httpReq)
 	if err != nil {
-		sm.failedRequests++
+		sm.failedRequests.Add(1)
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 
@@ -175,7 +176,7 @@ func (sm *SessionManager) UpdateAuth(data *auth.Data) {
 
 // Stats returns request statistics.
 func (sm *SessionManager) Stats() (total, failed int64) {
-	return sm.totalRequests, sm.failedRequests
+	return sm.totalRequests.Load(), sm.failedRequests.Load()
 }
 
 // ---------------------------------------------------------------------------
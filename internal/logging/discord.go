@@ -196,7 +196,31 @@ type discordPayload struct {
 // Returns:
 //   - Error if the HTTP request fails.
 func postToDiscord(url, content string) error {
-	payload := discordPayload{Content: "```\n" + content + "\n```"}
+	return postDiscordContent(url, "```\n"+content+"\n```")
+}
+
+// SendDiscord posts a Discord-formatted message to a webhook as is, split
+// into chunks Discord accepts. Unlike log records, the message is not
+// wrapped in a code block, so its markdown is rendered.
+//
+// Parameters:
+//   - url: The Discord webhook URL.
+//   - message: The message text.
+//
+// Returns:
+//   - Error from the first chunk that fails to send.
+func SendDiscord(url, message string) error {
+	for _, chunk := range chunkString(message, maxDiscordMessageLen) {
+		if err := postDiscordContent(url, chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// postDiscordContent posts content to the webhook URL unchanged.
+func postDiscordContent(url, content string) error {
+	payload := discordPayload{Content: content}
 	body, err := json.Marshal(payload)
 	if err != nil {
 		return err
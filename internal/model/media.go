@@ -119,8 +119,15 @@ type Media struct {
 	// --- Filepath (set after path resolution) ---
 	FilePath string `json:"filepath,omitempty"`
 
-	// --- Quality selection cache ---
-	SelectedQuality string `json:"selected_quality,omitempty"`
+	// --- Integrity (set after a successful download) ---
+	Hash string `json:"hash,omitempty"` // XXHash128 of the downloaded file
+
+	// --- Probed properties (set after download, or loaded from the DB) ---
+	Info *MediaInfo `json:"info,omitempty"`
+
+	// --- Quality selection ---
+	Variants        []MediaVariant `json:"variants,omitempty"`         // Every rendition, best first
+	SelectedQuality string         `json:"selected_quality,omitempty"` // Quality of the variant in RawURL
 
 	// --- Internal sync ---
 	mu sync.Mutex `json:"-"`
@@ -0,0 +1,33 @@
+// =============================================================================
+// FILE: internal/model/mediainfo.go
+// PURPOSE: Probed media properties. Holds the duration, dimensions, codec,
+//          and bitrate read from a downloaded file, which are more reliable
+//          than the values the API reports.
+// =============================================================================
+
+package model
+
+// ---------------------------------------------------------------------------
+// MediaInfo
+// ---------------------------------------------------------------------------
+
+// MediaInfo describes a downloaded file as probed from disk. Zero fields
+// are unknown.
+type MediaInfo struct {
+	Duration float64 `json:"duration,omitempty"` // Seconds (video and audio)
+	Width    int     `json:"width,omitempty"`    // Pixels
+	Height   int     `json:"height,omitempty"`   // Pixels
+	Codec    string  `json:"codec,omitempty"`    // e.g. "h264", "aac", "jpeg"
+	Bitrate  int64   `json:"bitrate,omitempty"`  // Bits per second (video and audio)
+}
+
+// Pixels returns the frame area, or 0 if the dimensions are unknown.
+//
+// Returns:
+//   - Width times height.
+func (i *MediaInfo) Pixels() int {
+	if i == nil {
+		return 0
+	}
+	return i.Width * i.Height
+}
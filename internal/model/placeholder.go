@@ -11,6 +11,7 @@ package model
 import (
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 )
@@ -49,6 +50,12 @@ const (
 	VarRegularPrice    = "regular_price"
 	VarPromoPrice      = "promo_price"
 	VarRenewalPrice    = "renewal_price"
+
+	// Short aliases used by the default dir, file, and metadata templates.
+	VarResponseTypeAlias = "responsetype"
+	VarMediaTypeAlias    = "mediatype"
+	VarFilenameAlias     = "filename" // URL filename without its extension
+	VarConfigPathAlias   = "configpath"
 )
 
 // ---------------------------------------------------------------------------
@@ -196,6 +203,7 @@ func (mp *MediaPlaceholder) SetMediaVariables(username string, modelID int64, qu
 
 	// Media type (capitalized)
 	mp.Context.Set(VarMediaType, strings.Title(string(m.MediaType())))
+	mp.Context.Set(VarMediaTypeAlias, strings.Title(string(m.MediaType())))
 
 	// Value: "Free" or "Paid"
 	if m.Value == "paid" {
@@ -206,6 +214,7 @@ func (mp *MediaPlaceholder) SetMediaVariables(username string, modelID int64, qu
 
 	// Response type
 	mp.Context.Set(VarResponseType, m.ResponseType)
+	mp.Context.Set(VarResponseTypeAlias, strings.Title(m.ResponseType))
 
 	// Label
 	mp.Context.Set(VarLabel, m.Label)
@@ -222,10 +231,13 @@ func (mp *MediaPlaceholder) SetMediaVariables(username string, modelID int64, qu
 	mp.Context.Set(VarOnlyFilename, origFilename)
 
 	// Filename with quality suffix
+	stem := strings.TrimSuffix(origFilename, path.Ext(origFilename))
 	if quality != "" && quality != "source" {
 		mp.Context.Set(VarFilename, origFilename+"_"+quality)
+		mp.Context.Set(VarFilenameAlias, stem+"_"+quality)
 	} else {
 		mp.Context.Set(VarFilename, origFilename)
+		mp.Context.Set(VarFilenameAlias, stem)
 	}
 
 	// Text (from parent post, cleaned for filenames)
@@ -344,6 +356,7 @@ func NewDatabasePlaceholder() *DatabasePlaceholder {
 //   - The resolved database file path string.
 func (dp *DatabasePlaceholder) DatabasePath(metadataFormat, configPath, profile string, modelID int64, modelUsername string) string {
 	dp.Context.Set(VarConfigPath, configPath)
+	dp.Context.Set(VarConfigPathAlias, configPath)
 	dp.Context.Set(VarProfile, profile)
 	dp.Context.Set(VarModelID, fmt.Sprintf("%d", modelID))
 	dp.Context.Set(VarModelUsername, modelUsername)
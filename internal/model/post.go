@@ -172,6 +172,27 @@ func (p *Post) ViewableMedia() []*Media {
 	return result
 }
 
+// LinkMedia sets each media item's parent reference and copies the
+// post-level fields (owner, dates, area, value, text) down onto it. Call it
+// once the post's Username and ResponseType are final.
+func (p *Post) LinkMedia() {
+	for i, m := range p.AllMedia {
+		m.Post = p
+		m.Count = i + 1
+		m.Username = p.Username
+		m.ModelID = p.ModelID
+		m.PostedAt = p.Date()
+		m.ResponseType = string(p.ResponseType)
+		m.Label = p.Label
+		m.Value = p.Value()
+		m.Mass = p.Mass
+		m.Text = p.RawText
+		if p.Preview {
+			m.Preview = 1
+		}
+	}
+}
+
 // DBText returns the post text sanitized for database storage.
 // Uses DBCleanup to strip HTML and normalize whitespace.
 //
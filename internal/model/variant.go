@@ -0,0 +1,127 @@
+// =============================================================================
+// FILE: internal/model/variant.go
+// PURPOSE: Media quality variants. Holds every rendition the API lists for a
+//          media item (source, 720p, 240p, ...) and selects one from a
+//          preference list. Ports Python classes/of/media.py quality logic.
+// =============================================================================
+
+package model
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ---------------------------------------------------------------------------
+// Variants
+// ---------------------------------------------------------------------------
+
+// QualitySource names the original, full-quality rendition.
+const QualitySource = "source"
+
+// qualityAliases maps the legacy --quality names onto API renditions.
+var qualityAliases = map[string]string{
+	"original": QualitySource,
+	"high":     QualitySource,
+	"medium":   "720",
+	"low":      "240",
+}
+
+// MediaVariant is one rendition of a media item.
+type MediaVariant struct {
+	Quality string  `json:"quality"`        // "source", or a height such as "720"
+	URL     string  `json:"url"`            // Direct download URL
+	Size    float64 `json:"size,omitempty"` // Size in bytes, if the API gave it
+}
+
+// NormalizeQuality canonicalizes a quality name: lower case, without a
+// trailing "p", with legacy aliases (high, medium, low) resolved.
+//
+// Parameters:
+//   - q: A quality name, e.g. "720p", "Source", "low".
+//
+// Returns:
+//   - The canonical name, e.g. "720", "source", "240".
+func NormalizeQuality(q string) string {
+	q = strings.ToLower(strings.TrimSpace(q))
+	if alias, ok := qualityAliases[q]; ok {
+		return alias
+	}
+	if n := strings.TrimSuffix(q, "p"); n != q {
+		if _, err := strconv.Atoi(n); err == nil {
+			return n
+		}
+	}
+	return q
+}
+
+// qualityRank orders variants for fallback: source first, then by height
+// descending, then anything else.
+func qualityRank(q string) int {
+	if q == QualitySource {
+		return 1 << 30
+	}
+	if n, err := strconv.Atoi(q); err == nil {
+		return n
+	}
+	return -1
+}
+
+// SortVariants orders variants best first: source, then by height.
+//
+// Parameters:
+//   - variants: The variants to sort in place.
+func SortVariants(variants []MediaVariant) {
+	slices.SortStableFunc(variants, func(a, b MediaVariant) int {
+		if d := qualityRank(b.Quality) - qualityRank(a.Quality); d != 0 {
+			return d
+		}
+		return strings.Compare(a.Quality, b.Quality)
+	})
+}
+
+// Variant returns the variant with the given quality.
+//
+// Parameters:
+//   - quality: The quality name (normalized before lookup).
+//
+// Returns:
+//   - The variant, and whether it exists.
+func (m *Media) Variant(quality string) (MediaVariant, bool) {
+	quality = NormalizeQuality(quality)
+	for _, v := range m.Variants {
+		if v.Quality == quality {
+			return v, true
+		}
+	}
+	return MediaVariant{}, false
+}
+
+// SelectQuality picks the first variant in prefs that the media has,
+// falling back to the best available one, and points RawURL and Size at
+// it. Media without variants (DRM content) are left unchanged.
+//
+// Parameters:
+//   - prefs: Quality names in order of preference.
+//
+// Returns:
+//   - The selected quality, or "" if the media has no variants.
+func (m *Media) SelectQuality(prefs []string) string {
+	if len(m.Variants) == 0 {
+		return m.SelectedQuality
+	}
+
+	chosen := m.Variants[0]
+	for _, p := range prefs {
+		if v, ok := m.Variant(p); ok {
+			chosen = v
+			break
+		}
+	}
+
+	m.RawURL = chosen.URL
+	m.Size = chosen.Size
+	m.SelectedQuality = chosen.Quality
+	return chosen.Quality
+}
@@ -1,7 +1,8 @@
 // =============================================================================
 // FILE: internal/paths/db.go
 // PURPOSE: Database path resolution. Provides functions for locating the
-//          per-model SQLite database files based on the current profile and
+//          per-model SQLite database files, or the consolidated database the
+//          metadata template selects, based on the current profile and
 //          save location settings. Ports Python utils/paths/db.py.
 // =============================================================================
 
@@ -12,15 +13,40 @@ import (
 	"strings"
 
 	"gofscraper/internal/config"
+	"gofscraper/internal/model"
 )
 
 // ---------------------------------------------------------------------------
 // Database paths
 // ---------------------------------------------------------------------------
 
+// dbFileName is the name of a model's database file, and of the
+// consolidated database when the metadata template names a directory.
+const dbFileName = "user_data.db"
+
+// ModelDir returns a model's directory under the save location.
+//
+// Parameters:
+//   - modelUsername: The OF model username.
+//
+// Returns:
+//   - Absolute path to the model's directory.
+func ModelDir(modelUsername string) string {
+	return filepath.Join(SaveLocation(), sanitizeComponent(modelUsername))
+}
+
+// SaveLocation returns the configured save location.
+//
+// Returns:
+//   - The save location path.
+func SaveLocation() string {
+	return config.GetSaveLocation()
+}
+
 // DBDir returns the directory where the database for a given model is stored.
 // This follows the same directory structure as the download location, but
-// with the DB inside the model's directory.
+// with the DB inside the model's directory; with a consolidated database it
+// is the directory of the shared file.
 //
 // Parameters:
 //   - modelUsername: The OF model username.
@@ -28,11 +54,12 @@ import (
 // Returns:
 //   - Absolute path to the model's database directory.
 func DBDir(modelUsername string) string {
-	saveLocation := config.GetSaveLocation()
-	return filepath.Join(saveLocation, sanitizeComponent(modelUsername))
+	return filepath.Dir(DBPath(modelUsername))
 }
 
-// DBPath returns the full path to the SQLite database file for a model.
+// DBPath returns the full path to the SQLite database file for a model:
+// the consolidated database when the metadata template selects one, or
+// the model's own file.
 //
 // Parameters:
 //   - modelUsername: The OF model username.
@@ -40,7 +67,78 @@ func DBDir(modelUsername string) string {
 // Returns:
 //   - Absolute path to the .db file.
 func DBPath(modelUsername string) string {
-	return filepath.Join(DBDir(modelUsername), "user_data.db")
+	if path, ok := ConsolidatedDBPath(); ok {
+		return path
+	}
+	return ModelDBPath(modelUsername)
+}
+
+// ModelDBPath returns the path of a model's own database file, whichever
+// layout is configured.
+//
+// Parameters:
+//   - modelUsername: The OF model username.
+//
+// Returns:
+//   - Absolute path to the model's .db file.
+func ModelDBPath(modelUsername string) string {
+	return filepath.Join(ModelDir(modelUsername), dbFileName)
+}
+
+// modelPlaceholders are the metadata template variables that differ
+// between models.
+var modelPlaceholders = []string{
+	model.VarModelID,
+	model.VarModelUsername,
+	model.VarUsername,
+	model.VarFirstLetter,
+}
+
+// ConsolidatedDBPath returns the path of the database shared by every
+// model. The metadata template selects it by naming no per-model
+// placeholder; a template that resolves to a directory gets user_data.db
+// inside it.
+//
+// Returns:
+//   - The absolute path and true, or "" and false for the per-model
+//     layout.
+func ConsolidatedDBPath() (string, bool) {
+	format := config.GetMetadata()
+	for _, v := range modelPlaceholders {
+		if strings.Contains(format, "{"+v+"}") {
+			return "", false
+		}
+	}
+
+	path := model.NewDatabasePlaceholder().DatabasePath(format, config.ConfigDirPath(), config.GetMainProfile(), 0, "")
+	if rest, ok := strings.CutPrefix(path, "{home}"); ok {
+		path = "~" + rest
+	}
+	path = ExpandHome(path)
+	if filepath.Ext(path) != ".db" {
+		path = filepath.Join(path, dbFileName)
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return path, true
+}
+
+// IsConsolidatedDB reports whether dbPath is the consolidated database.
+//
+// Parameters:
+//   - dbPath: Path to a database file.
+//
+// Returns:
+//   - true if the metadata template selects a consolidated database at
+//     dbPath.
+func IsConsolidatedDB(dbPath string) bool {
+	path, ok := ConsolidatedDBPath()
+	if !ok {
+		return false
+	}
+	abs, err := filepath.Abs(dbPath)
+	return err == nil && abs == path
 }
 
 // BackupDBPath returns the path for a database backup file.
@@ -56,7 +154,8 @@ func BackupDBPath(modelUsername string, suffix string) string {
 }
 
 // AllDBPaths scans the save location for all model directories containing
-// database files.
+// their own database files. Models of a consolidated database are listed
+// by its models table instead.
 //
 // Returns:
 //   - A map of model username to DB file path, and any error.
@@ -64,7 +163,7 @@ func AllDBPaths() (map[string]string, error) {
 	saveLocation := config.GetSaveLocation()
 	result := make(map[string]string)
 
-	entries, err := filepath.Glob(filepath.Join(saveLocation, "*", "user_data.db"))
+	entries, err := filepath.Glob(filepath.Join(saveLocation, "*", dbFileName))
 	if err != nil {
 		return nil, err
 	}
@@ -93,3 +192,43 @@ func sanitizeComponent(s string) string {
 	)
 	return replacer.Replace(s)
 }
+
+// ---------------------------------------------------------------------------
+// Recorded media directories
+// ---------------------------------------------------------------------------
+
+// StoredDir returns the form in which a media file's directory is recorded
+// in the database: relative to the save location when relative_paths is
+// enabled and dir lies under it, otherwise dir unchanged.
+//
+// Parameters:
+//   - dir: The absolute directory of a media file.
+//
+// Returns:
+//   - The directory to record.
+func StoredDir(dir string) string {
+	if !config.GetRelativePaths() || !filepath.IsAbs(dir) {
+		return dir
+	}
+	rel, err := filepath.Rel(config.GetSaveLocation(), dir)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return dir
+	}
+	return rel
+}
+
+// ResolveStoredDir returns the absolute form of a recorded media directory.
+// Relative directories are resolved against the save location; absolute
+// ones are returned unchanged.
+//
+// Parameters:
+//   - dir: The directory as recorded in the database.
+//
+// Returns:
+//   - The absolute directory.
+func ResolveStoredDir(dir string) string {
+	if filepath.IsAbs(dir) {
+		return dir
+	}
+	return filepath.Join(config.GetSaveLocation(), dir)
+}
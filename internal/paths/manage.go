@@ -1,43 +1,78 @@
 // =============================================================================
 // FILE: internal/paths/manage.go
 // PURPOSE: Path management utilities. Provides functions for managing
-//          temporary files, cleanup of incomplete downloads, and directory
-//          listing operations. Ports Python utils/paths/manage.py.
+//          temporary files, cleanup of incomplete downloads, moving files,
+//          and directory listing and pruning. Ports Python
+//          utils/paths/manage.py.
 // =============================================================================
 
 package paths
 
 import (
+	"errors"
 	"fmt"
+	"hash/fnv"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
+
+	"gofscraper/internal/hash"
 )
 
 // ---------------------------------------------------------------------------
 // Temp file management
 // ---------------------------------------------------------------------------
 
-// TempFilePath returns a temporary file path for an in-progress download.
-// The temp file is placed in the gofscraper temp directory with a .part suffix.
+// TempFilePath returns the staging path for an in-progress download of
+// finalPath. The name is prefixed with a hash of finalPath so files with the
+// same base name from different directories do not collide. Callers append
+// their own suffix (e.g. ".part").
 //
 // Parameters:
+//   - tempDir: The staging directory; empty uses TempDir().
 //   - finalPath: The intended final file path.
 //
 // Returns:
 //   - The temporary file path.
-func TempFilePath(finalPath string) string {
-	name := filepath.Base(finalPath) + ".part"
-	return filepath.Join(TempDir(), name)
+func TempFilePath(tempDir, finalPath string) string {
+	if tempDir == "" {
+		tempDir = TempDir()
+	}
+	h := fnv.New64a()
+	h.Write([]byte(filepath.Clean(finalPath)))
+	return filepath.Join(tempDir, fmt.Sprintf("%016x_%s", h.Sum64(), filepath.Base(finalPath)))
+}
+
+// isTempFileName reports whether name was produced by TempFilePath.
+func isTempFileName(name string) bool {
+	prefix, _, ok := strings.Cut(name, "_")
+	if !ok || len(prefix) != 16 {
+		return false
+	}
+	for _, c := range prefix {
+		if !strings.ContainsRune("0123456789abcdef", c) {
+			return false
+		}
+	}
+	return true
 }
 
-// CleanupTempFiles removes .part files from the temp directory that are older
-// than the current session. Called during startup.
+// CleanupTempFiles removes staged download files from a temp directory that
+// have not been modified for maxAge. Only files named by TempFilePath are
+// touched. Called during startup.
+//
+// Parameters:
+//   - tempDir: The staging directory; empty uses TempDir().
+//   - maxAge: Minimum age of a file to remove.
 //
 // Returns:
 //   - Number of files removed, and any error.
-func CleanupTempFiles() (int, error) {
-	tempDir := TempDir()
+func CleanupTempFiles(tempDir string, maxAge time.Duration) (int, error) {
+	if tempDir == "" {
+		tempDir = TempDir()
+	}
 	if !Exists(tempDir) {
 		return 0, nil
 	}
@@ -47,43 +82,114 @@ func CleanupTempFiles() (int, error) {
 		return 0, fmt.Errorf("failed to read temp dir: %w", err)
 	}
 
+	cutoff := time.Now().Add(-maxAge)
 	removed := 0
 	for _, entry := range entries {
-		if entry.IsDir() {
+		if entry.IsDir() || !isTempFileName(entry.Name()) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || info.ModTime().After(cutoff) {
 			continue
 		}
-		if strings.HasSuffix(entry.Name(), ".part") {
-			path := filepath.Join(tempDir, entry.Name())
-			if err := os.Remove(path); err == nil {
-				removed++
-			}
+		if err := os.Remove(filepath.Join(tempDir, entry.Name())); err == nil {
+			removed++
 		}
 	}
 
 	return removed, nil
 }
 
-// PromoteTemp moves a temporary file to its final path. Creates parent
-// directories if needed.
+// PromoteTemp moves a temporary file to its final path with MoveFile.
 //
 // Parameters:
 //   - tempPath: Path to the temp file.
 //   - finalPath: Desired final location.
 //
 // Returns:
-//   - Error if the move fails.
+//   - Error if the move fails; tempPath is kept in that case.
 func PromoteTemp(tempPath, finalPath string) error {
-	if err := EnsureParentDir(finalPath); err != nil {
-		return fmt.Errorf("failed to create directory for %s: %w", finalPath, err)
-	}
+	return MoveFile(tempPath, finalPath)
+}
 
-	if err := os.Rename(tempPath, finalPath); err != nil {
-		return fmt.Errorf("failed to move %s to %s: %w", tempPath, finalPath, err)
+// MoveFile moves a file, creating parent directories if needed. A rename
+// is used when possible; across filesystems the file is copied beside dst,
+// verified against the source's size and hash, renamed into place, and the
+// source removed.
+//
+// Parameters:
+//   - src: Path to the file to move.
+//   - dst: Destination path.
+//
+// Returns:
+//   - Error if the move fails; src is kept in that case.
+func MoveFile(src, dst string) error {
+	if err := EnsureParentDir(dst); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", dst, err)
 	}
 
+	renameErr := rename(src, dst)
+	if renameErr == nil {
+		return nil
+	}
+	if err := copyVerified(src, dst); err != nil {
+		return fmt.Errorf("failed to move %s to %s: %w", src, dst, errors.Join(renameErr, err))
+	}
+	if err := os.Remove(src); err != nil {
+		return fmt.Errorf("failed to remove %s after copy: %w", src, err)
+	}
 	return nil
 }
 
+// rename moves a file within a filesystem. Tests replace it to simulate a
+// move across filesystems.
+var rename = os.Rename
+
+// copyVerified copies src to dst through a temporary sibling of dst, which
+// is re-read and compared with src before it replaces dst.
+func copyVerified(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	tmp := dst + ".promote"
+	out, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp)
+
+	srcHash := hash.New()
+	n, err := io.Copy(io.MultiWriter(out, srcHash), in)
+	if err == nil {
+		err = out.Sync()
+	}
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("copy: %w", err)
+	}
+
+	info, err := os.Stat(tmp)
+	if err != nil {
+		return err
+	}
+	if info.Size() != n {
+		return fmt.Errorf("copy verification failed: wrote %d bytes, found %d", n, info.Size())
+	}
+	dstHash, err := hash.File(tmp)
+	if err != nil {
+		return err
+	}
+	if dstHash != srcHash.Sum() {
+		return fmt.Errorf("copy verification failed: hash mismatch")
+	}
+	return os.Rename(tmp, dst)
+}
+
 // ---------------------------------------------------------------------------
 // Directory listing
 // ---------------------------------------------------------------------------
@@ -131,3 +237,28 @@ func ListDirs(dir string) ([]string, error) {
 	}
 	return dirs, nil
 }
+
+// PruneEmptyDirs removes dir if it is empty, then each parent that becomes
+// empty, stopping at (and never removing) stop.
+//
+// Parameters:
+//   - dir: The directory to start from.
+//   - stop: An ancestor of dir that is kept.
+//
+// Returns:
+//   - Number of directories removed.
+func PruneEmptyDirs(dir, stop string) int {
+	stop = filepath.Clean(stop)
+	removed := 0
+	for dir = filepath.Clean(dir); dir != stop && isSubPath(stop, dir); dir = filepath.Dir(dir) {
+		entries, err := os.ReadDir(dir)
+		if err != nil || len(entries) > 0 {
+			break
+		}
+		if err := os.Remove(dir); err != nil {
+			break
+		}
+		removed++
+	}
+	return removed
+}
@@ -0,0 +1,103 @@
+// =============================================================================
+// FILE: internal/paths/manage_test.go
+// PURPOSE: Tests for file moves: a move across filesystems copies and
+//          verifies the file before removing the source, and a failed copy
+//          keeps the source and leaves no partial destination.
+// =============================================================================
+
+package paths
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+// crossDevice makes MoveFile's rename fail as it does across filesystems.
+func crossDevice(t *testing.T) {
+	t.Helper()
+	rename = func(src, dst string) error {
+		return &os.LinkError{Op: "rename", Old: src, New: dst, Err: syscall.EXDEV}
+	}
+	t.Cleanup(func() { rename = os.Rename })
+}
+
+func TestMoveFile(t *testing.T) {
+	body := bytes.Repeat([]byte("0123456789abcdef"), 4096)
+
+	tests := []struct {
+		name        string
+		crossDevice bool
+		existing    bool // dst already holds an older file
+	}{
+		{name: "same filesystem"},
+		{name: "across filesystems", crossDevice: true},
+		{name: "across filesystems replaces dst", crossDevice: true, existing: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.crossDevice {
+				crossDevice(t)
+			}
+			dir := t.TempDir()
+			src := filepath.Join(dir, "temp", "video.mp4.part")
+			dst := filepath.Join(dir, "out", "model", "video.mp4")
+			if err := os.MkdirAll(filepath.Dir(src), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(src, body, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if tt.existing {
+				if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(dst, []byte("old"), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := PromoteTemp(src, dst); err != nil {
+				t.Fatalf("PromoteTemp: %v", err)
+			}
+
+			got, err := os.ReadFile(dst)
+			if err != nil || !bytes.Equal(got, body) {
+				t.Errorf("dst differs from the source (err %v)", err)
+			}
+			if _, err := os.Stat(src); !os.IsNotExist(err) {
+				t.Errorf("src still exists after the move (err %v)", err)
+			}
+			if _, err := os.Stat(dst + ".promote"); !os.IsNotExist(err) {
+				t.Errorf("copy temp %s left behind (err %v)", dst+".promote", err)
+			}
+		})
+	}
+}
+
+func TestMoveFileFailedCopyKeepsSource(t *testing.T) {
+	crossDevice(t)
+	dir := t.TempDir()
+	src := filepath.Join(dir, "video.mp4.part")
+	dst := filepath.Join(dir, "out", "video.mp4")
+	if err := os.WriteFile(src, []byte("content"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// A directory where the copy temp goes makes the copy fail.
+	if err := os.MkdirAll(dst+".promote", 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := MoveFile(src, dst); err == nil {
+		t.Fatal("MoveFile succeeded, want the copy to fail")
+	}
+	if got, err := os.ReadFile(src); err != nil || string(got) != "content" {
+		t.Errorf("src = %q (err %v), want it kept", got, err)
+	}
+	if _, err := os.Stat(dst); !os.IsNotExist(err) {
+		t.Errorf("dst exists after a failed move (err %v)", err)
+	}
+}
@@ -55,6 +55,14 @@ func CacheDir() string {
 	return filepath.Join(ConfigDir(), "cache")
 }
 
+// DaemonStatePath returns the file where daemon mode persists its schedule.
+//
+// Returns:
+//   - Absolute path to the daemon state file.
+func DaemonStatePath() string {
+	return filepath.Join(ConfigDir(), "daemon_state.json")
+}
+
 // ---------------------------------------------------------------------------
 // Path resolution
 // ---------------------------------------------------------------------------
@@ -0,0 +1,146 @@
+// =============================================================================
+// FILE: internal/tui/filters.go
+// PURPOSE: Live table filtering. Binds the sidebar filter fields (date, price,
+//          media type, download state, text search) to table rows.
+//          Ports Python classes/table/utils/filter.py.
+// =============================================================================
+
+package tui
+
+import (
+	"strconv"
+	"strings"
+
+	"gofscraper/internal/tui/fields"
+	"gofscraper/internal/tui/sections"
+)
+
+// ---------------------------------------------------------------------------
+// Row keys
+// ---------------------------------------------------------------------------
+
+// Row keys read by the table filters. Rows may carry keys that are not shown
+// as columns (e.g. KeyMediaTypes).
+const (
+	KeyID         = "id"
+	KeyDate       = "date"        // "2006-01-02 15:04:05"
+	KeyPrice      = "price"       // "$N.NN"
+	KeyMedia      = "media"       // Media item count
+	KeyMediaTypes = "media_types" // Comma-separated: images,videos,audios
+	KeyDownloaded = "downloaded"  // Downloaded media item count
+	KeyText       = "text"
+)
+
+// ---------------------------------------------------------------------------
+// TableFilters
+// ---------------------------------------------------------------------------
+
+// TableFilters holds the sidebar filter fields for a triage table.
+type TableFilters struct {
+	Date     *fields.DateField
+	Price    *fields.PriceField
+	Media    *fields.MediaField
+	Download *fields.DownloadField
+	Text     *fields.TextSearchField
+}
+
+// NewTableFilters creates the default set of table filter fields.
+func NewTableFilters() *TableFilters {
+	return &TableFilters{
+		Date:     fields.NewDateField("Date"),
+		Price:    fields.NewPriceField("Price"),
+		Media:    fields.NewMediaField("Media"),
+		Download: fields.NewDownloadField("Downloaded"),
+		Text:     fields.NewTextSearchField("Text"),
+	}
+}
+
+// Fields returns the filter fields in sidebar order.
+func (f *TableFilters) Fields() []sections.Field {
+	return []sections.Field{f.Date, f.Price, f.Media, f.Download, f.Text}
+}
+
+// Apply returns the rows that pass every active filter.
+func (f *TableFilters) Apply(rows []sections.Row) []sections.Row {
+	result := make([]sections.Row, 0, len(rows))
+	for _, row := range rows {
+		if f.match(row) {
+			result = append(result, row)
+		}
+	}
+	return result
+}
+
+// match reports whether a single row passes every active filter.
+func (f *TableFilters) match(row sections.Row) bool {
+	// Date: compare the YYYY-MM-DD prefix lexically.
+	day := row[KeyDate]
+	if len(day) > len(fields.DateLayout) {
+		day = day[:len(fields.DateLayout)]
+	}
+	if after := f.Date.After(); after != "" && day < after {
+		return false
+	}
+	if before := f.Date.Before(); before != "" && day > before {
+		return false
+	}
+
+	// Price.
+	price, _ := strconv.ParseFloat(strings.TrimPrefix(row[KeyPrice], "$"), 64)
+	if min := f.Price.Min(); min != nil && price < *min {
+		return false
+	}
+	if max := f.Price.Max(); max != nil && price > *max {
+		return false
+	}
+
+	// Media type.
+	if want := mediaTypeName(f.Media.Filter()); want != "" {
+		if !strings.Contains(","+row[KeyMediaTypes]+",", ","+want+",") {
+			return false
+		}
+	}
+
+	// Download state: downloaded means every media item is downloaded.
+	total, _ := strconv.Atoi(row[KeyMedia])
+	done, _ := strconv.Atoi(row[KeyDownloaded])
+	complete := total > 0 && done >= total
+	switch f.Download.Filter() {
+	case fields.DownloadCompleted:
+		if !complete {
+			return false
+		}
+	case fields.DownloadNotCompleted:
+		if complete {
+			return false
+		}
+	}
+
+	// Text search.
+	if q := f.Text.Query(); q != "" {
+		text := row[KeyText]
+		if f.Text.Mode() == fields.TextSearchRegex {
+			if re := f.Text.Pattern(); re != nil && !re.MatchString(text) {
+				return false
+			}
+		} else if !strings.Contains(strings.ToLower(text), strings.ToLower(q)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// mediaTypeName maps a media filter to the media type names used in rows.
+func mediaTypeName(filter fields.MediaFilter) string {
+	switch filter {
+	case fields.MediaImages:
+		return "images"
+	case fields.MediaVideos:
+		return "videos"
+	case fields.MediaAudio:
+		return "audios"
+	default:
+		return ""
+	}
+}
@@ -9,6 +9,7 @@ package sections
 import (
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -53,6 +54,8 @@ type TableSection struct {
 	sortOrder SortOrder
 	width     int
 	height    int
+	keyCol    string          // Column whose value identifies a row for marking
+	marked    map[string]bool // Marked row keys; survives SetRows
 }
 
 // NewTableSection creates a new TableSection with the given columns.
@@ -60,6 +63,8 @@ func NewTableSection(columns []Column) *TableSection {
 	return &TableSection{
 		columns:  columns,
 		pageSize: 20,
+		keyCol:   "id",
+		marked:   make(map[string]bool),
 	}
 }
 
@@ -69,11 +74,15 @@ func (t *TableSection) SetSize(width, height int) {
 	t.height = height
 }
 
-// SetRows replaces the table data. Resets cursor and page.
+// SetRows replaces the table data, keeping the current sort and marks.
+// Resets cursor and page.
 func (t *TableSection) SetRows(rows []Row) {
 	t.rows = rows
 	t.cursor = 0
 	t.page = 0
+	if t.sortCol != "" && t.sortOrder != SortNone {
+		t.sortRows()
+	}
 }
 
 // SetPageSize sets the number of rows per page.
@@ -88,6 +97,11 @@ func (t *TableSection) Rows() []Row {
 	return t.rows
 }
 
+// Columns returns the table's column definitions.
+func (t *TableSection) Columns() []Column {
+	return t.columns
+}
+
 // SelectedRow returns the currently selected row, or nil.
 func (t *TableSection) SelectedRow() Row {
 	visible := t.visibleRows()
@@ -97,6 +111,53 @@ func (t *TableSection) SelectedRow() Row {
 	return visible[t.cursor]
 }
 
+// ---------------------------------------------------------------------------
+// Marking
+// ---------------------------------------------------------------------------
+
+// SetKeyColumn sets the column whose value identifies rows for marking.
+// Defaults to "id".
+func (t *TableSection) SetKeyColumn(key string) {
+	t.keyCol = key
+}
+
+// ToggleMark marks or unmarks the selected row.
+func (t *TableSection) ToggleMark() {
+	row := t.SelectedRow()
+	if row == nil {
+		return
+	}
+	key := row[t.keyCol]
+	if t.marked[key] {
+		delete(t.marked, key)
+	} else {
+		t.marked[key] = true
+	}
+}
+
+// MarkAll marks every row currently in the table.
+func (t *TableSection) MarkAll() {
+	for _, row := range t.rows {
+		t.marked[row[t.keyCol]] = true
+	}
+}
+
+// ClearMarks unmarks all rows.
+func (t *TableSection) ClearMarks() {
+	t.marked = make(map[string]bool)
+}
+
+// IsMarked reports whether the given row is marked.
+func (t *TableSection) IsMarked(row Row) bool {
+	return t.marked[row[t.keyCol]]
+}
+
+// MarkedCount returns the number of marked rows, including rows that are
+// currently filtered out of the table.
+func (t *TableSection) MarkedCount() int {
+	return len(t.marked)
+}
+
 // TotalPages returns the total number of pages.
 func (t *TableSection) TotalPages() int {
 	if len(t.rows) == 0 {
@@ -127,7 +188,8 @@ func (t *TableSection) visibleRows() []Row {
 	return t.rows[start:end]
 }
 
-// Sort sorts the rows by the given column key.
+// Sort sorts the rows by the given column key. Sorting the same column again
+// cycles through ascending, descending, and unsorted.
 func (t *TableSection) Sort(colKey string) {
 	if t.sortCol == colKey {
 		// Toggle sort order.
@@ -146,20 +208,54 @@ func (t *TableSection) Sort(colKey string) {
 		t.sortOrder = SortAsc
 	}
 
+	t.sortRows()
+}
+
+// SortBy sorts the rows by the given column key in an explicit order.
+func (t *TableSection) SortBy(colKey string, order SortOrder) {
+	t.sortCol = colKey
+	t.sortOrder = order
+	if order == SortNone {
+		t.sortCol = ""
+		return
+	}
+	t.sortRows()
+}
+
+// sortRows applies the current sort column and order to the rows.
+func (t *TableSection) sortRows() {
+	colKey := t.sortCol
 	order := t.sortOrder
 	sort.SliceStable(t.rows, func(i, j int) bool {
-		a := t.rows[i][colKey]
-		b := t.rows[j][colKey]
+		c := compareCells(t.rows[i][colKey], t.rows[j][colKey])
 		if order == SortDesc {
-			return a > b
+			return c > 0
 		}
-		return a < b
+		return c < 0
 	})
 
 	t.cursor = 0
 	t.page = 0
 }
 
+// compareCells compares two cell values numerically when both parse as
+// numbers (ignoring a leading "$"), and as strings otherwise.
+func compareCells(a, b string) int {
+	af, aErr := strconv.ParseFloat(strings.TrimPrefix(a, "$"), 64)
+	bf, bErr := strconv.ParseFloat(strings.TrimPrefix(b, "$"), 64)
+	if aErr == nil && bErr == nil {
+		switch {
+		case af < bf:
+			return -1
+		case af > bf:
+			return 1
+		default:
+			return 0
+		}
+	}
+	return strings.Compare(a, b)
+}
+
 // Update handles input events for the table.
 func (t *TableSection) Update(msg tea.Msg) tea.Cmd {
 	switch msg := msg.(type) {
@@ -190,6 +286,11 @@ func (t *TableSection) Update(msg tea.Msg) tea.Cmd {
 		case "end":
 			t.page = t.TotalPages() - 1
 			t.cursor = 0
+		case " ", "x":
+			t.ToggleMark()
+			if visible := t.visibleRows(); t.cursor < len(visible)-1 {
+				t.cursor++
+			}
 		}
 	}
 	return nil
@@ -225,10 +326,10 @@ func (t *TableSection) View() string {
 		}
 		headerCells = append(headerCells, padRight(title, col.Width))
 	}
-	header := headerStyle.Render(strings.Join(headerCells, " | "))
+	header := headerStyle.Render("  " + strings.Join(headerCells, " | "))
 
 	// Separator.
-	sepWidth := 0
+	sepWidth := 2 // mark gutter
 	for _, col := range t.columns {
 		sepWidth += col.Width
 	}
@@ -245,7 +346,11 @@ func (t *TableSection) View() string {
 		for _, col := range t.columns {
 			cells = append(cells, padRight(row[col.Key], col.Width))
 		}
-		line := strings.Join(cells, " | ")
+		gutter := "  "
+		if t.IsMarked(row) {
+			gutter = "* "
+		}
+		line := gutter + strings.Join(cells, " | ")
 		if i == t.cursor {
 			line = selectedStyle.Render(line)
 		} else {
@@ -259,13 +364,17 @@ func (t *TableSection) View() string {
 		Foreground(lipgloss.Color("#6B7280")).
 		MarginTop(1)
 	footer := footerStyle.Render(
-		fmt.Sprintf("Page %d/%d  |  %d rows  |  h/l: page  j/k: navigate",
-			t.page+1, t.TotalPages(), len(t.rows)))
+		fmt.Sprintf("Page %d/%d  |  %d rows  |  %d marked  |  h/l: page  j/k: navigate  space: mark",
+			t.page+1, t.TotalPages(), len(t.rows), len(t.marked)))
 
 	content := header + "\n" + separator + "\n"
 	content += strings.Join(rowLines, "\n")
 	content += "\n" + footer
 
+	// Clip wide tables to the available width instead of wrapping.
+	if t.width > 0 {
+		content = lipgloss.NewStyle().MaxWidth(t.width).Render(content)
+	}
 	return content
 }
 
@@ -0,0 +1,412 @@
+// =============================================================================
+// FILE: internal/tui/table.go
+// PURPOSE: Interactive triage table. Shows check results with a filter
+//          sidebar, lets the user mark rows, and queues the marked rows for
+//          download without leaving the TUI. Ports Python classes/table/app.py
+//          table screen behaviour.
+// =============================================================================
+
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dustin/go-humanize"
+
+	"gofscraper/internal/tui/fields"
+	"gofscraper/internal/tui/inputs"
+	"gofscraper/internal/tui/sections"
+)
+
+// ---------------------------------------------------------------------------
+// Types
+// ---------------------------------------------------------------------------
+
+// DownloadFunc downloads the media behind the given rows. It returns a
+// short summary for the status bar and per-row cell updates keyed by row ID
+// (e.g. a new "downloaded" count), which are applied once it completes.
+// It runs outside the UI loop and must not modify the rows it is given.
+type DownloadFunc func(ctx context.Context, rows []sections.Row) (summary string, updates map[string]sections.Row, err error)
+
+// BandwidthControl adjusts the live download bandwidth limit.
+type BandwidthControl interface {
+	Limit() int64
+	SetOverride(limit int64)
+	ClearOverride()
+}
+
+// limitStep is how much "+" and "-" change the bandwidth limit.
+const limitStep = 512 * 1024
+
+// tableFocus identifies which pane receives key input.
+type tableFocus int
+
+const (
+	focusTable tableFocus = iota
+	focusSidebar
+)
+
+// downloadDoneMsg is sent when a queued download finishes.
+type downloadDoneMsg struct {
+	summary string
+	updates map[string]sections.Row
+	err     error
+}
+
+// ---------------------------------------------------------------------------
+// Construction
+// ---------------------------------------------------------------------------
+
+// NewTableApp creates a TUI App opened on the triage table view.
+//
+// Parameters:
+//   - ctx: Context passed to download.
+//   - title: Heading shown above the table.
+//   - columns: Table columns.
+//   - rows: Table rows; each must have a unique KeyID value.
+//   - download: Called with the marked rows when the user presses "d".
+//     May be nil to disable downloading.
+//   - limit: Bandwidth limiter adjusted with "+", "-" and "0". May be nil.
+func NewTableApp(ctx context.Context, title string, columns []sections.Column, rows []sections.Row, download DownloadFunc, limit BandwidthControl) App {
+	filters := NewTableFilters()
+	table := sections.NewTableSection(columns)
+	table.SetKeyColumn(KeyID)
+	table.SetRows(rows)
+	input := inputs.NewStringInput("Value", "")
+
+	return App{
+		view:     ViewTable,
+		ctx:      ctx,
+		title:    title,
+		table:    table,
+		sidebar:  sections.NewSidebar(filters.Fields()),
+		filters:  filters,
+		allRows:  rows,
+		focus:    focusTable,
+		input:    &input,
+		download: download,
+		limit:    limit,
+	}
+}
+
+// RunTable runs the triage table until the user quits.
+func RunTable(ctx context.Context, title string, columns []sections.Column, rows []sections.Row, download DownloadFunc, limit BandwidthControl) error {
+	p := tea.NewProgram(NewTableApp(ctx, title, columns, rows, download, limit), tea.WithAltScreen())
+	_, err := p.Run()
+	return err
+}
+
+// ---------------------------------------------------------------------------
+// Update
+// ---------------------------------------------------------------------------
+
+// updateTable handles key input while the table view is active.
+func (a App) updateTable(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	if key == "ctrl+c" {
+		a.quitting = true
+		return a, tea.Quit
+	}
+
+	if a.sidebar.Editing() {
+		return a.updateEditing(msg)
+	}
+
+	switch key {
+	case "q":
+		a.quitting = true
+		return a, tea.Quit
+	case "tab":
+		if a.focus == focusTable {
+			a.focus = focusSidebar
+		} else {
+			a.focus = focusTable
+		}
+		return a, nil
+	}
+
+	if a.focus == focusSidebar {
+		return a.updateSidebar(msg)
+	}
+
+	switch key {
+	case "a":
+		a.table.MarkAll()
+	case "c":
+		a.table.ClearMarks()
+	case "d":
+		return a.startDownload()
+	case "+", "=":
+		a = a.adjustLimit(limitStep)
+	case "-":
+		a = a.adjustLimit(-limitStep)
+	case "0":
+		if a.limit != nil {
+			a.limit.ClearOverride()
+			a.status = "bandwidth limit reset to config: " + formatLimit(a.limit.Limit())
+		}
+	case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		idx, _ := strconv.Atoi(key)
+		cols := a.columns()
+		if idx <= len(cols) {
+			a.table.Sort(cols[idx-1].Key)
+		}
+	default:
+		a.table.Update(msg)
+	}
+	return a, nil
+}
+
+// updateSidebar handles navigation keys while the sidebar has focus.
+func (a App) updateSidebar(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		switch f := a.sidebar.SelectedField().(type) {
+		case *fields.MediaField:
+			f.Cycle()
+			a.refilter()
+			return a, nil
+		case *fields.DownloadField:
+			f.Cycle()
+			a.refilter()
+			return a, nil
+		}
+		a.sidebar.Update(msg)
+		a.input.SetValue(a.editValue())
+		return a, a.input.Focus()
+	case "r", "R":
+		a.sidebar.Update(msg)
+		a.refilter()
+		return a, nil
+	default:
+		return a, a.sidebar.Update(msg)
+	}
+}
+
+// updateEditing handles key input while a sidebar field is being edited.
+// Enter applies the value, tab switches the field's sub-input, esc cancels.
+func (a App) updateEditing(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		a.stopEditing()
+		return a, nil
+	case "enter":
+		if err := a.applyEditValue(a.input.Value()); err != nil {
+			a.status = err.Error()
+			return a, nil
+		}
+		a.status = ""
+		a.stopEditing()
+		a.refilter()
+		return a, nil
+	case "tab":
+		switch f := a.sidebar.SelectedField().(type) {
+		case *fields.DateField:
+			f.ToggleFocus()
+		case *fields.PriceField:
+			f.ToggleFocus()
+		case *fields.TextSearchField:
+			f.ToggleMode()
+			a.refilter()
+		}
+		a.input.SetValue(a.editValue())
+		return a, nil
+	default:
+		return a, a.input.Update(msg)
+	}
+}
+
+// stopEditing leaves field editing mode.
+func (a App) stopEditing() {
+	a.sidebar.SetEditing(false)
+	a.input.Blur()
+	a.input.Reset()
+}
+
+// editValue returns the current value of the field sub-input being edited.
+func (a App) editValue() string {
+	switch f := a.sidebar.SelectedField().(type) {
+	case *fields.DateField:
+		if f.Focus() == fields.DateFocusAfter {
+			return f.After()
+		}
+		return f.Before()
+	case *fields.PriceField:
+		var v *float64
+		if f.Focus() == fields.PriceFocusMin {
+			v = f.Min()
+		} else {
+			v = f.Max()
+		}
+		if v == nil {
+			return ""
+		}
+		return strconv.FormatFloat(*v, 'f', 2, 64)
+	case *fields.TextSearchField:
+		return f.Query()
+	}
+	return ""
+}
+
+// applyEditValue stores an edited value into the selected field.
+func (a App) applyEditValue(v string) error {
+	switch f := a.sidebar.SelectedField().(type) {
+	case *fields.DateField:
+		if f.Focus() == fields.DateFocusAfter {
+			return f.SetAfter(v)
+		}
+		return f.SetBefore(v)
+	case *fields.PriceField:
+		if f.Focus() == fields.PriceFocusMin {
+			return f.SetMin(v)
+		}
+		return f.SetMax(v)
+	case *fields.TextSearchField:
+		f.SetQuery(v)
+		return f.Err()
+	}
+	return nil
+}
+
+// refilter re-applies the sidebar filters to all rows.
+func (a App) refilter() {
+	a.table.SetRows(a.filters.Apply(a.allRows))
+}
+
+// resizeTable fits the sidebar and table to the window size.
+func (a App) resizeTable() {
+	a.sidebar.SetSize(SidebarWidth-2, a.height-HeaderHeight-FooterHeight-2)
+	a.table.SetSize(a.width-SidebarWidth-1, a.height-HeaderHeight-FooterHeight)
+	if rows := a.height - HeaderHeight - FooterHeight - 5; rows > 0 {
+		a.table.SetPageSize(rows)
+	}
+}
+
+// columns returns the table's columns.
+func (a App) columns() []sections.Column {
+	return a.table.Columns()
+}
+
+// ---------------------------------------------------------------------------
+// Download
+// ---------------------------------------------------------------------------
+
+// startDownload hands the marked rows to the download callback.
+func (a App) startDownload() (tea.Model, tea.Cmd) {
+	if a.download == nil {
+		a.status = "downloading is not available here"
+		return a, nil
+	}
+	if a.busy {
+		a.status = "a download is already running"
+		return a, nil
+	}
+
+	var rows []sections.Row
+	for _, row := range a.allRows {
+		if a.table.IsMarked(row) {
+			cp := make(sections.Row, len(row))
+			for k, v := range row {
+				cp[k] = v
+			}
+			rows = append(rows, cp)
+		}
+	}
+	if len(rows) == 0 {
+		a.status = "no rows marked (space to mark, a to mark all)"
+		return a, nil
+	}
+
+	a.busy = true
+	a.status = fmt.Sprintf("downloading media from %d posts...", len(rows))
+	ctx, download := a.ctx, a.download
+	return a, func() tea.Msg {
+		summary, updates, err := download(ctx, rows)
+		return downloadDoneMsg{summary: summary, updates: updates, err: err}
+	}
+}
+
+// adjustLimit raises or lowers the live bandwidth limit by delta. Starting
+// from unlimited, either direction sets the limit to one step.
+func (a App) adjustLimit(delta int64) App {
+	if a.limit == nil {
+		a.status = "bandwidth limit is not adjustable here"
+		return a
+	}
+	limit := a.limit.Limit()
+	if limit <= 0 {
+		limit = limitStep
+	} else {
+		limit = max(limit+delta, limitStep)
+	}
+	a.limit.SetOverride(limit)
+	a.status = "bandwidth limit: " + formatLimit(limit)
+	return a
+}
+
+// formatLimit renders a bandwidth limit for the status bar.
+func formatLimit(limit int64) string {
+	if limit <= 0 {
+		return "unlimited"
+	}
+	return humanize.IBytes(uint64(limit)) + "/s"
+}
+
+// finishDownload applies a completed download's row updates.
+func (a App) finishDownload(msg downloadDoneMsg) App {
+	a.busy = false
+	for _, row := range a.allRows {
+		if upd, ok := msg.updates[row[KeyID]]; ok {
+			for k, v := range upd {
+				row[k] = v
+			}
+		}
+	}
+	if msg.err != nil {
+		a.status = "download failed: " + msg.err.Error()
+	} else {
+		a.status = msg.summary
+		a.table.ClearMarks()
+	}
+	a.refilter()
+	return a
+}
+
+// ---------------------------------------------------------------------------
+// View
+// ---------------------------------------------------------------------------
+
+// tableView renders the triage table with its filter sidebar.
+func (a App) tableView() string {
+	if a.table == nil {
+		return "Table View\n"
+	}
+
+	header := TitleStyle.Render(a.title)
+
+	sidebar := a.sidebar.View()
+	if a.sidebar.Editing() {
+		sidebar += "\n" + a.input.View()
+	}
+
+	bodyHeight := a.height - HeaderHeight - FooterHeight
+	body := ComposeLayout(sidebar, a.table.View(), a.width, bodyHeight)
+
+	help := "tab: switch pane  space: mark  a: mark all  c: clear  1-9: sort  d: download  +/-/0: limit  q: quit"
+	if a.focus == focusSidebar {
+		help = "tab: switch pane  enter: edit/cycle  (editing: tab: after/before, min/max, regex)  esc: cancel  q: quit"
+	}
+	status := a.status
+	if status == "" {
+		status = fmt.Sprintf("%d of %d rows shown", len(a.table.Rows()), len(a.allRows))
+		if a.limit != nil {
+			status += "  limit: " + formatLimit(a.limit.Limit())
+		}
+	}
+	footer := StatusBarStyle.Render(status) + "\n" + SubtleStyle.Render(help)
+
+	return ComposeVertical(header, body, footer)
+}
@@ -7,9 +7,13 @@
 package tui
 
 import (
+	"context"
 	"fmt"
 
 	tea "github.com/charmbracelet/bubbletea"
+
+	"gofscraper/internal/tui/inputs"
+	"gofscraper/internal/tui/sections"
 )
 
 // ---------------------------------------------------------------------------
@@ -18,12 +22,27 @@ import (
 
 // App is the top-level Bubbletea model for the TUI.
 type App struct {
-	width   int
-	height  int
-	ready   bool
+	width    int
+	height   int
+	ready    bool
 	quitting bool
-	err     error
-	view    View
+	err      error
+	view     View
+
+	// Table (triage) view state. Pointers so the value-receiver model
+	// shares them across updates.
+	ctx      context.Context
+	title    string
+	table    *sections.TableSection
+	sidebar  *sections.Sidebar
+	filters  *TableFilters
+	allRows  []sections.Row
+	focus    tableFocus
+	input    *inputs.StringInput
+	status   string
+	busy     bool
+	download DownloadFunc
+	limit    BandwidthControl
 }
 
 // View represents which screen is currently active.
@@ -51,6 +70,9 @@ func (a App) Init() tea.Cmd {
 func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if a.view == ViewTable {
+			return a.updateTable(msg)
+		}
 		switch msg.String() {
 		case "q", "ctrl+c":
 			a.quitting = true
@@ -61,6 +83,12 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.width = msg.Width
 		a.height = msg.Height
 		a.ready = true
+		if a.view == ViewTable {
+			a.resizeTable()
+		}
+
+	case downloadDoneMsg:
+		return a.finishDownload(msg), nil
 	}
 
 	return a, nil
@@ -97,11 +125,6 @@ func (a App) menuView() string {
 	return "GoFScraper - Main Menu\n\nPress 'q' to quit\n"
 }
 
-// tableView renders the data table.
-func (a App) tableView() string {
-	return "Table View\n"
-}
-
 // progressView renders the download progress.
 func (a App) progressView() string {
 	return "Progress View\n"
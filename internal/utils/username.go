@@ -1,9 +1,10 @@
 // =============================================================================
-// FILE: internal/cli/callbacks/username_parse.go
+// FILE: internal/utils/username.go
 // PURPOSE: Username format parsing. Handles URL-style and plain usernames.
+//          Shared by the CLI flags and the commands that resolve URLs.
 // =============================================================================
 
-package callbacks
+package utils
 
 import (
 	"net/url"